@@ -0,0 +1,689 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: analysis/v1/analysis.proto
+
+// Analysis Service проверяет работы на заимствования и строит облако слов.
+// GetReport и WordCloud выполняются от имени пользователя: шлюз передает его токен
+// в метаданных authorization или API-ключ в x-api-key.
+
+package analysisv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ImageFormat int32
+
+const (
+	ImageFormat_IMAGE_FORMAT_UNSPECIFIED ImageFormat = 0
+	ImageFormat_IMAGE_FORMAT_SVG         ImageFormat = 1
+	ImageFormat_IMAGE_FORMAT_PNG         ImageFormat = 2
+)
+
+// Enum value maps for ImageFormat.
+var (
+	ImageFormat_name = map[int32]string{
+		0: "IMAGE_FORMAT_UNSPECIFIED",
+		1: "IMAGE_FORMAT_SVG",
+		2: "IMAGE_FORMAT_PNG",
+	}
+	ImageFormat_value = map[string]int32{
+		"IMAGE_FORMAT_UNSPECIFIED": 0,
+		"IMAGE_FORMAT_SVG":         1,
+		"IMAGE_FORMAT_PNG":         2,
+	}
+)
+
+func (x ImageFormat) Enum() *ImageFormat {
+	p := new(ImageFormat)
+	*p = x
+	return p
+}
+
+func (x ImageFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ImageFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_analysis_v1_analysis_proto_enumTypes[0].Descriptor()
+}
+
+func (ImageFormat) Type() protoreflect.EnumType {
+	return &file_analysis_v1_analysis_proto_enumTypes[0]
+}
+
+func (x ImageFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ImageFormat.Descriptor instead.
+func (ImageFormat) EnumDescriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{0}
+}
+
+type AnalyzeRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	WorkId       string                 `protobuf:"bytes,1,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	FileId       string                 `protobuf:"bytes,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	AssignmentId string                 `protobuf:"bytes,3,opt,name=assignment_id,json=assignmentId,proto3" json:"assignment_id,omitempty"`
+	Filename     string                 `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`
+	// student_id — автор работы: сервис сохраняет работу, если еще не знает ее, чтобы
+	// отчет, облако слов и поток проверки были доступны по правам на работу.
+	StudentId     string `protobuf:"bytes,5,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeRequest) Reset() {
+	*x = AnalyzeRequest{}
+	mi := &file_analysis_v1_analysis_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeRequest) ProtoMessage() {}
+
+func (x *AnalyzeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_v1_analysis_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeRequest) Descriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AnalyzeRequest) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetAssignmentId() string {
+	if x != nil {
+		return x.AssignmentId
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetStudentId() string {
+	if x != nil {
+		return x.StudentId
+	}
+	return ""
+}
+
+type AnalyzeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Report        *Report                `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeResponse) Reset() {
+	*x = AnalyzeResponse{}
+	mi := &file_analysis_v1_analysis_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeResponse) ProtoMessage() {}
+
+func (x *AnalyzeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_v1_analysis_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzeResponse) Descriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AnalyzeResponse) GetReport() *Report {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+type GetReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkId        string                 `protobuf:"bytes,1,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReportRequest) Reset() {
+	*x = GetReportRequest{}
+	mi := &file_analysis_v1_analysis_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReportRequest) ProtoMessage() {}
+
+func (x *GetReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_v1_analysis_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReportRequest.ProtoReflect.Descriptor instead.
+func (*GetReportRequest) Descriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetReportRequest) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+type GetReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Report        *Report                `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReportResponse) Reset() {
+	*x = GetReportResponse{}
+	mi := &file_analysis_v1_analysis_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReportResponse) ProtoMessage() {}
+
+func (x *GetReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_v1_analysis_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReportResponse.ProtoReflect.Descriptor instead.
+func (*GetReportResponse) Descriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetReportResponse) GetReport() *Report {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+type Report struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WorkId        string                 `protobuf:"bytes,2,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	IsPlagiarized bool                   `protobuf:"varint,3,opt,name=is_plagiarized,json=isPlagiarized,proto3" json:"is_plagiarized,omitempty"`
+	Score         float64                `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	// matched_work_id пуст, если совпадений нет или у вызывающего нет полного доступа.
+	MatchedWorkId string                 `protobuf:"bytes,5,opt,name=matched_work_id,json=matchedWorkId,proto3" json:"matched_work_id,omitempty"`
+	Details       *ReportDetails         `protobuf:"bytes,6,opt,name=details,proto3" json:"details,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// status — clean, flagged или insufficient_content, как в отчете монолита.
+	Status        string `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Report) Reset() {
+	*x = Report{}
+	mi := &file_analysis_v1_analysis_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Report) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Report) ProtoMessage() {}
+
+func (x *Report) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_v1_analysis_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Report.ProtoReflect.Descriptor instead.
+func (*Report) Descriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Report) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Report) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *Report) GetIsPlagiarized() bool {
+	if x != nil {
+		return x.IsPlagiarized
+	}
+	return false
+}
+
+func (x *Report) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Report) GetMatchedWorkId() string {
+	if x != nil {
+		return x.MatchedWorkId
+	}
+	return ""
+}
+
+func (x *Report) GetDetails() *ReportDetails {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *Report) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Report) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ReportDetails struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm     string                 `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	MatchedTokens int32                  `protobuf:"varint,2,opt,name=matched_tokens,json=matchedTokens,proto3" json:"matched_tokens,omitempty"`
+	TotalTokens   int32                  `protobuf:"varint,3,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	Coverage      float64                `protobuf:"fixed64,4,opt,name=coverage,proto3" json:"coverage,omitempty"`
+	// insufficient_content: работа слишком короткая и не оценивалась.
+	InsufficientContent bool `protobuf:"varint,5,opt,name=insufficient_content,json=insufficientContent,proto3" json:"insufficient_content,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ReportDetails) Reset() {
+	*x = ReportDetails{}
+	mi := &file_analysis_v1_analysis_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportDetails) ProtoMessage() {}
+
+func (x *ReportDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_v1_analysis_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportDetails.ProtoReflect.Descriptor instead.
+func (*ReportDetails) Descriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReportDetails) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *ReportDetails) GetMatchedTokens() int32 {
+	if x != nil {
+		return x.MatchedTokens
+	}
+	return 0
+}
+
+func (x *ReportDetails) GetTotalTokens() int32 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+func (x *ReportDetails) GetCoverage() float64 {
+	if x != nil {
+		return x.Coverage
+	}
+	return 0
+}
+
+func (x *ReportDetails) GetInsufficientContent() bool {
+	if x != nil {
+		return x.InsufficientContent
+	}
+	return false
+}
+
+type WordCloudRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	WorkId string                 `protobuf:"bytes,1,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	// По умолчанию SVG.
+	Format        ImageFormat `protobuf:"varint,2,opt,name=format,proto3,enum=analysis.v1.ImageFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WordCloudRequest) Reset() {
+	*x = WordCloudRequest{}
+	mi := &file_analysis_v1_analysis_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WordCloudRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WordCloudRequest) ProtoMessage() {}
+
+func (x *WordCloudRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_v1_analysis_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WordCloudRequest.ProtoReflect.Descriptor instead.
+func (*WordCloudRequest) Descriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WordCloudRequest) GetWorkId() string {
+	if x != nil {
+		return x.WorkId
+	}
+	return ""
+}
+
+func (x *WordCloudRequest) GetFormat() ImageFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ImageFormat_IMAGE_FORMAT_UNSPECIFIED
+}
+
+type WordCloudResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Image         []byte                 `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WordCloudResponse) Reset() {
+	*x = WordCloudResponse{}
+	mi := &file_analysis_v1_analysis_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WordCloudResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WordCloudResponse) ProtoMessage() {}
+
+func (x *WordCloudResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_v1_analysis_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WordCloudResponse.ProtoReflect.Descriptor instead.
+func (*WordCloudResponse) Descriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WordCloudResponse) GetImage() []byte {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *WordCloudResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+var File_analysis_v1_analysis_proto protoreflect.FileDescriptor
+
+const file_analysis_v1_analysis_proto_rawDesc = "" +
+	"\n" +
+	"\x1aanalysis/v1/analysis.proto\x12\vanalysis.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa2\x01\n" +
+	"\x0eAnalyzeRequest\x12\x17\n" +
+	"\awork_id\x18\x01 \x01(\tR\x06workId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\tR\x06fileId\x12#\n" +
+	"\rassignment_id\x18\x03 \x01(\tR\fassignmentId\x12\x1a\n" +
+	"\bfilename\x18\x04 \x01(\tR\bfilename\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x05 \x01(\tR\tstudentId\">\n" +
+	"\x0fAnalyzeResponse\x12+\n" +
+	"\x06report\x18\x01 \x01(\v2\x13.analysis.v1.ReportR\x06report\"+\n" +
+	"\x10GetReportRequest\x12\x17\n" +
+	"\awork_id\x18\x01 \x01(\tR\x06workId\"@\n" +
+	"\x11GetReportResponse\x12+\n" +
+	"\x06report\x18\x01 \x01(\v2\x13.analysis.v1.ReportR\x06report\"\x9f\x02\n" +
+	"\x06Report\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\awork_id\x18\x02 \x01(\tR\x06workId\x12%\n" +
+	"\x0eis_plagiarized\x18\x03 \x01(\bR\risPlagiarized\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\x12&\n" +
+	"\x0fmatched_work_id\x18\x05 \x01(\tR\rmatchedWorkId\x124\n" +
+	"\adetails\x18\x06 \x01(\v2\x1a.analysis.v1.ReportDetailsR\adetails\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\"\xc6\x01\n" +
+	"\rReportDetails\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\tR\talgorithm\x12%\n" +
+	"\x0ematched_tokens\x18\x02 \x01(\x05R\rmatchedTokens\x12!\n" +
+	"\ftotal_tokens\x18\x03 \x01(\x05R\vtotalTokens\x12\x1a\n" +
+	"\bcoverage\x18\x04 \x01(\x01R\bcoverage\x121\n" +
+	"\x14insufficient_content\x18\x05 \x01(\bR\x13insufficientContent\"]\n" +
+	"\x10WordCloudRequest\x12\x17\n" +
+	"\awork_id\x18\x01 \x01(\tR\x06workId\x120\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x18.analysis.v1.ImageFormatR\x06format\"L\n" +
+	"\x11WordCloudResponse\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\fR\x05image\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType*W\n" +
+	"\vImageFormat\x12\x1c\n" +
+	"\x18IMAGE_FORMAT_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10IMAGE_FORMAT_SVG\x10\x01\x12\x14\n" +
+	"\x10IMAGE_FORMAT_PNG\x10\x022\xef\x01\n" +
+	"\x0fAnalysisService\x12D\n" +
+	"\aAnalyze\x12\x1b.analysis.v1.AnalyzeRequest\x1a\x1c.analysis.v1.AnalyzeResponse\x12J\n" +
+	"\tGetReport\x12\x1d.analysis.v1.GetReportRequest\x1a\x1e.analysis.v1.GetReportResponse\x12J\n" +
+	"\tWordCloud\x12\x1d.analysis.v1.WordCloudRequest\x1a\x1e.analysis.v1.WordCloudResponseBMZKgithub.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/analysis/v1;analysisv1b\x06proto3"
+
+var (
+	file_analysis_v1_analysis_proto_rawDescOnce sync.Once
+	file_analysis_v1_analysis_proto_rawDescData []byte
+)
+
+func file_analysis_v1_analysis_proto_rawDescGZIP() []byte {
+	file_analysis_v1_analysis_proto_rawDescOnce.Do(func() {
+		file_analysis_v1_analysis_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_analysis_v1_analysis_proto_rawDesc), len(file_analysis_v1_analysis_proto_rawDesc)))
+	})
+	return file_analysis_v1_analysis_proto_rawDescData
+}
+
+var file_analysis_v1_analysis_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_analysis_v1_analysis_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_analysis_v1_analysis_proto_goTypes = []any{
+	(ImageFormat)(0),              // 0: analysis.v1.ImageFormat
+	(*AnalyzeRequest)(nil),        // 1: analysis.v1.AnalyzeRequest
+	(*AnalyzeResponse)(nil),       // 2: analysis.v1.AnalyzeResponse
+	(*GetReportRequest)(nil),      // 3: analysis.v1.GetReportRequest
+	(*GetReportResponse)(nil),     // 4: analysis.v1.GetReportResponse
+	(*Report)(nil),                // 5: analysis.v1.Report
+	(*ReportDetails)(nil),         // 6: analysis.v1.ReportDetails
+	(*WordCloudRequest)(nil),      // 7: analysis.v1.WordCloudRequest
+	(*WordCloudResponse)(nil),     // 8: analysis.v1.WordCloudResponse
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_analysis_v1_analysis_proto_depIdxs = []int32{
+	5, // 0: analysis.v1.AnalyzeResponse.report:type_name -> analysis.v1.Report
+	5, // 1: analysis.v1.GetReportResponse.report:type_name -> analysis.v1.Report
+	6, // 2: analysis.v1.Report.details:type_name -> analysis.v1.ReportDetails
+	9, // 3: analysis.v1.Report.created_at:type_name -> google.protobuf.Timestamp
+	0, // 4: analysis.v1.WordCloudRequest.format:type_name -> analysis.v1.ImageFormat
+	1, // 5: analysis.v1.AnalysisService.Analyze:input_type -> analysis.v1.AnalyzeRequest
+	3, // 6: analysis.v1.AnalysisService.GetReport:input_type -> analysis.v1.GetReportRequest
+	7, // 7: analysis.v1.AnalysisService.WordCloud:input_type -> analysis.v1.WordCloudRequest
+	2, // 8: analysis.v1.AnalysisService.Analyze:output_type -> analysis.v1.AnalyzeResponse
+	4, // 9: analysis.v1.AnalysisService.GetReport:output_type -> analysis.v1.GetReportResponse
+	8, // 10: analysis.v1.AnalysisService.WordCloud:output_type -> analysis.v1.WordCloudResponse
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_analysis_v1_analysis_proto_init() }
+func file_analysis_v1_analysis_proto_init() {
+	if File_analysis_v1_analysis_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_analysis_v1_analysis_proto_rawDesc), len(file_analysis_v1_analysis_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_analysis_v1_analysis_proto_goTypes,
+		DependencyIndexes: file_analysis_v1_analysis_proto_depIdxs,
+		EnumInfos:         file_analysis_v1_analysis_proto_enumTypes,
+		MessageInfos:      file_analysis_v1_analysis_proto_msgTypes,
+	}.Build()
+	File_analysis_v1_analysis_proto = out.File
+	file_analysis_v1_analysis_proto_goTypes = nil
+	file_analysis_v1_analysis_proto_depIdxs = nil
+}
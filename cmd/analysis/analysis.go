@@ -2,34 +2,55 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log"
+	"net"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
 
+	analysisv1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/analysis/v1"
+	storagev1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/storage/v1"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/webhook"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/dispatch"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/jwt"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/persistence/postgres"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/progress"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/signing"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/text"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/rpc"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/pkg/config"
 )
 
-var storageServiceURL string
+const (
+	// progressRetention — сколько помнить итог проверки для подключившихся к потоку позже.
+	progressRetention = 10 * time.Minute
+	// streamHeartbeat — интервал комментариев в потоке, чтобы прокси не закрывали соединение.
+	streamHeartbeat = 15 * time.Second
+)
+
+// storageClient вызывает Storage Service по gRPC с подписью ключом Analysis Service.
+var storageClient storagev1.StorageServiceClient
 
 func main() {
 	_ = godotenv.Load(".env.local")
 	cfg := config.LoadConfig()
 
-	storageServiceURL = "http://localhost:9091"
+	storageAddr := "localhost:9091"
 	if cfg.Env == "docker" {
-		storageServiceURL = "http://storage:9091"
+		storageAddr = "storage:9091"
 	}
 
 	dbCfg := postgres.Config{
@@ -41,153 +62,215 @@ func main() {
 		log.Fatalf("Analysis Service: DB Connection failed: %v", err)
 	}
 
+	if _, err := postgres.Migrate(context.Background(), db); err != nil {
+		log.Fatalf("Analysis Service: migrations failed: %v", err)
+	}
+
 	workRepo := postgres.NewWorkRepository(db)
 	plagRepo := postgres.NewPlagiarismRepository(db)
 
+	// Отчеты сервиса рассылаются подписчикам вебхуков; очередь доставок общая с монолитом.
+	webhookRepo := postgres.NewWebhookRepository(db)
+	webhooks := service.NewWebhookService(webhookRepo)
+	go dispatch.NewDispatcher(webhookRepo, webhook.DefaultRetryPolicy()).Run(context.Background(), 2*time.Second)
+
 	detector := plagiarism.NewShingleDetector()
+	detector.Normalization = plagiarism.NormalizationPolicy{
+		ExcludeQuotes:       cfg.ExcludeQuotes,
+		ExcludeBibliography: cfg.ExcludeBibliography,
+	}
+	detector.MinTokens = cfg.MinTokensForComparison
+	detector.MinPassageWords = cfg.MinPassageWords
 	extractor := text.NewSimpleExtractor()
+	analyticsSvc := service.NewAnalyticsServiceWithTexts(workRepo, storageTexts{extractor: extractor}, detector)
+	// Этапы проверки публикуются в шину и отдаются клиентам потоком SSE.
+	bus := progress.NewBus(progressRetention)
 
-	r := gin.Default()
+	verifier, err := jwt.LoadVerifier(cfg.JWTSecret, cfg.JWTPublicKeyFile, cfg.JWTIssuer)
+	if err != nil {
+		log.Fatalf("Analysis Service: failed to configure authentication: %v", err)
+	}
+	apiKeys := service.NewAPIKeyService(postgres.NewAPIKeyRepository(db))
+	// Аналитика по работе доступна только преподавателю ее задания: шлюз передает токен
+	// пользователя или API-ключ.
+	teacherOfWork := []gin.HandlerFunc{
+		middleware.Auth(verifier, apiKeys.Authenticate),
+		middleware.RequireScope(auth.ScopeAnalyticsRead),
+		middleware.RequireWorkAccess(workRepo.GetByID, auth.AccessFull, "work_id"),
+	}
 
-	r.POST("/internal/analyze", func(c *gin.Context) {
-		analyzeHandler(c, db, workRepo, plagRepo, detector, extractor)
-	})
+	services, err := signing.LoadVerifier(cfg.TrustedServiceKeys)
+	if err != nil {
+		log.Fatalf("Analysis Service: failed to configure service authentication: %v", err)
+	}
+	signer, err := signing.NewSigner(cfg.ServiceKeyID, []byte(cfg.ServiceKeySecret))
+	if err != nil {
+		log.Fatalf("Analysis Service: failed to configure request signing: %v", err)
+	}
+	storageConn, err := rpc.Dial(storageAddr, signer)
+	if err != nil {
+		log.Fatalf("Analysis Service: failed to connect to storage: %v", err)
+	}
+	storageClient = storagev1.NewStorageServiceClient(storageConn)
 
-	r.GET("/internal/analyze/:work_id/wordcloud", func(c *gin.Context) {
-		wordCloudHandler(c, workRepo, extractor)
+	// Проверка и облако слов — контракт gRPC (api/proto/analysis/v1); стилометрия и поток
+	// хода проверки остаются на HTTP.
+	policy, err := plagiarism.NewPolicy(detector.Parameters(), cfg.SimilarityThreshold, cfg.DecisionRules)
+	if err != nil {
+		log.Fatalf("Analysis Service: invalid DECISION_RULES: %v", err)
+	}
+	auditLog := service.NewAuditService(postgres.NewAuditRepository(db))
+	if err := auditLog.RecordPolicy(context.Background(), "analysis", policy); err != nil {
+		log.Printf("Analysis Service: failed to record analysis policy in audit log: %v", err)
+	}
+	server := rpc.NewServer(services)
+	analysisv1.RegisterAnalysisServiceServer(server, &analysisServer{
+		workRepo:     workRepo,
+		plagRepo:     plagRepo,
+		detector:     detector,
+		extractor:    extractor,
+		webhooks:     webhooks,
+		bus:          bus,
+		users:        rpc.NewAuthenticator(verifier, apiKeys.Authenticate),
+		policy:       policy,
+		counterparts: service.NewCounterpartUpdater(plagRepo, detector, webhooks, policy),
 	})
+	grpcPort := ":9093"
+	lis, err := net.Listen("tcp", grpcPort)
+	if err != nil {
+		log.Fatalf("Analysis Service: failed to listen on %s: %v", grpcPort, err)
+	}
+	go func() {
+		log.Printf("🧠 Analysis Service (gRPC) running on %s", grpcPort)
+		if err := server.Serve(lis); err != nil {
+			log.Fatalf("Analysis Service: gRPC server stopped: %v", err)
+		}
+	}()
+
+	r := gin.Default()
+	internal := r.Group("/internal", middleware.ServiceAuth(services))
+
+	internal.GET("/analyze/:work_id/events",
+		middleware.Auth(verifier, apiKeys.Authenticate),
+		middleware.RequireScope(auth.ScopeReportsRead),
+		middleware.RequireWorkAccess(workRepo.GetByID, auth.AccessSummary, "work_id"),
+		func(c *gin.Context) {
+			eventsHandler(c, plagRepo, bus)
+		})
+
+	internal.GET("/analyze/:work_id/stylometry", append(teacherOfWork, func(c *gin.Context) {
+		stylometryHandler(c, analyticsSvc)
+	})...)
 
 	port := ":9092"
 	log.Printf("🧠 Analysis Service running on %s", port)
 	r.Run(port)
 }
 
-type AnalyzeRequest struct {
-	WorkID       uuid.UUID `json:"work_id"`
-	FileID       uuid.UUID `json:"file_id"`
-	AssignmentID uuid.UUID `json:"assignment_id"`
-	Filename     string    `json:"filename"`
-}
-
-func analyzeHandler(
-	c *gin.Context,
-	db *sqlx.DB,
-	workRepo work.Repository,
-	plagRepo plagiarism.Repository,
-	detector *plagiarism.ShingleDetector,
-	extractor *text.SimpleExtractor,
-) {
-	var req AnalyzeRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	content, err := downloadFileFromStorage(req.FileID)
-	if err != nil {
-		log.Printf("Failed to download file: %v", err)
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to download file from storage"})
-		return
+func completedEvent(r *plagiarism.Report) progress.Event {
+	return progress.Event{
+		WorkID:        r.WorkID,
+		Stage:         progress.StageCompleted,
+		ReportID:      &r.ID,
+		Score:         &r.Score,
+		IsPlagiarized: &r.IsPlagiarized,
 	}
+}
 
-	mimeType := "text/plain"
-	currentText, _ := extractor.ExtractText(bytes.NewReader(content), mimeType)
-
-	log.Printf("DEBUG: Extracted text length: %d", len(currentText))
-
-	otherWorks, err := workRepo.FindByAssignmentID(c.Request.Context(), req.AssignmentID)
+// eventsHandler отдает этапы проверки работы потоком SSE и закрывает его после completed
+// или failed. Если шина о работе не знает, а отчет уже есть, сразу отдается completed.
+func eventsHandler(c *gin.Context, plagRepo plagiarism.Repository, bus *progress.Bus) {
+	workID, err := uuid.Parse(c.Param("work_id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch other works"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
 		return
 	}
 
-	maxScore := 0.0
-	var matchID *uuid.UUID
-
-	log.Printf("DEBUG: Found %d other works for assignment", len(otherWorks))
-
-	for _, w := range otherWorks {
-		if w.ID == req.WorkID {
-			continue
-		}
+	// Подписка до чтения отчета: завершение между двумя шагами не потеряется.
+	events, unsubscribe := bus.Subscribe(workID)
+	defer unsubscribe()
 
-		otherContent, err := downloadFileFromStorage(w.FileID)
-		if err != nil {
-			continue
+	var pending []progress.Event
+	select {
+	case e, ok := <-events:
+		if ok {
+			pending = append(pending, e)
 		}
-
-		otherText, _ := extractor.ExtractText(bytes.NewReader(otherContent), mimeType)
-
-		score, _ := detector.Compare(currentText, otherText)
-		if score > maxScore {
-			maxScore = score
-			id := w.ID
-			matchID = &id
+	default:
+		if report, err := plagRepo.GetByWorkID(c.Request.Context(), workID); err == nil {
+			pending = append(pending, completedEvent(report))
 		}
-
-		log.Printf("DEBUG: Final Result - Score: %f, IsPlagiarized: %v", maxScore, maxScore > 0.85)
 	}
 
-	report := plagiarism.NewReport(req.WorkID, maxScore, 0.85)
-	if matchID != nil {
-		report.SetMatch(*matchID, plagiarism.AnalysisDetails{AlgorithmUsed: "shingle"})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		for _, e := range pending {
+			c.SSEvent(string(e.Stage), e)
+			c.Writer.Flush()
+			if e.Terminal() {
+				return
+			}
+		}
+		pending = pending[:0]
+
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			pending = append(pending, e)
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+			c.Writer.Flush()
+		case <-c.Request.Context().Done():
+			return
+		}
 	}
-	plagRepo.Save(c.Request.Context(), report)
-
-	c.JSON(http.StatusOK, report)
 }
 
-func wordCloudHandler(c *gin.Context, workRepo work.Repository, extractor *text.SimpleExtractor) {
-	workIDStr := c.Param("work_id")
-	workID, err := uuid.Parse(workIDStr)
+func stylometryHandler(c *gin.Context, analyticsSvc *service.AnalyticsService) {
+	workID, err := uuid.Parse(c.Param("work_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
 		return
 	}
 
-	workEntity, err := workRepo.GetByID(c.Request.Context(), workID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Work not found"})
-		return
-	}
-
-	content, err := downloadFileFromStorage(workEntity.FileID)
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to get file content"})
-		return
-	}
-
-	textStr, err := extractor.ExtractText(bytes.NewReader(content), "text/plain")
+	result, err := analyticsSvc.Stylometry(c.Request.Context(), workID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to extract text"})
+		if errors.Is(err, shared.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Work not found"})
+			return
+		}
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to analyze work style"})
 		return
 	}
 
-	if len(textStr) > 1000 {
-		textStr = textStr[:1000]
-	}
-
-	quickChartURL := fmt.Sprintf("https://quickchart.io/wordcloud?text=%s&format=png&width=800&height=600", url.QueryEscape(textStr))
-
-	c.JSON(http.StatusOK, gin.H{
-		"work_id":        workID,
-		"word_cloud_url": quickChartURL,
-	})
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
 }
 
-func downloadFileFromStorage(fileID uuid.UUID) ([]byte, error) {
-	url := fmt.Sprintf("%s/internal/files/%s/content", storageServiceURL, fileID.String())
+// storageTexts загружает тексты работ из Storage Service.
+type storageTexts struct {
+	extractor *text.SimpleExtractor
+}
 
-	client := http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(url)
+func (t storageTexts) Load(ctx context.Context, w *work.Work) (string, error) {
+	content, err := downloadFileFromStorage(ctx, w.FileID)
 	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("storage returned %d", resp.StatusCode)
+		return "", err
 	}
+	return t.extractor.ExtractText(bytes.NewReader(content), "text/plain")
+}
 
-	return io.ReadAll(resp.Body)
+func downloadFileFromStorage(ctx context.Context, fileID uuid.UUID) ([]byte, error) {
+	_, content, err := rpc.DownloadFile(ctx, storageClient, fileID.String())
+	return content, err
 }
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	analysisv1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/analysis/v1"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/progress"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/text"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/wordcloud"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/rpc"
+)
+
+type analysisServer struct {
+	analysisv1.UnimplementedAnalysisServiceServer
+	workRepo  work.Repository
+	plagRepo  plagiarism.Repository
+	detector  *plagiarism.ShingleDetector
+	extractor *text.SimpleExtractor
+	webhooks  service.WebhookEvents
+	bus       *progress.Bus
+	users     *rpc.Authenticator
+	policy    plagiarism.Policy
+	// counterparts дописывает совпадение с проверенной работой в отчеты ранее сданных.
+	counterparts *service.CounterpartUpdater
+}
+
+func (s *analysisServer) Analyze(ctx context.Context, req *analysisv1.AnalyzeRequest) (*analysisv1.AnalyzeResponse, error) {
+	workID, errWork := uuid.Parse(req.WorkId)
+	fileID, errFile := uuid.Parse(req.FileId)
+	assignmentID, errAssignment := uuid.Parse(req.AssignmentId)
+	if errWork != nil || errFile != nil || errAssignment != nil {
+		return nil, status.Error(codes.InvalidArgument, "work_id, file_id and assignment_id must be UUIDs")
+	}
+
+	checked, err := s.ensureWork(ctx, workID, fileID, assignmentID, req.StudentId)
+	if err != nil {
+		return nil, err
+	}
+
+	publish := func(e progress.Event) {
+		e.WorkID = workID
+		s.bus.Publish(e)
+	}
+	publish(progress.Event{Stage: progress.StageQueued})
+
+	publish(progress.Event{Stage: progress.StageExtracting})
+	content, err := downloadFileFromStorage(ctx, fileID)
+	if err != nil {
+		log.Printf("Failed to download file: %v", err)
+		publish(progress.Event{Stage: progress.StageFailed, Error: "Failed to download file from storage"})
+		return nil, status.Error(codes.Unavailable, "Failed to download file from storage")
+	}
+
+	mimeType := "text/plain"
+	currentText, _ := s.extractor.ExtractText(bytes.NewReader(content), mimeType)
+
+	log.Printf("DEBUG: Extracted text length: %d", len(currentText))
+
+	policy := s.policy
+	if tokens := s.detector.CountTokens(currentText); !s.detector.Sufficient(tokens) {
+		report := plagiarism.NewInsufficientReport(workID, tokens, s.detector.Exclusions(currentText), policy)
+		s.saveReport(ctx, report)
+		publish(completedEvent(report))
+		return &analysisv1.AnalyzeResponse{Report: reportMessage(report, auth.AccessFull)}, nil
+	}
+
+	otherWorks, err := s.workRepo.FindByAssignmentID(ctx, assignmentID)
+	if err != nil {
+		publish(progress.Event{Stage: progress.StageFailed, Error: "Failed to fetch other works"})
+		return nil, status.Error(codes.Internal, "Failed to fetch other works")
+	}
+
+	total := 0
+	for _, w := range otherWorks {
+		if w.ID != workID {
+			total++
+		}
+	}
+	done := 0
+	publish(progress.Event{Stage: progress.StageComparing, Done: done, Total: total})
+
+	maxScore := 0.0
+	var matchID *uuid.UUID
+	var matchText string
+	var matches []plagiarism.Match
+	var counterparts []service.Counterpart
+
+	log.Printf("DEBUG: Found %d other works for assignment", len(otherWorks))
+
+	for _, w := range otherWorks {
+		if w.ID == workID {
+			continue
+		}
+
+		otherContent, err := downloadFileFromStorage(ctx, w.FileID)
+		done++
+		if err != nil {
+			publish(progress.Event{Stage: progress.StageComparing, Done: done, Total: total})
+			continue
+		}
+
+		otherText, _ := s.extractor.ExtractText(bytes.NewReader(otherContent), mimeType)
+
+		overlap, _ := s.detector.Overlap(currentText, otherText)
+		score := overlap.Similarity
+		matches = append(matches, plagiarism.NewMatch(w.ID, score, overlap.Containment, checked.SubmittedAt, w.SubmittedAt))
+		if score > 0 {
+			counterparts = append(counterparts, service.Counterpart{Work: w, Text: otherText, Overlap: overlap})
+		}
+		if score > maxScore {
+			maxScore = score
+			id := w.ID
+			matchID = &id
+			matchText = otherText
+		}
+
+		log.Printf("DEBUG: Final Result - Score: %f, IsPlagiarized: %v", maxScore, maxScore > policy.Threshold)
+		publish(progress.Event{Stage: progress.StageComparing, Done: done, Total: total})
+	}
+
+	details := plagiarism.NewAnalysisDetails(
+		s.detector.CountTokens(currentText),
+		s.detector.Passages(currentText, matchText),
+		s.detector.Exclusions(currentText),
+	)
+
+	report := plagiarism.NewReport(workID, maxScore, policy)
+	report.Details = details
+	if matchID != nil {
+		report.SetMatch(*matchID, details)
+	}
+	report.SetMatches(matches)
+	s.saveReport(ctx, report)
+	s.counterparts.AddMatches(ctx, checked, currentText, counterparts)
+	publish(completedEvent(report))
+
+	return &analysisv1.AnalyzeResponse{Report: reportMessage(report, auth.AccessFull)}, nil
+}
+
+// ensureWork возвращает проверяемую работу и сохраняет ее, если сервис видит ее впервые:
+// шлюз не пишет в базу, а отчет, облако слов и поток проверки выдаются по правам на работу.
+func (s *analysisServer) ensureWork(ctx context.Context, workID, fileID, assignmentID uuid.UUID, studentID string) (*work.Work, error) {
+	w, err := s.workRepo.GetByID(ctx, workID)
+	if err == nil {
+		return w, nil
+	}
+	if !errors.Is(err, shared.ErrNotFound) {
+		log.Printf("Failed to load work %s: %v", workID, err)
+		return nil, status.Error(codes.Internal, "Failed to load work")
+	}
+
+	student, err := uuid.Parse(studentID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "student_id must be a UUID")
+	}
+	w = &work.Work{ID: workID, AssignmentID: assignmentID, StudentID: student, FileID: fileID, SubmittedAt: time.Now()}
+	if err := s.workRepo.Save(ctx, w); err != nil {
+		log.Printf("Failed to save work %s: %v", workID, err)
+		return nil, status.Error(codes.Internal, "Failed to save work")
+	}
+	return w, nil
+}
+
+// saveReport сохраняет отчет и рассылает вебхук; ошибка сохранения только логируется,
+// чтобы вызывающий все равно получил результат проверки.
+func (s *analysisServer) saveReport(ctx context.Context, report *plagiarism.Report) {
+	if err := s.plagRepo.Save(ctx, report); err != nil {
+		log.Printf("Failed to save report for work %s: %v", report.WorkID, err)
+	} else if w, err := s.workRepo.GetByID(ctx, report.WorkID); err == nil {
+		s.webhooks.ReportSaved(ctx, report, w)
+	}
+}
+
+func (s *analysisServer) GetReport(ctx context.Context, req *analysisv1.GetReportRequest) (*analysisv1.GetReportResponse, error) {
+	level, err := s.users.WorkAccess(ctx, s.workRepo.GetByID, auth.ScopeReportsRead, auth.AccessSummary, req.WorkId)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.plagRepo.GetByWorkID(ctx, uuid.MustParse(req.WorkId))
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "Report not found")
+		}
+		return nil, status.Error(codes.Internal, "Failed to load report")
+	}
+	return &analysisv1.GetReportResponse{Report: reportMessage(report, level)}, nil
+}
+
+func (s *analysisServer) WordCloud(ctx context.Context, req *analysisv1.WordCloudRequest) (*analysisv1.WordCloudResponse, error) {
+	if _, err := s.users.WorkAccess(ctx, s.workRepo.GetByID, auth.ScopeAnalyticsRead, auth.AccessFull, req.WorkId); err != nil {
+		return nil, err
+	}
+
+	format := wordcloud.FormatSVG
+	switch req.Format {
+	case analysisv1.ImageFormat_IMAGE_FORMAT_UNSPECIFIED, analysisv1.ImageFormat_IMAGE_FORMAT_SVG:
+	case analysisv1.ImageFormat_IMAGE_FORMAT_PNG:
+		format = wordcloud.FormatPNG
+	default:
+		return nil, status.Error(codes.InvalidArgument, "format must be svg or png")
+	}
+
+	workEntity, err := s.workRepo.GetByID(ctx, uuid.MustParse(req.WorkId))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "Work not found")
+	}
+
+	content, err := downloadFileFromStorage(ctx, workEntity.FileID)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, "Failed to get file content")
+	}
+
+	textStr, err := s.extractor.ExtractText(bytes.NewReader(content), "text/plain")
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to extract text")
+	}
+
+	var buf bytes.Buffer
+	if err := wordcloud.Generate(textStr, wordcloud.DefaultOptions()).Write(&buf, format); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to render word cloud")
+	}
+
+	return &analysisv1.WordCloudResponse{Image: buf.Bytes(), ContentType: wordcloud.ContentType(format)}, nil
+}
+
+// reportMessage переводит отчет в сообщение; автору работы (AccessSummary) совпавшая
+// работа и подробности не отдаются.
+func reportMessage(r *plagiarism.Report, level auth.AccessLevel) *analysisv1.Report {
+	msg := &analysisv1.Report{
+		Id:            r.ID.String(),
+		WorkId:        r.WorkID.String(),
+		IsPlagiarized: r.IsPlagiarized,
+		Score:         r.Score,
+		CreatedAt:     timestamppb.New(r.CreatedAt),
+		Status:        service.ReportStatus(r),
+	}
+	if level < auth.AccessFull {
+		return msg
+	}
+	if r.MatchedWorkID != nil {
+		msg.MatchedWorkId = r.MatchedWorkID.String()
+	}
+	msg.Details = &analysisv1.ReportDetails{
+		Algorithm:           r.Details.AlgorithmUsed,
+		MatchedTokens:       int32(r.Details.MatchedTokens),
+		TotalTokens:         int32(r.Details.TotalTokens),
+		Coverage:            r.Details.Coverage,
+		InsufficientContent: r.Details.InsufficientContent,
+	}
+	return msg
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/audit"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/persistence/postgres"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/storage/local"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/pkg/config"
+)
+
+// Административные команды работают с базой и каталогом хранилища напрямую, с теми же
+// переменными окружения, что и сервисы (DB_*, FILE_STORAGE_PATH).
+
+func connect(cfg config.Config) (*sqlx.DB, error) {
+	return postgres.NewConnection(postgres.Config{
+		Host:     cfg.DBHost,
+		Port:     cfg.DBPort,
+		User:     cfg.DBUser,
+		Password: cfg.DBPassword,
+		DBName:   cfg.DBName,
+		SSLMode:  cfg.DBSSLMode,
+	})
+}
+
+func runMigrate(ctx context.Context, args []string) error {
+	fs := newFlagSet("migrate")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := connect(config.LoadConfig())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	applied, err := postgres.Migrate(ctx, db)
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("Schema is up to date.")
+	}
+	for _, m := range applied {
+		fmt.Printf("Applied migration %s\n", m)
+	}
+	return nil
+}
+
+// runReindex пересчитывает content_hash файлов по содержимому в хранилище: по нему
+// ищутся повторные загрузки того же файла.
+func runReindex(ctx context.Context, args []string) error {
+	fs := newFlagSet("reindex")
+	dryRun := fs.Bool("dry-run", false, "только показать расхождения")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := config.LoadConfig()
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	files := postgres.NewFileRepository(db)
+	storage := local.NewLocalFileStorage(cfg.FileStoragePath)
+
+	list, err := files.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var updated, missing int
+	for _, f := range list {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rc, err := storage.Download(ctx, f.StoragePath)
+		if err != nil {
+			fmt.Printf("missing  %s  %s: %v\n", f.ID, f.StoragePath, err)
+			missing++
+			continue
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("read %s: %w", f.StoragePath, err)
+		}
+
+		hash := hex.EncodeToString(h.Sum(nil))
+		if hash == f.Hash {
+			continue
+		}
+		fmt.Printf("rehash   %s  %s\n", f.ID, f.OriginalName)
+		updated++
+		if *dryRun {
+			continue
+		}
+		if err := files.UpdateHash(ctx, f.ID, hash); err != nil {
+			return fmt.Errorf("update %s: %w", f.ID, err)
+		}
+	}
+
+	fmt.Printf("%d files checked, %d rehashed, %d missing in storage\n", len(list), updated, missing)
+	return nil
+}
+
+// runGC удаляет из каталога хранилища файлы, на которые не ссылаются ни файлы работ, ни
+// приложения к апелляциям. Свежие файлы не трогаются: загрузка пишет содержимое раньше,
+// чем запись в базу.
+func runGC(ctx context.Context, args []string) error {
+	fs := newFlagSet("gc")
+	dryRun := fs.Bool("dry-run", false, "только показать, что будет удалено")
+	minAge := fs.Duration("min-age", 24*time.Hour, "не удалять файлы моложе")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := config.LoadConfig()
+	db, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	storage := local.NewLocalFileStorage(cfg.FileStoragePath)
+	// Сначала список каталога, потом ссылки: файл, записанный между ними, либо уже
+	// есть в ссылках, либо слишком свежий.
+	blobs, err := storage.List(ctx)
+	if err != nil {
+		return err
+	}
+	referenced, err := postgres.NewFileRepository(db).StoragePaths(ctx)
+	if err != nil {
+		return err
+	}
+
+	auditLog := service.NewAuditService(postgres.NewAuditRepository(db))
+
+	cutoff := time.Now().Add(-*minAge)
+	var removed int
+	var freed int64
+	for _, b := range blobs {
+		if _, ok := referenced[b.Path]; ok || b.ModTime.After(cutoff) {
+			continue
+		}
+		fmt.Printf("remove  %s  %d bytes  %s\n", b.Path, b.Size, b.ModTime.Format(time.RFC3339))
+		removed++
+		freed += b.Size
+		if *dryRun {
+			continue
+		}
+		if err := storage.Delete(ctx, b.Path); err != nil {
+			return fmt.Errorf("delete %s: %w", b.Path, err)
+		}
+		err := auditLog.RecordSystem(ctx, audit.Event{
+			Action:       audit.ActionDelete,
+			ResourceType: "file",
+			ResourceID:   b.Path,
+			Details:      map[string]string{"size": strconv.FormatInt(b.Size, 10), "reason": "gc"},
+		})
+		if err != nil {
+			return fmt.Errorf("record deletion of %s: %w", b.Path, err)
+		}
+	}
+
+	fmt.Printf("%d of %d stored files unreferenced, %d bytes\n", removed, len(blobs), freed)
+	return nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/text"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/pkg/config"
+)
+
+// errFound — check -fail нашел пару не ниже порога плагиата.
+var errFound = errors.New("plagiarism found")
+
+// checkedExtensions — файлы, которые принимает и сервис: TXT и MD.
+var checkedExtensions = map[string]bool{".txt": true, ".md": true}
+
+type checkPair struct {
+	File          string  `json:"file"`
+	MatchedFile   string  `json:"matched_file"`
+	Score         float64 `json:"score"`
+	MatchedWords  int     `json:"matched_words"`
+	IsPlagiarized bool    `json:"is_plagiarized"`
+}
+
+type checkResult struct {
+	Dir       string                `json:"dir"`
+	Files     int                   `json:"files"`
+	Threshold float64               `json:"threshold"`
+	Params    plagiarism.Parameters `json:"params"`
+	Pairs     []checkPair           `json:"pairs"`
+	Clusters  [][]string            `json:"clusters"`
+}
+
+func runCheck(ctx context.Context, args []string) error {
+	cfg := config.LoadConfig()
+
+	fs := newFlagSet("check")
+	threshold := fs.Float64("threshold", cfg.SimilarityThreshold, "порог плагиата: пары не ниже него отмечаются и объединяются в группы")
+	minScore := fs.Float64("min-score", 0.1, "не показывать пары с оценкой ниже")
+	top := fs.Int("top", 0, "показать только N пар с наибольшей оценкой (0 — все)")
+	asJSON := fs.Bool("json", false, "вывести результат в JSON")
+	fail := fs.Bool("fail", false, "завершиться с кодом 1, если есть пара не ниже порога")
+	excludeQuotes := fs.Bool("exclude-quotes", cfg.ExcludeQuotes, "не сравнивать цитаты")
+	excludeBibliography := fs.Bool("exclude-bibliography", cfg.ExcludeBibliography, "не сравнивать список литературы")
+	minTokens := fs.Int("min-tokens", cfg.MinTokensForComparison, "не сравнивать файлы короче стольких слов")
+	minPassageWords := fs.Int("min-passage-words", cfg.MinPassageWords, "не учитывать общие фрагменты короче стольких слов")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	dir := fs.Arg(0)
+
+	names, texts, err := loadTexts(dir)
+	if err != nil {
+		return err
+	}
+	if len(names) < 2 {
+		return fmt.Errorf("need at least two .txt or .md files in %s, found %d", dir, len(names))
+	}
+
+	detector := plagiarism.NewShingleDetector()
+	detector.Normalization = plagiarism.NormalizationPolicy{
+		ExcludeQuotes:       *excludeQuotes,
+		ExcludeBibliography: *excludeBibliography,
+	}
+	detector.MinTokens = *minTokens
+	detector.MinPassageWords = *minPassageWords
+
+	// Матрица адресует работы по идентификаторам; для локальных файлов они временные.
+	ids := make([]uuid.UUID, len(names))
+	nameOf := make(map[uuid.UUID]string, len(names))
+	textOf := make(map[uuid.UUID]string, len(names))
+	for i, name := range names {
+		ids[i] = uuid.New()
+		nameOf[ids[i]] = name
+		textOf[ids[i]] = texts[i]
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	matrix, err := plagiarism.BuildSimilarityMatrix(detector, ids, texts)
+	if err != nil {
+		return err
+	}
+
+	result := checkResult{
+		Dir:       dir,
+		Files:     len(names),
+		Threshold: *threshold,
+		Params:    detector.Parameters(),
+		Pairs:     []checkPair{},
+		Clusters:  [][]string{},
+	}
+	pairs := matrix.Pairs(*minScore)
+	if *top > 0 && len(pairs) > *top {
+		pairs = pairs[:*top]
+	}
+	found := false
+	for _, p := range pairs {
+		passages := detector.Passages(textOf[p.WorkID], textOf[p.MatchedWorkID])
+		result.Pairs = append(result.Pairs, checkPair{
+			File:          nameOf[p.WorkID],
+			MatchedFile:   nameOf[p.MatchedWorkID],
+			Score:         p.Score,
+			MatchedWords:  plagiarism.MatchedWords(passages),
+			IsPlagiarized: p.Score >= *threshold,
+		})
+		found = found || p.Score >= *threshold
+	}
+	for _, c := range matrix.Clusters(*threshold) {
+		files := make([]string, len(c.WorkIDs))
+		for i, id := range c.WorkIDs {
+			files[i] = nameOf[id]
+		}
+		result.Clusters = append(result.Clusters, files)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printCheck(os.Stdout, result)
+	}
+
+	if *fail && found {
+		return errFound
+	}
+	return nil
+}
+
+// loadTexts читает TXT и MD из dir и его подкаталогов. Имена — пути относительно dir.
+func loadTexts(dir string) ([]string, []string, error) {
+	extractor := text.NewSimpleExtractor()
+	var names, texts []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !checkedExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		// Markdown извлекается как обычный текст: разметка в шинглы почти не попадает.
+		content, err := extractor.ExtractText(f, "text/plain")
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		names = append(names, rel)
+		texts = append(texts, content)
+		return nil
+	})
+	return names, texts, err
+}
+
+func printCheck(w io.Writer, r checkResult) {
+	fmt.Fprintf(w, "%d files in %s, %s v%s, threshold %.2f\n\n", r.Files, r.Dir, r.Params.Algorithm, r.Params.Version, r.Threshold)
+	if len(r.Pairs) == 0 {
+		fmt.Fprintln(w, "No similar pairs.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tSCORE\tFILE\tMATCHED FILE\tMATCHED WORDS\t")
+	for i, p := range r.Pairs {
+		mark := ""
+		if p.IsPlagiarized {
+			mark = "!"
+		}
+		fmt.Fprintf(tw, "%d\t%.3f%s\t%s\t%s\t%d\t\n", i+1, p.Score, mark, p.File, p.MatchedFile, p.MatchedWords)
+	}
+	tw.Flush()
+
+	if len(r.Clusters) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Groups above threshold:")
+		for _, c := range r.Clusters {
+			fmt.Fprintln(w, "  "+strings.Join(c, ", "))
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/joho/godotenv"
+)
+
+// command — подкоманда CLI: разбирает свои флаги из args и выполняет работу.
+type command struct {
+	name string
+	run  func(ctx context.Context, args []string) error
+}
+
+// usages — строки использования подкоманд; отдельно от commands, чтобы newFlagSet
+// не зависел от списка команд при инициализации.
+var usages = map[string]string{
+	"check":   "check [flags] DIR          сравнить файлы каталога локально, без сервера",
+	"submit":  "submit [flags] FILE        загрузить работу через шлюз",
+	"report":  "report [flags] WORK_ID     получить отчет по работе через шлюз",
+	"migrate": "migrate                    применить миграции базы",
+	"reindex": "reindex [flags]            пересчитать хеши содержимого файлов",
+	"gc":      "gc [flags]                 удалить из хранилища файлы без ссылок из базы",
+}
+
+var commands = []command{
+	{"check", runCheck},
+	{"submit", runSubmit},
+	{"report", runReport},
+	{"migrate", runMigrate},
+	{"reindex", runReindex},
+	{"gc", runGC},
+}
+
+// errUsage — неверные аргументы; сообщение уже выведено.
+var errUsage = errors.New("usage")
+
+func main() {
+	// Настройки базы и хранилища берутся из окружения, как у сервисов.
+	_ = godotenv.Load(".env.local")
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	for _, cmd := range commands {
+		if cmd.name != os.Args[1] {
+			continue
+		}
+		err := cmd.run(ctx, os.Args[2:])
+		switch {
+		case err == nil:
+			return
+		case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+			os.Exit(2)
+		case errors.Is(err, errFound):
+			os.Exit(1)
+		default:
+			fmt.Fprintf(os.Stderr, "antiplague %s: %v\n", cmd.name, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "antiplague: unknown command %q\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: antiplague <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr)
+	for _, cmd := range commands {
+		fmt.Fprintln(os.Stderr, "  "+usages[cmd.name])
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "antiplague <command> -h — флаги команды.")
+}
+
+// newFlagSet создает набор флагов подкоманды с ее строкой использования.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: antiplague "+usages[name])
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// envOr возвращает значение переменной окружения или def.
+func envOr(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return def
+}
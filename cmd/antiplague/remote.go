@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/pkg/client"
+)
+
+// remoteFlags — адрес шлюза и учетные данные; по умолчанию из ANTIPLAGUE_SERVER,
+// ANTIPLAGUE_TOKEN и ANTIPLAGUE_API_KEY.
+type remoteFlags struct {
+	server string
+	token  string
+	apiKey string
+	json   bool
+}
+
+func (r *remoteFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&r.server, "server", envOr("ANTIPLAGUE_SERVER", "http://localhost:9090"), "адрес шлюза")
+	fs.StringVar(&r.token, "token", envOr("ANTIPLAGUE_TOKEN", ""), "JWT пользователя")
+	fs.StringVar(&r.apiKey, "api-key", envOr("ANTIPLAGUE_API_KEY", ""), "API-ключ вместо JWT")
+	fs.BoolVar(&r.json, "json", false, "вывести ответ в JSON")
+}
+
+func (r *remoteFlags) client() *client.Client {
+	var opts []client.Option
+	if r.token != "" {
+		opts = append(opts, client.WithToken(r.token))
+	}
+	if r.apiKey != "" {
+		opts = append(opts, client.WithAPIKey(r.apiKey))
+	}
+	return client.New(r.server, opts...)
+}
+
+func runSubmit(ctx context.Context, args []string) error {
+	fs := newFlagSet("submit")
+	var remote remoteFlags
+	remote.register(fs)
+	assignment := fs.String("assignment", "", "ID задания (UUID)")
+	student := fs.String("student", "", "ID студента (UUID)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	assignmentID, err := uuid.Parse(*assignment)
+	if err != nil {
+		return fmt.Errorf("invalid -assignment: %w", err)
+	}
+	studentID, err := uuid.Parse(*student)
+	if err != nil {
+		return fmt.Errorf("invalid -student: %w", err)
+	}
+
+	path := fs.Arg(0)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sub, err := remote.client().SubmitWork(ctx, assignmentID, studentID, f, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	if remote.json {
+		return printJSON(sub)
+	}
+	fmt.Printf("work_id:        %s\n", sub.WorkID)
+	fmt.Printf("submitted_at:   %s\n", sub.SubmittedAt.Format(time.RFC3339))
+	fmt.Printf("score:          %.3f\n", sub.Plagiarism.Score)
+	fmt.Printf("is_plagiarized: %t\n", sub.Plagiarism.IsPlagiarized)
+	return nil
+}
+
+func runReport(ctx context.Context, args []string) error {
+	fs := newFlagSet("report")
+	var remote remoteFlags
+	remote.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	workID, err := uuid.Parse(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid work id: %w", err)
+	}
+
+	report, err := remote.client().GetReport(ctx, workID)
+	if err != nil {
+		return err
+	}
+
+	if remote.json {
+		return printJSON(report)
+	}
+	fmt.Printf("work_id:         %s\n", report.WorkID)
+	fmt.Printf("status:          %s\n", report.Status)
+	fmt.Printf("score:           %.3f\n", report.SimilarityScore)
+	fmt.Printf("is_plagiarized:  %t\n", report.IsPlagiarized)
+	if report.MatchedWorkID != nil {
+		fmt.Printf("matched_work_id: %s\n", report.MatchedWorkID)
+	}
+	if d := report.Details; d.Algorithm != "" {
+		fmt.Printf("matched_tokens:  %d of %d (coverage %.2f, %s)\n", d.MatchedTokens, d.TotalTokens, d.Coverage, d.Algorithm)
+	}
+	if report.CreatedAt != nil {
+		fmt.Printf("created_at:      %s\n", report.CreatedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
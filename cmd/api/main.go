@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,8 +13,13 @@ import (
 
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
 
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/appeal"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/webhook"
 
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/dispatch"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/jwt"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/notify"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/persistence/postgres"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/storage/local"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/text"
@@ -21,6 +27,12 @@ import (
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/pkg/config"
 )
 
+// webhookPollInterval — как часто проверяется очередь доставок вебхуков.
+const webhookPollInterval = 2 * time.Second
+
+// batchPollInterval — как часто проверяется очередь пакетных загрузок.
+const batchPollInterval = 5 * time.Second
+
 func main() {
 	if err := godotenv.Load(".env.local"); err != nil {
 		log.Println("Warning: .env.local file not found, relying on environment variables")
@@ -45,9 +57,18 @@ func main() {
 
 	log.Println("Connected to PostgreSQL")
 
+	applied, err := postgres.Migrate(context.Background(), db)
+	if err != nil {
+		log.Fatalf("Failed to apply migrations: %v", err)
+	}
+	for _, m := range applied {
+		log.Printf("Applied migration %s", m)
+	}
+
 	workRepo := postgres.NewWorkRepository(db)
 	fileRepo := postgres.NewFileRepository(db)
 	plagRepo := postgres.NewPlagiarismRepository(db)
+	reviewRepo := postgres.NewReviewRepository(db)
 
 	if err := os.MkdirAll(cfg.FileStoragePath, 0755); err != nil {
 		log.Fatalf("Failed to create storage directory: %v", err)
@@ -57,6 +78,21 @@ func main() {
 	textExtractor := text.NewSimpleExtractor()
 
 	detector := plagiarism.NewShingleDetector()
+	detector.Normalization = plagiarism.NormalizationPolicy{
+		ExcludeQuotes:       cfg.ExcludeQuotes,
+		ExcludeBibliography: cfg.ExcludeBibliography,
+	}
+	detector.MinTokens = cfg.MinTokensForComparison
+	detector.MinPassageWords = cfg.MinPassageWords
+
+	policy, err := plagiarism.NewPolicy(detector.Parameters(), cfg.SimilarityThreshold, cfg.DecisionRules)
+	if err != nil {
+		log.Fatalf("Invalid DECISION_RULES: %v", err)
+	}
+
+	webhookRepo := postgres.NewWebhookRepository(db)
+	webhookSvc := service.NewWebhookService(webhookRepo)
+	go dispatch.NewDispatcher(webhookRepo, webhook.DefaultRetryPolicy()).Run(context.Background(), webhookPollInterval)
 
 	submissionSvc := service.NewSubmissionService(
 		workRepo,
@@ -65,9 +101,76 @@ func main() {
 		fileStorage,
 		textExtractor,
 		detector,
+		webhookSvc,
+		policy,
+	)
+
+	reportSvc := service.NewReportService(plagRepo, workRepo, reviewRepo, policy)
+	reanalysisSvc := service.NewReanalysisService(
+		workRepo,
+		fileRepo,
+		plagRepo,
+		fileStorage,
+		textExtractor,
+		detector,
+		webhookSvc,
+		policy,
+	)
+	reviewSvc := service.NewReviewService(plagRepo, workRepo, reviewRepo, webhookSvc)
+
+	similaritySvc := service.NewSimilarityService(
+		workRepo,
+		plagRepo,
+		fileRepo,
+		fileStorage,
+		textExtractor,
+		detector,
+		cfg.SimilarityThreshold,
+	)
+
+	const maxFileSize = 50 * 1024 * 1024
+
+	batchSvc := service.NewBatchService(
+		postgres.NewBatchRepository(db),
+		workRepo,
+		fileRepo,
+		plagRepo,
+		fileStorage,
+		textExtractor,
+		detector,
+		webhookSvc,
+		policy,
+		int64(maxFileSize),
+	)
+	go batchSvc.Run(context.Background(), batchPollInterval)
+
+	analyticsSvc := service.NewAnalyticsService(workRepo, fileRepo, fileStorage, textExtractor, detector)
+
+	appealSvc := service.NewAppealService(
+		postgres.NewAppealRepository(db),
+		plagRepo,
+		reviewRepo,
+		fileStorage,
+		notify.NewLogNotifier(),
+		webhookSvc,
+		appeal.Policy{
+			FilingWindow: time.Duration(cfg.AppealFilingDays) * 24 * time.Hour,
+			ReviewWindow: time.Duration(cfg.AppealReviewDays) * 24 * time.Hour,
+		},
 	)
+	go watchOverdueAppeals(appealSvc)
 
-	reportSvc := service.NewReportService(plagRepo, workRepo)
+	apiKeySvc := service.NewAPIKeyService(postgres.NewAPIKeyRepository(db))
+	auditSvc := service.NewAuditService(postgres.NewAuditRepository(db))
+	// Порог и правила приходят из конфигурации: их смена записывается при запуске.
+	if err := auditSvc.RecordPolicy(context.Background(), "api", policy); err != nil {
+		log.Printf("Failed to record analysis policy in audit log: %v", err)
+	}
+
+	verifier, err := jwt.LoadVerifier(cfg.JWTSecret, cfg.JWTPublicKeyFile, cfg.JWTIssuer)
+	if err != nil {
+		log.Fatalf("Failed to configure authentication: %v", err)
+	}
 
 	if cfg.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -77,13 +180,21 @@ func main() {
 
 	engine.Use(gin.Recovery())
 
-	const maxFileSize = 50 * 1024 * 1024
-
 	httplayer.SetupRoutes(
 		engine,
 		db,
 		submissionSvc,
 		reportSvc,
+		reviewSvc,
+		appealSvc,
+		similaritySvc,
+		analyticsSvc,
+		apiKeySvc,
+		auditSvc,
+		webhookSvc,
+		batchSvc,
+		reanalysisSvc,
+		verifier,
 		int64(maxFileSize),
 	)
 
@@ -102,3 +213,19 @@ func main() {
 		log.Fatalf("Server failed: %v", err)
 	}
 }
+
+// watchOverdueAppeals раз в час уведомляет о нерассмотренных в срок апелляциях.
+func watchOverdueAppeals(appeals *service.AppealService) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		n, err := appeals.NotifyOverdue(context.Background())
+		if err != nil {
+			log.Printf("Failed to check overdue appeals: %v", err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("Sent %d overdue appeal notifications", n)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+)
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Create a scoped key for an LMS integration or a script. The key itself is returned only in this response; only its hash is stored
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body service.CreateAPIKeyRequest true "Name, scopes (works:submit, reports:read, analytics:read, reviews:write), optional assignment and expiry"
+// @Success 201 {object} dto.CreatedAPIKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/api-keys [post]
+func createAPIKeyHandler(keys *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req service.CreateAPIKeyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid request body: " + err.Error()})
+			return
+		}
+
+		key, err := keys.Create(c.Request.Context(), req, principal(c).UserID)
+		if err != nil {
+			if errors.Is(err, shared.ErrInvalidInput) {
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: err.Error()})
+				return
+			}
+			log.Printf("API key creation failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to create API key"})
+			return
+		}
+
+		middleware.SetAuditResource(c, key.ID.String())
+		c.JSON(http.StatusCreated, dto.CreatedAPIKeyResponse{Success: true, Data: *key, Timestamp: time.Now()})
+	}
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description List all API keys with scopes, expiry and last use; secrets are never returned
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.APIKeyListResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/api-keys [get]
+func listAPIKeysHandler(keys *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		list, err := keys.List(c.Request.Context())
+		if err != nil {
+			log.Printf("API key listing failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to list API keys"})
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.APIKeyListResponse{Success: true, Data: list, Timestamp: time.Now()})
+	}
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revoke a key immediately; revoking an already revoked key keeps the original revocation time
+// @Tags admin
+// @Produce json
+// @Param key_id path string true "API key ID"
+// @Success 200 {object} dto.APIKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/api-keys/{key_id} [delete]
+func revokeAPIKeyHandler(keys *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyID, err := uuid.Parse(c.Param("key_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid key_id"})
+			return
+		}
+
+		key, err := keys.Revoke(c.Request.Context(), keyID)
+		if err != nil {
+			if errors.Is(err, shared.ErrNotFound) {
+				c.JSON(http.StatusNotFound, dto.ErrorResponse{Success: false, Error: "API key not found"})
+				return
+			}
+			log.Printf("API key revocation failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to revoke API key"})
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.APIKeyResponse{Success: true, Data: *key, Timestamp: time.Now()})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/dto"
+)
+
+// GetAuditLog godoc
+// @Summary Query the audit log
+// @Description Who viewed, exported or changed what: report views and exports, threshold changes, overrides, deletions and API key changes, newest first
+// @Tags admin
+// @Produce json
+// @Param actor_id query string false "Actor ID (user or API key UUID)"
+// @Param action query string false "Action, e.g. report.view, report.export, api_key.create"
+// @Param resource_type query string false "Resource type, e.g. work, assignment, api_key"
+// @Param resource_id query string false "Resource ID"
+// @Param from query string false "From (RFC 3339, inclusive)"
+// @Param to query string false "To (RFC 3339, exclusive)"
+// @Param limit query int false "Page size (default 100, max 1000)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} dto.AuditLogResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/audit [get]
+func getAuditLogHandler(auditLog *service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := service.ParseAuditFilter(c.Request.URL.Query())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: err.Error()})
+			return
+		}
+
+		entries, err := auditLog.Query(c.Request.Context(), filter)
+		if err != nil {
+			log.Printf("Audit log query failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to query audit log"})
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.AuditLogResponse{Success: true, Data: entries, Timestamp: time.Now()})
+	}
+}
+
+// VerifyAuditLog godoc
+// @Summary Verify the audit log hash chain
+// @Description Recompute the hash chain over the whole log and report the first entry that was changed, removed or reordered
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.AuditVerificationResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/audit/verify [get]
+func verifyAuditLogHandler(auditLog *service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := auditLog.Verify(c.Request.Context())
+		if err != nil {
+			log.Printf("Audit log verification failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to verify audit log"})
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.AuditVerificationResponse{Success: true, Data: *result, Timestamp: time.Now()})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/apikey"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/jwt"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+)
+
+// authRequired проверяет API-ключ из X-API-Key или JWT на входе в систему.
+// Права на конкретную работу дополнительно проверяет Analysis Service, которому
+// шлюз передает те же заголовки.
+func authRequired(verifier *jwt.Verifier, keys *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(middleware.APIKeyHeader); key != "" {
+			p, err := keys.Authenticate(c.Request.Context(), key)
+			if err != nil {
+				if errors.Is(err, apikey.ErrInvalidKey) || errors.Is(err, apikey.ErrRevoked) || errors.Is(err, apikey.ErrExpired) {
+					c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Success: false, Error: "Invalid API key: " + err.Error()})
+					return
+				}
+				log.Printf("API key check failed: %v", err)
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to check API key"})
+				return
+			}
+			middleware.SetPrincipal(c, p)
+			c.Next()
+			return
+		}
+
+		header := c.GetHeader("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Success: false, Error: "Missing bearer token"})
+			return
+		}
+
+		claims, err := verifier.Verify(strings.TrimSpace(strings.TrimPrefix(header, "Bearer ")))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Success: false, Error: "Invalid token: " + err.Error()})
+			return
+		}
+		p, err := claims.Principal()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Success: false, Error: "Invalid token: " + err.Error()})
+			return
+		}
+
+		middleware.SetPrincipal(c, p)
+		c.Next()
+	}
+}
+
+func requireRole(roles ...auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := principal(c)
+		for _, r := range roles {
+			if p != nil && p.Role == r {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Success: false, Error: "Insufficient role"})
+	}
+}
+
+// requireScope ограничивает API-ключи их областями; пользователей с JWT не затрагивает.
+func requireScope(scope auth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p := principal(c); p == nil || !p.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Success: false, Error: "API key lacks scope " + string(scope)})
+			return
+		}
+		c.Next()
+	}
+}
+
+func principal(c *gin.Context) *auth.Principal {
+	return middleware.PrincipalFrom(c)
+}
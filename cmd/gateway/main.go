@@ -1,49 +1,123 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"log"
-	"mime/multipart"
 	"net/http"
+	"strconv"
 	"time"
 
+	analysisv1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/analysis/v1"
+	storagev1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/storage/v1"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/audit"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/jwt"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/persistence/postgres"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/signing"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/rpc"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/pkg/config"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	_ "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/docs"
 )
 
 const (
-	StorageServiceURL  = "http://storage:9091"
-	AnalysisServiceURL = "http://analysis:9092"
+	// Загрузка файлов, проверка, отчеты и облако слов идут по gRPC (api/proto);
+	// стилометрия и поток хода проверки — по HTTP.
+	StorageServiceAddr  = "storage:9091"
+	AnalysisServiceAddr = "analysis:9093"
+	AnalysisServiceURL  = "http://analysis:9092"
 )
 
-type AnalysisResponse struct {
-	Score               float64 `json:"score"`
-	SimilarityScore     float64 `json:"similarity_score"`
-	IsPlagiarized       bool    `json:"is_plagiarized"`
-	IsPlagiarizedCamel  bool    `json:"isPlagiarized"`
-	IsPlagiarizedPascal bool    `json:"IsPlagiarized"`
-}
+const (
+	// analysisTimeout ограничивает проверку, результат которой ждет клиент.
+	analysisTimeout = 30 * time.Second
+	// backgroundAnalysisTimeout ограничивает проверку, запущенную без ожидания результата.
+	backgroundAnalysisTimeout = 10 * time.Minute
+)
+
+var (
+	// internalClient подписывает запросы к внутренним сервисам ключом шлюза.
+	internalClient *http.Client
+	// streamClient — тот же клиент без общего таймаута для потоков; их длительность
+	// ограничивает контекст запроса.
+	streamClient *http.Client
+
+	storageRPC  storagev1.StorageServiceClient
+	analysisRPC analysisv1.AnalysisServiceClient
+)
 
 // @title HSE KPO Antiplague Gateway API
 // @version 1.0
 // @description API Gateway for the Distributed Plagiarism Detection System
 // @host localhost:9090
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT in the form "Bearer <token>"
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key issued by an administrator (ak_...)
 func main() {
+	cfg := config.LoadConfig()
+
+	// База нужна шлюзу только для API-ключей, аудита и подписок на вебхуки: работы и отчеты
+	// остаются за внутренними сервисами.
+	db, err := postgres.NewConnection(postgres.Config{
+		Host: cfg.DBHost, Port: cfg.DBPort, User: cfg.DBUser,
+		Password: cfg.DBPassword, DBName: cfg.DBName, SSLMode: cfg.DBSSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Gateway: DB Connection failed: %v", err)
+	}
+	if _, err := postgres.Migrate(context.Background(), db); err != nil {
+		log.Fatalf("Gateway: migrations failed: %v", err)
+	}
+	apiKeys := service.NewAPIKeyService(postgres.NewAPIKeyRepository(db))
+	auditLog := service.NewAuditService(postgres.NewAuditRepository(db))
+	webhooks := service.NewWebhookService(postgres.NewWebhookRepository(db))
+
+	verifier, err := jwt.LoadVerifier(cfg.JWTSecret, cfg.JWTPublicKeyFile, cfg.JWTIssuer)
+	if err != nil {
+		log.Fatalf("Gateway: failed to configure authentication: %v", err)
+	}
+	signer, err := signing.NewSigner(cfg.ServiceKeyID, []byte(cfg.ServiceKeySecret))
+	if err != nil {
+		log.Fatalf("Gateway: failed to configure request signing: %v", err)
+	}
+	internalClient = signer.Client(30 * time.Second)
+	streamClient = signer.Client(0)
+
+	storageConn, err := rpc.Dial(StorageServiceAddr, signer)
+	if err != nil {
+		log.Fatalf("Gateway: failed to connect to storage: %v", err)
+	}
+	storageRPC = storagev1.NewStorageServiceClient(storageConn)
+	analysisConn, err := rpc.Dial(AnalysisServiceAddr, signer)
+	if err != nil {
+		log.Fatalf("Gateway: failed to connect to analysis: %v", err)
+	}
+	analysisRPC = analysisv1.NewAnalysisServiceClient(analysisConn)
+
 	r := gin.Default()
+	r.Use(middleware.RequestID())
 
 	// Настройка CORS (опционально, но полезно для фронтенда)
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -53,10 +127,27 @@ func main() {
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	api := r.Group("/api/v1")
+	api := r.Group("/api/v1", authRequired(verifier, apiKeys))
 	{
-		api.POST("/works", submitWorkHandler)
-		api.GET("/works/:work_id/wordcloud", getWordCloudHandler)
+		api.POST("/works", requireScope(auth.ScopeWorksSubmit), submitWorkHandler)
+		staff := requireRole(auth.RoleTeacher, auth.RoleAdmin)
+		analytics := requireScope(auth.ScopeAnalyticsRead)
+		api.GET("/works/:work_id/wordcloud", staff, analytics, getWordCloudHandler)
+		api.GET("/works/:work_id/stylometry", staff, analytics, getStylometryHandler)
+		api.GET("/works/:work_id/events", requireScope(auth.ScopeReportsRead), getWorkEventsHandler)
+		api.GET("/works/:work_id/reports", requireScope(auth.ScopeReportsRead), middleware.Audit(auditLog.Record, audit.ActionReportView, "work", "work_id"), getWorkReportHandler)
+
+		admin := api.Group("/admin", requireRole(auth.RoleAdmin), requireScope(auth.ScopeAdmin))
+		admin.POST("/api-keys", middleware.Audit(auditLog.Record, audit.ActionAPIKeyCreate, "api_key"), createAPIKeyHandler(apiKeys))
+		admin.GET("/api-keys", listAPIKeysHandler(apiKeys))
+		admin.DELETE("/api-keys/:key_id", middleware.Audit(auditLog.Record, audit.ActionAPIKeyRevoke, "api_key", "key_id"), revokeAPIKeyHandler(apiKeys))
+		admin.GET("/audit", getAuditLogHandler(auditLog))
+		admin.GET("/audit/verify", verifyAuditLogHandler(auditLog))
+		admin.POST("/webhooks", middleware.Audit(auditLog.Record, audit.ActionWebhookCreate, "webhook"), createWebhookHandler(webhooks))
+		admin.GET("/webhooks", listWebhooksHandler(webhooks))
+		admin.DELETE("/webhooks/:webhook_id", middleware.Audit(auditLog.Record, audit.ActionWebhookDelete, "webhook", "webhook_id"), deleteWebhookHandler(webhooks))
+		admin.GET("/webhooks/:webhook_id/deliveries", listWebhookDeliveriesHandler(webhooks))
+		admin.POST("/webhooks/:webhook_id/deliveries/:delivery_id/replay", replayWebhookDeliveryHandler(webhooks))
 	}
 
 	log.Println("🚀 Gateway Service running on :9090")
@@ -74,9 +165,14 @@ func main() {
 // @Param assignment_id formData string true "Assignment ID"
 // @Param student_id formData string true "Student ID"
 // @Param file formData file true "Work file"
+// @Param async formData bool false "Do not wait for the check: respond right after upload and follow events_url"
 // @Success 202 {object} dto.SubmitWorkResponse "Успешная проверка"
 // @Failure 400 {object} dto.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} dto.ErrorResponse "Нет или неверный токен"
+// @Failure 403 {object} dto.ErrorResponse "Сдача работы за другого студента"
 // @Failure 503 {object} dto.ErrorResponse "Сервис недоступен"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/works [post]
 func submitWorkHandler(c *gin.Context) {
 	fileHeader, err := c.FormFile("file")
@@ -92,6 +188,17 @@ func submitWorkHandler(c *gin.Context) {
 		return
 	}
 
+	studentUUID, errStudent := uuid.Parse(studentID)
+	assignmentUUID, errAssignment := uuid.Parse(assignmentID)
+	if errStudent != nil || errAssignment != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "assignment_id and student_id must be UUIDs"})
+		return
+	}
+	if !principal(c).CanSubmitAs(studentUUID, assignmentUUID) {
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Success: false, Error: "Cannot submit work on behalf of another student"})
+		return
+	}
+
 	file, err := fileHeader.Open()
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Failed to open file"})
@@ -99,42 +206,51 @@ func submitWorkHandler(c *gin.Context) {
 	}
 	defer file.Close()
 
-	storageResp, err := uploadToStorage(file, fileHeader.Filename, assignmentID, studentID)
+	stored, err := rpc.UploadFile(c.Request.Context(), storageRPC, fileHeader.Filename, fileHeader.Header.Get("Content-Type"), file)
 	if err != nil {
 		log.Printf("Storage upload failed: %v", err)
 		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Storage service unavailable"})
 		return
 	}
 
-	analysisResp, err := sendToAnalysis(storageResp["file_id"].(string), assignmentID, storageResp["file_name"].(string))
-
+	workID := stored.FileId
 	response := dto.SubmitWorkResponse{
 		Success: true,
 		Data: dto.WorkResponseData{
-			WorkID:      storageResp["file_id"].(string),
+			WorkID:      workID,
 			SubmittedAt: time.Now(),
 			PlagiarismCheck: dto.PlagiarismInfo{
 				Status: "pending",
 				Score:  0,
 			},
+			EventsURL: "/api/v1/works/" + workID + "/events",
 		},
 		Timestamp: time.Now(),
 	}
 
+	if async, _ := strconv.ParseBool(c.PostForm("async")); async {
+		// Проверка переживает ответ клиенту, поэтому не привязана к контексту запроса.
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), backgroundAnalysisTimeout)
+			defer cancel()
+			if _, err := sendToAnalysis(ctx, workID, assignmentID, studentID, stored.FileName); err != nil {
+				log.Printf("Background analysis of work %s failed: %v", workID, err)
+			}
+		}()
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), analysisTimeout)
+	defer cancel()
+	report, err := sendToAnalysis(ctx, workID, assignmentID, studentID, stored.FileName)
 	if err != nil {
 		log.Printf("Analysis failed: %v", err)
 	} else {
-		finalScore := analysisResp.Score
-		if analysisResp.SimilarityScore > finalScore {
-			finalScore = analysisResp.SimilarityScore
-		}
-
-		isPlag := analysisResp.IsPlagiarized || analysisResp.IsPlagiarizedCamel || analysisResp.IsPlagiarizedPascal
-
 		response.Data.PlagiarismCheck = dto.PlagiarismInfo{
 			Status:        "checked",
-			Score:         finalScore,
-			IsPlagiarized: isPlag,
+			Score:         report.Score,
+			IsPlagiarized: report.IsPlagiarized,
 		}
 	}
 
@@ -143,80 +259,205 @@ func submitWorkHandler(c *gin.Context) {
 
 // GenerateWordCloud godoc
 // @Summary Generate Word Cloud
-// @Description Get a URL to the word cloud image generated from the work text
+// @Description Render a word cloud of the work text in-process (stopwords removed); no text leaves the system
 // @Tags works
-// @Produce json
+// @Produce image/svg+xml
+// @Produce image/png
 // @Param work_id path string true "Work ID"
-// @Success 200 {object} dto.WordCloudResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Param format query string false "svg (default) or png"
+// @Success 200 {file} file "Word cloud image"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/works/{work_id}/wordcloud [get]
 func getWordCloudHandler(c *gin.Context) {
+	var format analysisv1.ImageFormat
+	switch c.DefaultQuery("format", "svg") {
+	case "svg":
+		format = analysisv1.ImageFormat_IMAGE_FORMAT_SVG
+	case "png":
+		format = analysisv1.ImageFormat_IMAGE_FORMAT_PNG
+	default:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid work_id or format"})
+		return
+	}
+
+	resp, err := analysisRPC.WordCloud(forwardUser(c), &analysisv1.WordCloudRequest{WorkId: c.Param("work_id"), Format: format})
+	if err != nil {
+		respondRPCError(c, err, "Failed to generate word cloud")
+		return
+	}
+
+	c.Data(http.StatusOK, resp.ContentType, resp.Image)
+}
+
+// GetStylometry godoc
+// @Summary Stylometric profile of a work
+// @Description Sentence lengths, type/token ratio, function words, punctuation and readability (RU/EN), compared with the student's earlier works to flag unusual drift
+// @Tags works
+// @Produce json
+// @Param work_id path string true "Work ID"
+// @Success 200 {object} object "Profile, baseline works and drift"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/works/{work_id}/stylometry [get]
+func getStylometryHandler(c *gin.Context) {
 	workID := c.Param("work_id")
 
-	url := fmt.Sprintf("%s/internal/analyze/%s/wordcloud", AnalysisServiceURL, workID)
-	resp, err := http.Get(url)
+	url := fmt.Sprintf("%s/internal/analyze/%s/stylometry", AnalysisServiceURL, workID)
+	resp, err := forwardGet(c, internalClient, url)
 	if err != nil {
 		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Analysis service unavailable"})
 		return
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to generate word cloud"})
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid work_id"})
+		return
+	case http.StatusUnauthorized, http.StatusForbidden:
+		c.JSON(resp.StatusCode, dto.ErrorResponse{Success: false, Error: "No access to this work"})
+		return
+	case http.StatusNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Success: false, Error: "Work not found"})
+		return
+	default:
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to analyze work style"})
 		return
 	}
 
-	body, _ := io.ReadAll(resp.Body)
-	c.Data(http.StatusOK, "application/json", body)
+	c.DataFromReader(http.StatusOK, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, nil)
 }
 
-func uploadToStorage(file multipart.File, filename, assignmentID, studentID string) (map[string]interface{}, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	part, _ := writer.CreateFormFile("file", filename)
-	io.Copy(part, file)
-	writer.WriteField("assignment_id", assignmentID)
-	writer.WriteField("student_id", studentID)
-	writer.Close()
+// GetWorkEvents godoc
+// @Summary Live progress of a work check
+// @Description Server-Sent Events stream: queued, extracting, comparing (done/total works), then completed with the final score or failed. Each event's data is JSON; the stream closes after completed or failed. A check that has already finished yields a single completed event
+// @Tags works
+// @Produce text/event-stream
+// @Param work_id path string true "Work ID"
+// @Success 200 {string} string "event: comparing\ndata: {\"work_id\":\"...\",\"stage\":\"comparing\",\"done\":3,\"total\":10,...}"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/works/{work_id}/events [get]
+func getWorkEventsHandler(c *gin.Context) {
+	workID := c.Param("work_id")
 
-	resp, err := http.Post(StorageServiceURL+"/internal/files", writer.FormDataContentType(), body)
+	url := fmt.Sprintf("%s/internal/analyze/%s/events", AnalysisServiceURL, workID)
+	resp, err := forwardGet(c, streamClient, url)
 	if err != nil {
-		return nil, err
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Analysis service unavailable"})
+		return
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 && resp.StatusCode != 201 {
-		return nil, fmt.Errorf("storage returned %d", resp.StatusCode)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid work_id"})
+		return
+	case http.StatusUnauthorized, http.StatusForbidden:
+		c.JSON(resp.StatusCode, dto.ErrorResponse{Success: false, Error: "No access to this work"})
+		return
+	case http.StatusNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Success: false, Error: "Work not found"})
+		return
+	default:
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to follow work check"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	// Каждый прочитанный фрагмент сразу отправляется клиенту: буферизация задержала бы
+	// события до закрытия потока.
+	buf := make([]byte, 4096)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+		if err != nil {
+			return
+		}
 	}
+}
 
-	var res map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&res)
-	return res, nil
+// forwardUser передает токен пользователя или API-ключ в вызов gRPC: Analysis Service
+// сам проверяет доступ к работе.
+func forwardUser(c *gin.Context) context.Context {
+	return rpc.ForwardUser(c.Request.Context(), c.GetHeader("Authorization"), c.GetHeader(middleware.APIKeyHeader), middleware.RequestIDFrom(c))
 }
 
-func sendToAnalysis(fileID, assignmentID, filename string) (*AnalysisResponse, error) {
-	reqBody := map[string]string{
-		"work_id":       fileID,
-		"file_id":       fileID,
-		"assignment_id": assignmentID,
-		"filename":      filename,
+// respondRPCError переводит статус gRPC внутреннего сервиса в ответ шлюза.
+func respondRPCError(c *gin.Context, err error, fallback string) {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid work_id"})
+	case codes.Unauthenticated:
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Success: false, Error: "No access to this work"})
+	case codes.PermissionDenied:
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Success: false, Error: "No access to this work"})
+	case codes.NotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Success: false, Error: status.Convert(err).Message()})
+	case codes.Unavailable:
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Analysis service unavailable"})
+	default:
+		log.Printf("%s: %v", fallback, err)
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: fallback})
 	}
-	jsonValue, _ := json.Marshal(reqBody)
+}
 
-	resp, err := http.Post(AnalysisServiceURL+"/internal/analyze", "application/json", bytes.NewBuffer(jsonValue))
+// forwardGet передает токен пользователя или API-ключ дальше: Analysis Service сам
+// проверяет, ведет ли преподаватель задание, к которому относится работа.
+func forwardGet(c *gin.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("analysis returned %d", resp.StatusCode)
+	req.Header.Set("Authorization", c.GetHeader("Authorization"))
+	if key := c.GetHeader(middleware.APIKeyHeader); key != "" {
+		req.Header.Set(middleware.APIKeyHeader, key)
 	}
+	req.Header.Set(middleware.RequestIDHeader, middleware.RequestIDFrom(c))
+	return client.Do(req)
+}
 
-	var res AnalysisResponse
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+// sendToAnalysis запускает проверку; Analysis Service сохраняет работу от имени studentID,
+// если видит ее впервые.
+func sendToAnalysis(ctx context.Context, workID, assignmentID, studentID, filename string) (*analysisv1.Report, error) {
+	resp, err := analysisRPC.Analyze(ctx, &analysisv1.AnalyzeRequest{
+		WorkId:       workID,
+		FileId:       workID,
+		AssignmentId: assignmentID,
+		StudentId:    studentID,
+		Filename:     filename,
+	})
+	if err != nil {
 		return nil, err
 	}
-	return &res, nil
+	return resp.Report, nil
 }
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	analysisv1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/analysis/v1"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/dto"
+)
+
+// GetWorkReport godoc
+// @Summary Plagiarism report of a work
+// @Description Latest report of the work, in the same envelope as the monolith. The author of the work gets the status, verdict and score only; the teacher of the assignment also gets the matched work and token statistics
+// @Tags works
+// @Produce json
+// @Param work_id path string true "Work ID"
+// @Success 200 {object} dto.WorkReportResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/works/{work_id}/reports [get]
+func getWorkReportHandler(c *gin.Context) {
+	resp, err := analysisRPC.GetReport(forwardUser(c), &analysisv1.GetReportRequest{WorkId: c.Param("work_id")})
+	if err != nil {
+		respondRPCError(c, err, "Failed to load report")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.WorkReportResponse{Success: true, Data: reportData(resp.Report), Timestamp: time.Now()})
+}
+
+// reportData переводит отчет из gRPC в формат монолита. Подробности Analysis Service
+// отдает только при полном доступе; без них автор работы получает краткий итог.
+func reportData(r *analysisv1.Report) interface{} {
+	workID, _ := uuid.Parse(r.WorkId)
+	createdAt := r.CreatedAt.AsTime().UTC().Format(time.RFC3339)
+	d := r.Details
+	if d == nil {
+		return service.ReportSummary{
+			WorkID:          workID,
+			Status:          r.Status,
+			IsPlagiarized:   r.IsPlagiarized,
+			SimilarityScore: r.Score,
+			CreatedAt:       createdAt,
+		}
+	}
+
+	data := service.ReportResponse{
+		WorkID:          workID,
+		Status:          r.Status,
+		IsPlagiarized:   r.IsPlagiarized,
+		SimilarityScore: r.Score,
+		CreatedAt:       createdAt,
+		Details: plagiarism.AnalysisDetails{
+			AlgorithmUsed:       d.Algorithm,
+			MatchedTokens:       int(d.MatchedTokens),
+			TotalTokens:         int(d.TotalTokens),
+			Coverage:            d.Coverage,
+			InsufficientContent: d.InsufficientContent,
+		},
+	}
+	if matched, err := uuid.Parse(r.MatchedWorkId); err == nil {
+		data.MatchedWorkID = &matched
+	}
+	return data
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+)
+
+// CreateWebhook godoc
+// @Summary Create a webhook subscription
+// @Description Register a URL to receive report.created, report.flagged and review.updated events. Deliveries are signed with HMAC-SHA256 using the secret, which is returned only in this response
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body service.CreateWebhookRequest true "Name, URL, events, optional secret and assignment"
+// @Success 201 {object} dto.CreatedWebhookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/webhooks [post]
+func createWebhookHandler(webhooks *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req service.CreateWebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid request body: " + err.Error()})
+			return
+		}
+
+		hook, err := webhooks.Create(c.Request.Context(), req, principal(c).UserID)
+		if err != nil {
+			if errors.Is(err, shared.ErrInvalidInput) {
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: err.Error()})
+				return
+			}
+			log.Printf("Webhook creation failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to create webhook"})
+			return
+		}
+
+		middleware.SetAuditResource(c, hook.ID.String())
+		c.JSON(http.StatusCreated, dto.CreatedWebhookResponse{Success: true, Data: *hook, Timestamp: time.Now()})
+	}
+}
+
+// ListWebhooks godoc
+// @Summary List webhook subscriptions
+// @Description List all subscriptions with their events; secrets are never returned
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.WebhookListResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/webhooks [get]
+func listWebhooksHandler(webhooks *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		list, err := webhooks.List(c.Request.Context())
+		if err != nil {
+			log.Printf("Webhook listing failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to list webhooks"})
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.WebhookListResponse{Success: true, Data: list, Timestamp: time.Now()})
+	}
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook subscription
+// @Description Stop sending events to the URL. Pending deliveries are cancelled; the delivery log is kept
+// @Tags admin
+// @Param webhook_id path string true "Webhook ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/webhooks/{webhook_id} [delete]
+func deleteWebhookHandler(webhooks *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhookID, err := uuid.Parse(c.Param("webhook_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid webhook_id"})
+			return
+		}
+
+		if err := webhooks.Delete(c.Request.Context(), webhookID); err != nil {
+			if errors.Is(err, shared.ErrNotFound) {
+				c.JSON(http.StatusNotFound, dto.ErrorResponse{Success: false, Error: "Webhook not found"})
+				return
+			}
+			log.Printf("Webhook deletion failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to delete webhook"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ListWebhookDeliveries godoc
+// @Summary Webhook delivery log
+// @Description Deliveries of a subscription, newest first: status, attempts, last response and the next retry time
+// @Tags admin
+// @Produce json
+// @Param webhook_id path string true "Webhook ID"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Success 200 {object} dto.WebhookDeliveryListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/webhooks/{webhook_id}/deliveries [get]
+func listWebhookDeliveriesHandler(webhooks *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhookID, err := uuid.Parse(c.Param("webhook_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid webhook_id"})
+			return
+		}
+		limit := 0
+		if raw := c.Query("limit"); raw != "" {
+			if limit, err = strconv.Atoi(raw); err != nil || limit < 1 {
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "limit must be a positive integer"})
+				return
+			}
+		}
+
+		list, err := webhooks.Deliveries(c.Request.Context(), webhookID, limit)
+		if err != nil {
+			log.Printf("Webhook delivery listing failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to list deliveries"})
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.WebhookDeliveryListResponse{Success: true, Data: list, Timestamp: time.Now()})
+	}
+}
+
+// ReplayWebhookDelivery godoc
+// @Summary Replay a webhook delivery
+// @Description Queue the same event again as a new delivery, e.g. after the receiver was fixed
+// @Tags admin
+// @Produce json
+// @Param webhook_id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 202 {object} dto.WebhookDeliveryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/webhooks/{webhook_id}/deliveries/{delivery_id}/replay [post]
+func replayWebhookDeliveryHandler(webhooks *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhookID, err := uuid.Parse(c.Param("webhook_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid webhook_id"})
+			return
+		}
+		deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: "Invalid delivery_id"})
+			return
+		}
+
+		delivery, err := webhooks.Replay(c.Request.Context(), webhookID, deliveryID)
+		if err != nil {
+			switch {
+			case errors.Is(err, shared.ErrNotFound):
+				c.JSON(http.StatusNotFound, dto.ErrorResponse{Success: false, Error: "Delivery not found"})
+			case errors.Is(err, shared.ErrInvalidInput):
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Success: false, Error: err.Error()})
+			default:
+				log.Printf("Webhook replay failed: %v", err)
+				c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Success: false, Error: "Failed to replay delivery"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusAccepted, dto.WebhookDeliveryResponse{Success: true, Data: *delivery, Timestamp: time.Now()})
+	}
+}
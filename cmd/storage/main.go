@@ -1,18 +1,17 @@
 package main
 
 import (
-	"io"
 	"log"
-	"net/http"
+	"net"
 	"os"
 
-	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 
-	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/file"
+	storagev1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/storage/v1"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/persistence/postgres"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/signing"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/storage/local"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/rpc"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/pkg/config"
 )
 
@@ -36,82 +35,21 @@ func main() {
 	}
 	fileStorage := local.NewLocalFileStorage(cfg.FileStoragePath)
 
-	r := gin.Default()
-
-	r.POST("/internal/upload", func(c *gin.Context) {
-		uploadHandler(c, fileRepo, fileStorage)
-	})
-
-	r.GET("/internal/files/:file_id/content", func(c *gin.Context) {
-		downloadHandler(c, fileRepo, fileStorage)
-	})
-
-	port := ":9091"
-	log.Printf("💾 Storage Service running on %s", port)
-	r.Run(port)
-}
-
-func uploadHandler(c *gin.Context, repo file.Repository, storage file.Storage) {
-	header, err := c.FormFile("file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No file"})
-		return
-	}
-
-	f, err := header.Open()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open file"})
-		return
-	}
-	defer f.Close()
-
-	tempFileEntity := file.NewFile(header.Filename, "", header.Header.Get("Content-Type"), "", header.Size)
-
-	path, err := storage.Upload(c.Request.Context(), tempFileEntity.ID, f)
+	services, err := signing.LoadVerifier(cfg.TrustedServiceKeys)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload to disk"})
-		return
+		log.Fatalf("Storage Service: failed to configure service authentication: %v", err)
 	}
 
-	tempFileEntity.StoragePath = path
+	server := rpc.NewServer(services)
+	storagev1.RegisterStorageServiceServer(server, newStorageServer(fileRepo, fileStorage))
 
-	if err := repo.Save(c.Request.Context(), tempFileEntity); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save metadata"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"file_id": tempFileEntity.ID,
-		"path":    path,
-	})
-}
-
-func downloadHandler(c *gin.Context, repo file.Repository, storage file.Storage) {
-	idStr := c.Param("file_id")
-	id, err := uuid.Parse(idStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
-		return
-	}
-
-	fileMeta, err := repo.GetByID(c.Request.Context(), id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "File metadata not found"})
-		return
-	}
-
-	content, err := storage.Download(c.Request.Context(), fileMeta.StoragePath)
+	port := ":9091"
+	lis, err := net.Listen("tcp", port)
 	if err != nil {
-		log.Printf("Failed to read file from disk: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "File content not found"})
-		return
+		log.Fatalf("Storage Service: failed to listen on %s: %v", port, err)
 	}
-	defer content.Close()
-
-	c.Header("Content-Type", fileMeta.MimeType)
-	c.Header("Content-Disposition", "attachment; filename="+fileMeta.OriginalName)
-
-	if _, err := io.Copy(c.Writer, content); err != nil {
-		log.Printf("Failed to stream file: %v", err)
+	log.Printf("💾 Storage Service (gRPC) running on %s", port)
+	if err := server.Serve(lis); err != nil {
+		log.Fatal(err)
 	}
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	storagev1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/storage/v1"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/file"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/rpc"
+)
+
+type storageServer struct {
+	storagev1.UnimplementedStorageServiceServer
+	repo    file.Repository
+	storage file.Storage
+}
+
+func newStorageServer(repo file.Repository, storage file.Storage) *storageServer {
+	return &storageServer{repo: repo, storage: storage}
+}
+
+func (s *storageServer) Upload(stream storagev1.StorageService_UploadServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "No file")
+	}
+	info := first.GetInfo()
+	if info == nil || info.Filename == "" {
+		return status.Error(codes.InvalidArgument, "first message must describe the file")
+	}
+
+	content := &chunkReader{stream: stream}
+	entity := file.NewFile(info.Filename, "", info.ContentType, "", 0)
+
+	path, err := s.storage.Upload(stream.Context(), entity.ID, content)
+	if err != nil {
+		if content.err != nil {
+			return content.err
+		}
+		log.Printf("Failed to upload to disk: %v", err)
+		return status.Error(codes.Internal, "Failed to upload to disk")
+	}
+
+	entity.StoragePath = path
+	entity.Size = content.size
+
+	if err := s.repo.Save(stream.Context(), entity); err != nil {
+		log.Printf("Failed to save metadata: %v", err)
+		return status.Error(codes.Internal, "Failed to save metadata")
+	}
+
+	return stream.SendAndClose(&storagev1.UploadResponse{
+		FileId:   entity.ID.String(),
+		FileName: entity.OriginalName,
+		Size:     entity.Size,
+	})
+}
+
+func (s *storageServer) Download(req *storagev1.DownloadRequest, stream storagev1.StorageService_DownloadServer) error {
+	id, err := uuid.Parse(req.FileId)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "Invalid UUID")
+	}
+
+	meta, err := s.repo.GetByID(stream.Context(), id)
+	if err != nil {
+		return status.Error(codes.NotFound, "File metadata not found")
+	}
+
+	content, err := s.storage.Download(stream.Context(), meta.StoragePath)
+	if err != nil {
+		log.Printf("Failed to read file from disk: %v", err)
+		return status.Error(codes.NotFound, "File content not found")
+	}
+	defer content.Close()
+
+	info := &storagev1.FileInfo{Filename: meta.OriginalName, ContentType: meta.MimeType}
+	if err := stream.Send(&storagev1.DownloadResponse{Payload: &storagev1.DownloadResponse_Info{Info: info}}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, rpc.ChunkSize)
+	for {
+		n, err := content.Read(buf)
+		if n > 0 {
+			chunk := append([]byte(nil), buf[:n]...)
+			if err := stream.Send(&storagev1.DownloadResponse{Payload: &storagev1.DownloadResponse_Chunk{Chunk: chunk}}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			log.Printf("Failed to stream file: %v", err)
+			return status.Error(codes.Internal, "Failed to read file")
+		}
+	}
+}
+
+// chunkReader читает содержимое файла из потока Upload по мере поступления частей.
+type chunkReader struct {
+	stream storagev1.StorageService_UploadServer
+	buf    []byte
+	size   int64
+	// err — ошибка клиента (неверное сообщение), которую нужно вернуть вместо ошибки записи.
+	err error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		chunk, ok := msg.Payload.(*storagev1.UploadRequest_Chunk)
+		if !ok {
+			r.err = status.Error(codes.InvalidArgument, "file description must be sent once, before the content")
+			return 0, r.err
+		}
+		r.buf = chunk.Chunk
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	r.size += int64(n)
+	return n, nil
+}
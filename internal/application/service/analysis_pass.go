@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+// analysisPass проверяет отобранные работы задания против всех остальных за один проход:
+// тексты загружаются один раз, а пара из двух отобранных работ сравнивается один раз.
+// Общий для пакетной загрузки и повторного анализа.
+type analysisPass struct {
+	texts    workTextLoader
+	detector plagiarism.Detector
+	policy   plagiarism.Policy
+	reason   string
+	// counterparts, если задан, дописывает найденные совпадения в отчеты неотобранных работ.
+	counterparts *CounterpartUpdater
+}
+
+// run строит отчет для каждой работы из works, для которой selected вернул true, и
+// передает его в save по мере готовности. Ошибка save прерывает проход.
+func (p analysisPass) run(
+	ctx context.Context,
+	works []*work.Work,
+	selected func(w *work.Work) bool,
+	save func(r *plagiarism.Report, w *work.Work) error,
+) error {
+	ids := make([]uuid.UUID, len(works))
+	texts := make([]string, len(works))
+	picked := make([]bool, len(works))
+	for i, w := range works {
+		ids[i] = w.ID
+		picked[i] = selected(w)
+		text, err := p.texts.Load(ctx, w)
+		if err != nil {
+			// Как при одиночной загрузке: работа без текста получает нулевую оценку.
+			log.Printf("Analysis pass: no text for work %s: %v", w.ID, err)
+		}
+		texts[i] = text
+	}
+
+	matrix := plagiarism.NewSimilarityMatrix(ids)
+	// contained[i][j] — доля шинглов работы i, найденных в работе j; в отличие от оценки
+	// она несимметрична.
+	contained := make([][]float64, len(works))
+	for i := range contained {
+		contained[i] = make([]float64, len(works))
+	}
+	for i := range works {
+		if !picked[i] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if tokens := p.detector.CountTokens(texts[i]); !p.detector.Sufficient(tokens) {
+			report := plagiarism.NewInsufficientReport(ids[i], tokens, p.detector.Exclusions(texts[i]), p.policy)
+			report.Reason = p.reason
+			if err := save(report, works[i]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for j := range works {
+			// Пара из двух отобранных работ уже посчитана со стороны меньшего индекса.
+			if j == i || (picked[j] && j < i) {
+				continue
+			}
+			overlap, err := p.detector.Overlap(texts[i], texts[j])
+			if err != nil {
+				return fmt.Errorf("failed to compare works: %w", err)
+			}
+			matrix.Set(i, j, overlap.Similarity)
+			contained[i][j] = overlap.Containment
+			contained[j][i] = overlap.MatchedContainment
+		}
+
+		best := -1
+		var matches []plagiarism.Match
+		for j := range works {
+			if j == i || matrix.Scores[i][j] <= 0 {
+				continue
+			}
+			matches = append(matches, plagiarism.NewMatch(ids[j], matrix.Scores[i][j], contained[i][j], works[i].SubmittedAt, works[j].SubmittedAt))
+			if best < 0 || matrix.Scores[i][j] > matrix.Scores[i][best] {
+				best = j
+			}
+		}
+
+		var matchText string
+		var maxScore float64
+		if best >= 0 {
+			matchText = texts[best]
+			maxScore = matrix.Scores[i][best]
+		}
+		details := plagiarism.NewAnalysisDetails(
+			p.detector.CountTokens(texts[i]),
+			p.detector.Passages(texts[i], matchText),
+			p.detector.Exclusions(texts[i]),
+		)
+
+		report := plagiarism.NewReport(ids[i], maxScore, p.policy)
+		report.Reason = p.reason
+		report.Details = details
+		if best >= 0 {
+			report.SetMatch(ids[best], details)
+		}
+		report.SetMatches(matches)
+		if err := save(report, works[i]); err != nil {
+			return err
+		}
+	}
+
+	if p.counterparts != nil {
+		p.updateCounterparts(ctx, works, texts, picked, matrix, contained)
+	}
+	return nil
+}
+
+// updateCounterparts добавляет совпадения с отобранными работами в отчеты остальных работ
+// задания. Отчеты отобранных уже сохранены, поэтому ошибки только логируются.
+func (p analysisPass) updateCounterparts(ctx context.Context, works []*work.Work, texts []string, picked []bool, matrix *plagiarism.SimilarityMatrix, contained [][]float64) {
+	for j, w := range works {
+		if picked[j] {
+			continue
+		}
+		var found []counterpartMatch
+		for i := range works {
+			if picked[i] && matrix.Scores[i][j] > 0 {
+				found = append(found, counterpartMatch{
+					match: plagiarism.NewMatch(works[i].ID, matrix.Scores[i][j], contained[j][i], w.SubmittedAt, works[i].SubmittedAt),
+					text:  texts[i],
+				})
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+		if err := p.counterparts.update(ctx, w, texts[j], found); err != nil {
+			log.Printf("Analysis pass: failed to update report of work %s: %v", w.ID, err)
+		}
+	}
+}
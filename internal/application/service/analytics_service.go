@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/analytics"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/file"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+// TextLoader загружает текст работы.
+type TextLoader interface {
+	Load(ctx context.Context, w *work.Work) (string, error)
+}
+
+type AnalyticsService struct {
+	workRepo work.Repository
+	texts    TextLoader
+	detector plagiarism.Detector
+}
+
+func NewAnalyticsService(
+	wr work.Repository,
+	fr file.Repository,
+	fs file.Storage,
+	te file.TextExtractor,
+	det plagiarism.Detector,
+) *AnalyticsService {
+	return NewAnalyticsServiceWithTexts(wr, workTextLoader{fileRepo: fr, fileStorage: fs, textExtractor: te}, det)
+}
+
+// NewAnalyticsServiceWithTexts — аналитика поверх своего источника текстов: Analysis Service
+// читает файлы из Storage Service, а не из локального хранилища.
+func NewAnalyticsServiceWithTexts(wr work.Repository, texts TextLoader, det plagiarism.Detector) *AnalyticsService {
+	return &AnalyticsService{
+		workRepo: wr,
+		texts:    texts,
+		detector: det,
+	}
+}
+
+// WordFrequencies возвращает limit самых частых значимых слов работы.
+func (s *AnalyticsService) WordFrequencies(ctx context.Context, workID uuid.UUID, limit int) ([]analytics.WordCount, error) {
+	w, err := s.workRepo.GetByID(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := s.texts.Load(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+
+	return analytics.Frequencies(text, analytics.MinWordLen, limit), nil
+}
+
+// maxBaselineWorks ограничивает число прежних работ в базе сравнения: стиль меняется
+// со временем, и работы многолетней давности описывают его хуже последних.
+const maxBaselineWorks = 10
+
+type BaselineWork struct {
+	WorkID       uuid.UUID `json:"work_id"`
+	AssignmentID uuid.UUID `json:"assignment_id"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+}
+
+type StylometryResponse struct {
+	WorkID    uuid.UUID              `json:"work_id"`
+	StudentID uuid.UUID              `json:"student_id"`
+	Profile   analytics.StyleProfile `json:"profile"`
+	Baseline  []BaselineWork         `json:"baseline"`
+	Drift     analytics.StyleDrift   `json:"drift"`
+}
+
+// Stylometry строит стилометрический профиль работы и сравнивает его с профилями
+// более ранних работ того же студента (до maxBaselineWorks последних).
+func (s *AnalyticsService) Stylometry(ctx context.Context, workID uuid.UUID) (*StylometryResponse, error) {
+	w, err := s.workRepo.GetByID(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := s.texts.Load(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.workRepo.FindByStudentID(ctx, w.StudentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch student works: %w", err)
+	}
+
+	earlier := make([]*work.Work, 0, len(history))
+	for _, h := range history {
+		if h.ID != w.ID && h.SubmittedAt.Before(w.SubmittedAt) {
+			earlier = append(earlier, h)
+		}
+	}
+	sort.Slice(earlier, func(i, j int) bool { return earlier[i].SubmittedAt.After(earlier[j].SubmittedAt) })
+	if len(earlier) > maxBaselineWorks {
+		earlier = earlier[:maxBaselineWorks]
+	}
+
+	baseline := make([]BaselineWork, 0, len(earlier))
+	profiles := make([]analytics.StyleProfile, 0, len(earlier))
+	for _, h := range earlier {
+		t, err := s.texts.Load(ctx, h)
+		if err != nil {
+			fmt.Printf("Skipping work %s in stylometry baseline: %v\n", h.ID, err)
+			continue
+		}
+		baseline = append(baseline, BaselineWork{WorkID: h.ID, AssignmentID: h.AssignmentID, SubmittedAt: h.SubmittedAt})
+		profiles = append(profiles, analytics.AnalyzeStyle(t))
+	}
+
+	profile := analytics.AnalyzeStyle(text)
+	return &StylometryResponse{
+		WorkID:    w.ID,
+		StudentID: w.StudentID,
+		Profile:   profile,
+		Baseline:  baseline,
+		Drift:     analytics.CompareStyle(profile, profiles),
+	}, nil
+}
+
+type WorkKeywords struct {
+	WorkID    uuid.UUID           `json:"work_id"`
+	StudentID uuid.UUID           `json:"student_id"`
+	Keywords  []analytics.Keyword `json:"keywords"`
+}
+
+type AssignmentKeywordsResponse struct {
+	AssignmentID uuid.UUID      `json:"assignment_id"`
+	Works        []WorkKeywords `json:"works"`
+}
+
+type AssignmentVocabularyResponse struct {
+	AssignmentID uuid.UUID                   `json:"assignment_id"`
+	Works        int                         `json:"works"`
+	Vocabulary   []analytics.VocabularyEntry `json:"vocabulary"`
+}
+
+type RarePhraseGroup struct {
+	WorkIDs    []uuid.UUID `json:"work_ids"`
+	StudentIDs []uuid.UUID `json:"student_ids"`
+	Phrases    []string    `json:"phrases"`
+}
+
+type RarePhrasesResponse struct {
+	AssignmentID uuid.UUID         `json:"assignment_id"`
+	PhraseLength int               `json:"phrase_length"`
+	MaxGroup     int               `json:"max_group"`
+	Groups       []RarePhraseGroup `json:"groups"`
+}
+
+// AssignmentKeywords возвращает для каждой работы задания limit слов с наибольшим TF-IDF:
+// слова, частые в этой работе и редкие в остальных.
+func (s *AnalyticsService) AssignmentKeywords(ctx context.Context, assignmentID uuid.UUID, limit int) (*AssignmentKeywordsResponse, error) {
+	works, texts, err := s.loadAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := analytics.NewTermIndex(texts, analytics.MinWordLen)
+	items := make([]WorkKeywords, len(works))
+	for i, w := range works {
+		items[i] = WorkKeywords{WorkID: w.ID, StudentID: w.StudentID, Keywords: index.Keywords(i, limit)}
+	}
+
+	return &AssignmentKeywordsResponse{AssignmentID: assignmentID, Works: items}, nil
+}
+
+// AssignmentVocabulary возвращает общий словарь задания с числом работ, где встречается каждое слово.
+func (s *AnalyticsService) AssignmentVocabulary(ctx context.Context, assignmentID uuid.UUID, limit int) (*AssignmentVocabularyResponse, error) {
+	works, texts, err := s.loadAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AssignmentVocabularyResponse{
+		AssignmentID: assignmentID,
+		Works:        len(works),
+		Vocabulary:   analytics.NewTermIndex(texts, analytics.MinWordLen).Vocabulary(limit),
+	}, nil
+}
+
+// RarePhrases находит n-граммы, общие лишь для небольшой группы работ задания.
+func (s *AnalyticsService) RarePhrases(ctx context.Context, assignmentID uuid.UUID, opts analytics.RarePhraseOptions) (*RarePhrasesResponse, error) {
+	works, texts, err := s.loadAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := analytics.RarePhrases(texts, opts)
+	items := make([]RarePhraseGroup, len(groups))
+	for i, g := range groups {
+		item := RarePhraseGroup{Phrases: g.Phrases}
+		for _, doc := range g.Documents {
+			item.WorkIDs = append(item.WorkIDs, works[doc].ID)
+			item.StudentIDs = append(item.StudentIDs, works[doc].StudentID)
+		}
+		items[i] = item
+	}
+
+	return &RarePhrasesResponse{
+		AssignmentID: assignmentID,
+		PhraseLength: opts.N,
+		MaxGroup:     opts.MaxGroup,
+		Groups:       items,
+	}, nil
+}
+
+// loadAssignment загружает тексты работ задания без цитат и списков литературы:
+// общие источники иначе выглядели бы как общие редкие фразы. Работы, текст которых
+// не удалось получить, пропускаются.
+func (s *AnalyticsService) loadAssignment(ctx context.Context, assignmentID uuid.UUID) ([]*work.Work, []string, error) {
+	all, err := s.workRepo.FindByAssignmentID(ctx, assignmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch works: %w", err)
+	}
+
+	works := make([]*work.Work, 0, len(all))
+	texts := make([]string, 0, len(all))
+	for _, w := range all {
+		text, err := s.texts.Load(ctx, w)
+		if err != nil {
+			fmt.Printf("Skipping work %s in assignment analytics: %v\n", w.ID, err)
+			continue
+		}
+		works = append(works, w)
+		texts = append(texts, stripExcluded(text, s.detector.Exclusions(text)))
+	}
+	return works, texts, nil
+}
+
+// stripExcluded заменяет исключенные фрагменты переводом строки, чтобы слова
+// по обе стороны не склеивались в одну фразу.
+func stripExcluded(text string, excluded []plagiarism.ExcludedRange) string {
+	if len(excluded) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	prev := 0
+	for _, r := range excluded {
+		b.WriteString(text[prev:r.Start])
+		b.WriteString("\n\n")
+		prev = r.End
+	}
+	b.WriteString(text[prev:])
+	return b.String()
+}
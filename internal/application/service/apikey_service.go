@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/apikey"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+type APIKeyService struct {
+	repo apikey.Repository
+	now  func() time.Time
+}
+
+func NewAPIKeyService(repo apikey.Repository) *APIKeyService {
+	return &APIKeyService{repo: repo, now: time.Now}
+}
+
+type CreateAPIKeyRequest struct {
+	Name         string     `json:"name" binding:"required"`
+	Scopes       []string   `json:"scopes" binding:"required,min=1"`
+	AssignmentID *uuid.UUID `json:"assignment_id,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyInfo — описание ключа без секрета.
+type APIKeyInfo struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	Prefix       string     `json:"prefix"`
+	Scopes       []string   `json:"scopes"`
+	AssignmentID *uuid.UUID `json:"assignment_id,omitempty"`
+	CreatedBy    uuid.UUID  `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKey возвращается один раз при создании: после этого ключ восстановить нельзя.
+type CreatedAPIKey struct {
+	APIKeyInfo
+	Key string `json:"key"`
+}
+
+func (s *APIKeyService) Create(ctx context.Context, req CreateAPIKeyRequest, createdBy uuid.UUID) (*CreatedAPIKey, error) {
+	scopes := make([]auth.Scope, 0, len(req.Scopes))
+	for _, raw := range req.Scopes {
+		scope, err := auth.ParseScope(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unknown scope %q", shared.ErrInvalidInput, raw)
+		}
+		scopes = append(scopes, scope)
+	}
+
+	key, token, err := apikey.New(req.Name, scopes, req.AssignmentID, req.ExpiresAt, createdBy, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, key); err != nil {
+		return nil, err
+	}
+	return &CreatedAPIKey{APIKeyInfo: apiKeyInfo(key), Key: token}, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]APIKeyInfo, error) {
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]APIKeyInfo, len(keys))
+	for i, k := range keys {
+		infos[i] = apiKeyInfo(k)
+	}
+	return infos, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id uuid.UUID) (*APIKeyInfo, error) {
+	if err := s.repo.Revoke(ctx, id, s.now()); err != nil {
+		return nil, err
+	}
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	info := apiKeyInfo(key)
+	return &info, nil
+}
+
+// Authenticate проверяет ключ из заголовка и возвращает пользователя, от имени которого он действует.
+func (s *APIKeyService) Authenticate(ctx context.Context, token string) (*auth.Principal, error) {
+	key, err := s.repo.GetByHash(ctx, apikey.HashToken(token))
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			return nil, apikey.ErrInvalidKey
+		}
+		return nil, err
+	}
+
+	now := s.now()
+	if err := key.Check(now); err != nil {
+		return nil, err
+	}
+	// Учет использования не должен ломать запрос: ключ уже проверен.
+	if err := s.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+		fmt.Printf("Failed to update last use of api key %s: %v\n", key.ID, err)
+	}
+	return key.Principal(), nil
+}
+
+func apiKeyInfo(k *apikey.APIKey) APIKeyInfo {
+	scopes := make([]string, len(k.Scopes))
+	for i, s := range k.Scopes {
+		scopes[i] = string(s)
+	}
+	return APIKeyInfo{
+		ID:           k.ID,
+		Name:         k.Name,
+		Prefix:       k.Prefix,
+		Scopes:       scopes,
+		AssignmentID: k.AssignmentID,
+		CreatedBy:    k.CreatedBy,
+		CreatedAt:    k.CreatedAt,
+		ExpiresAt:    k.ExpiresAt,
+		LastUsedAt:   k.LastUsedAt,
+		RevokedAt:    k.RevokedAt,
+	}
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/appeal"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/file"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/review"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+// AppealService ведет апелляции студентов на подтвержденные преподавателем выводы о плагиате.
+type AppealService struct {
+	appealRepo  appeal.Repository
+	plagRepo    plagiarism.Repository
+	reviewRepo  review.Repository
+	fileStorage file.Storage
+	notifier    appeal.Notifier
+	events      WebhookEvents
+	policy      appeal.Policy
+	now         func() time.Time
+}
+
+func NewAppealService(
+	ar appeal.Repository,
+	pr plagiarism.Repository,
+	rr review.Repository,
+	fs file.Storage,
+	n appeal.Notifier,
+	ev WebhookEvents,
+	policy appeal.Policy,
+) *AppealService {
+	return &AppealService{
+		appealRepo:  ar,
+		plagRepo:    pr,
+		reviewRepo:  rr,
+		fileStorage: fs,
+		notifier:    n,
+		events:      ev,
+		policy:      policy,
+		now:         time.Now,
+	}
+}
+
+// EvidenceUpload — файл, приложенный к апелляции при подаче.
+type EvidenceUpload struct {
+	FileName string
+	MimeType string
+	Size     int64
+	Content  io.Reader
+}
+
+type AppealEvidenceResponse struct {
+	ID        uuid.UUID `json:"id"`
+	FileName  string    `json:"file_name"`
+	MimeType  string    `json:"mime_type"`
+	Size      int64     `json:"size"`
+	CreatedAt string    `json:"created_at"`
+}
+
+type AppealResponse struct {
+	ID             uuid.UUID                `json:"id"`
+	WorkID         uuid.UUID                `json:"work_id"`
+	ReportID       uuid.UUID                `json:"report_id"`
+	StudentID      uuid.UUID                `json:"student_id"`
+	Statement      string                   `json:"statement"`
+	Status         string                   `json:"status"`
+	ReviewerID     *uuid.UUID               `json:"reviewer_id,omitempty"`
+	Resolution     string                   `json:"resolution,omitempty"`
+	ResolvedBy     *uuid.UUID               `json:"resolved_by,omitempty"`
+	SubmittedAt    string                   `json:"submitted_at"`
+	ReviewDeadline string                   `json:"review_deadline"`
+	ResolvedAt     string                   `json:"resolved_at,omitempty"`
+	Overdue        bool                     `json:"overdue"`
+	Evidence       []AppealEvidenceResponse `json:"evidence,omitempty"`
+}
+
+// Submit подает апелляцию на последний отчет по работе. Файлы сохраняются до записи
+// апелляции и удаляются, если ее сохранить не удалось.
+func (s *AppealService) Submit(ctx context.Context, workID, studentID uuid.UUID, statement string, files []EvidenceUpload) (*AppealResponse, error) {
+	if _, err := s.plagRepo.GetByWorkID(ctx, workID); err != nil {
+		return nil, err
+	}
+	rv, err := s.reviewRepo.Get(ctx, workID)
+	if errors.Is(err, shared.ErrNotFound) {
+		return nil, appeal.ErrNotAppealable
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rv.Verdict != review.VerdictConfirmed || rv.DecidedAt == nil {
+		return nil, appeal.ErrNotAppealable
+	}
+
+	// Апелляция оспаривает вердикт, а он относится к ревизии, по которой начата проверка:
+	// новые ревизии отчета не должны позволять открыть вторую апелляцию.
+	open, err := s.appealRepo.HasOpen(ctx, rv.ReportID)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, appeal.ErrAlreadyOpen
+	}
+
+	now := s.now()
+	a, err := appeal.New(rv.ReportID, workID, rv.AssignmentID, studentID, statement, *rv.DecidedAt, s.policy, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attach(ctx, a, files, now); err != nil {
+		s.discardEvidence(ctx, a)
+		return nil, err
+	}
+	if err := s.appealRepo.Create(ctx, a); err != nil {
+		s.discardEvidence(ctx, a)
+		return nil, err
+	}
+
+	s.notify(ctx, appeal.NotifySubmitted, a)
+	return newAppealResponse(a, now), nil
+}
+
+func (s *AppealService) attach(ctx context.Context, a *appeal.Appeal, files []EvidenceUpload, now time.Time) error {
+	for _, f := range files {
+		id := uuid.New()
+		path, err := s.fileStorage.Upload(ctx, id, f.Content)
+		if err != nil {
+			return err
+		}
+		e := appeal.Evidence{ID: id, FileName: f.FileName, StoragePath: path, MimeType: f.MimeType, Size: f.Size, CreatedAt: now}
+		if err := a.AddEvidence(e); err != nil {
+			if delErr := s.fileStorage.Delete(ctx, path); delErr != nil {
+				log.Printf("Failed to delete appeal evidence %s: %v", path, delErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *AppealService) discardEvidence(ctx context.Context, a *appeal.Appeal) {
+	for _, e := range a.Evidence {
+		if err := s.fileStorage.Delete(ctx, e.StoragePath); err != nil {
+			log.Printf("Failed to delete appeal evidence %s: %v", e.StoragePath, err)
+		}
+	}
+}
+
+func (s *AppealService) List(ctx context.Context, workID uuid.UUID) ([]AppealResponse, error) {
+	appeals, err := s.appealRepo.ListByWork(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	result := make([]AppealResponse, 0, len(appeals))
+	for _, a := range appeals {
+		result = append(result, *newAppealResponse(a, now))
+	}
+	return result, nil
+}
+
+func (s *AppealService) Get(ctx context.Context, workID, appealID uuid.UUID) (*AppealResponse, error) {
+	a, err := s.get(ctx, workID, appealID)
+	if err != nil {
+		return nil, err
+	}
+	return newAppealResponse(a, s.now()), nil
+}
+
+// OpenEvidence возвращает сведения о приложенном файле и его содержимое; закрывает вызывающий.
+func (s *AppealService) OpenEvidence(ctx context.Context, workID, appealID, evidenceID uuid.UUID) (*AppealEvidenceResponse, io.ReadCloser, error) {
+	a, err := s.get(ctx, workID, appealID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, e := range a.Evidence {
+		if e.ID != evidenceID {
+			continue
+		}
+		rc, err := s.fileStorage.Download(ctx, e.StoragePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		info := newEvidenceResponse(e)
+		return &info, rc, nil
+	}
+	return nil, nil, shared.ErrNotFound
+}
+
+func (s *AppealService) StartReview(ctx context.Context, workID, appealID, reviewerID uuid.UUID) (*AppealResponse, error) {
+	a, err := s.get(ctx, workID, appealID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if err := a.StartReview(reviewerID, now); err != nil {
+		return nil, err
+	}
+	if err := s.appealRepo.Update(ctx, a); err != nil {
+		return nil, err
+	}
+	s.notify(ctx, appeal.NotifyUnderReview, a)
+	return newAppealResponse(a, now), nil
+}
+
+// Resolve выносит решение по апелляции. Удовлетворенная апелляция снимает вывод о плагиате:
+// вердикт проверки меняется на dismissed, и это видно в ее истории.
+func (s *AppealService) Resolve(ctx context.Context, workID, appealID, actorID uuid.UUID, decision, resolution string) (*AppealResponse, error) {
+	d, err := appeal.ParseDecision(decision)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.get(ctx, workID, appealID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if err := a.Resolve(actorID, d, resolution, now); err != nil {
+		return nil, err
+	}
+
+	// Вердикт меняется до сохранения решения: если запись ревью не удалась,
+	// апелляция остается открытой и ее можно рассмотреть повторно.
+	var rv *review.Review
+	if d == appeal.DecisionUphold {
+		if rv, err = s.reviewRepo.Get(ctx, a.WorkID); err != nil {
+			return nil, err
+		}
+		e, err := rv.SetVerdict(actorID, review.VerdictDismissed, a.Resolution, now)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.reviewRepo.Save(ctx, rv, e); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.appealRepo.Update(ctx, a); err != nil {
+		return nil, err
+	}
+
+	if rv != nil {
+		if report, err := s.plagRepo.GetByWorkID(ctx, a.WorkID); err == nil {
+			s.events.ReviewUpdated(ctx, rv, report.IsPlagiarized)
+		} else {
+			log.Printf("Failed to load report of work %s for review event: %v", a.WorkID, err)
+		}
+	}
+
+	s.notify(ctx, appeal.NotifyResolved, a)
+	return newAppealResponse(a, now), nil
+}
+
+// NotifyOverdue сообщает участникам о пропущенных сроках рассмотрения, по одному разу на апелляцию.
+func (s *AppealService) NotifyOverdue(ctx context.Context) (int, error) {
+	now := s.now()
+	appeals, err := s.appealRepo.ListOverdue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	for i, a := range appeals {
+		notified := now
+		a.OverdueNotifiedAt = &notified
+		if err := s.appealRepo.Update(ctx, a); err != nil {
+			return i, err
+		}
+		s.notify(ctx, appeal.NotifyOverdue, a)
+	}
+	return len(appeals), nil
+}
+
+// get загружает апелляцию и проверяет, что она относится к работе из пути запроса:
+// права проверены по работе, поэтому чужая апелляция выглядит как несуществующая.
+func (s *AppealService) get(ctx context.Context, workID, appealID uuid.UUID) (*appeal.Appeal, error) {
+	a, err := s.appealRepo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if a.WorkID != workID {
+		return nil, shared.ErrNotFound
+	}
+	return a, nil
+}
+
+// notify не прерывает операцию: апелляция уже сохранена, недоставленное уведомление только логируется.
+func (s *AppealService) notify(ctx context.Context, kind appeal.NotificationKind, a *appeal.Appeal) {
+	if err := s.notifier.Notify(ctx, appeal.NewNotification(kind, a)); err != nil {
+		log.Printf("Failed to send %s notification for appeal %s: %v", kind, a.ID, err)
+	}
+}
+
+func newAppealResponse(a *appeal.Appeal, now time.Time) *AppealResponse {
+	resp := &AppealResponse{
+		ID:             a.ID,
+		WorkID:         a.WorkID,
+		ReportID:       a.ReportID,
+		StudentID:      a.StudentID,
+		Statement:      a.Statement,
+		Status:         string(a.Status),
+		ReviewerID:     a.ReviewerID,
+		Resolution:     a.Resolution,
+		ResolvedBy:     a.ResolvedBy,
+		SubmittedAt:    formatTimestamp(a.SubmittedAt),
+		ReviewDeadline: formatTimestamp(a.ReviewDeadline),
+		Overdue:        a.Overdue(now),
+	}
+	if a.ResolvedAt != nil {
+		resp.ResolvedAt = formatTimestamp(*a.ResolvedAt)
+	}
+	for _, e := range a.Evidence {
+		resp.Evidence = append(resp.Evidence, newEvidenceResponse(e))
+	}
+	return resp
+}
+
+func newEvidenceResponse(e appeal.Evidence) AppealEvidenceResponse {
+	return AppealEvidenceResponse{
+		ID:        e.ID,
+		FileName:  e.FileName,
+		MimeType:  e.MimeType,
+		Size:      e.Size,
+		CreatedAt: formatTimestamp(e.CreatedAt),
+	}
+}
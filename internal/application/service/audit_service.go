@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/audit"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+const (
+	DefaultAuditLimit = 100
+	MaxAuditLimit     = 1000
+	// auditVerifyBatch — сколько записей читается за раз при проверке цепочки.
+	auditVerifyBatch = 1000
+)
+
+type AuditService struct {
+	repo audit.Repository
+	now  func() time.Time
+}
+
+func NewAuditService(repo audit.Repository) *AuditService {
+	return &AuditService{repo: repo, now: time.Now}
+}
+
+type AuditEntryResponse struct {
+	Seq          int64             `json:"seq"`
+	ActorID      uuid.UUID         `json:"actor_id"`
+	ActorType    string            `json:"actor_type"`
+	ActorRole    string            `json:"actor_role"`
+	Action       string            `json:"action"`
+	ResourceType string            `json:"resource_type"`
+	ResourceID   string            `json:"resource_id"`
+	RequestID    string            `json:"request_id"`
+	Details      map[string]string `json:"details,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	PrevHash     string            `json:"prev_hash"`
+	Hash         string            `json:"hash"`
+}
+
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Checked  int64  `json:"checked"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Record добавляет запись от имени p. Для API-ключа актором считается сам ключ.
+func (s *AuditService) Record(ctx context.Context, p *auth.Principal, requestID string, ev audit.Event) error {
+	actorType := audit.ActorUser
+	if p.Scopes != nil {
+		actorType = audit.ActorAPIKey
+	}
+	return s.repo.Append(ctx, &audit.Entry{
+		ActorID:      p.UserID,
+		ActorType:    actorType,
+		ActorRole:    string(p.Role),
+		Action:       ev.Action,
+		ResourceType: ev.ResourceType,
+		ResourceID:   ev.ResourceID,
+		RequestID:    requestID,
+		Details:      ev.Details,
+		CreatedAt:    s.now(),
+	})
+}
+
+// RecordSystem добавляет запись без пользователя: актор — сама система.
+func (s *AuditService) RecordSystem(ctx context.Context, ev audit.Event) error {
+	return s.repo.Append(ctx, &audit.Entry{
+		ActorType:    audit.ActorSystem,
+		Action:       ev.Action,
+		ResourceType: ev.ResourceType,
+		ResourceID:   ev.ResourceID,
+		Details:      ev.Details,
+		CreatedAt:    s.now(),
+	})
+}
+
+// RecordPolicy записывает смену политики анализа, с которой стартовал сервис svc, если она
+// отличается от последней записанной для него. Порог и правила задаются конфигурацией,
+// поэтому их смена видна только при запуске.
+func (s *AuditService) RecordPolicy(ctx context.Context, svc string, policy plagiarism.Policy) error {
+	current, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	last, err := s.repo.Query(ctx, audit.Filter{
+		Action:       audit.ActionThresholdChange,
+		ResourceType: "policy",
+		ResourceID:   svc,
+		Limit:        1,
+	})
+	if err != nil {
+		return err
+	}
+
+	details := map[string]string{"policy": string(current)}
+	if len(last) > 0 {
+		previous := last[0].Details["policy"]
+		if previous == string(current) {
+			return nil
+		}
+		details["previous_policy"] = previous
+	}
+	return s.RecordSystem(ctx, audit.Event{
+		Action:       audit.ActionThresholdChange,
+		ResourceType: "policy",
+		ResourceID:   svc,
+		Details:      details,
+	})
+}
+
+func (s *AuditService) Query(ctx context.Context, f audit.Filter) ([]AuditEntryResponse, error) {
+	if f.Limit <= 0 {
+		f.Limit = DefaultAuditLimit
+	}
+	if f.Limit > MaxAuditLimit {
+		f.Limit = MaxAuditLimit
+	}
+
+	entries, err := s.repo.Query(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]AuditEntryResponse, len(entries))
+	for i, e := range entries {
+		out[i] = AuditEntryResponse{
+			Seq:          e.Seq,
+			ActorID:      e.ActorID,
+			ActorType:    e.ActorType,
+			ActorRole:    e.ActorRole,
+			Action:       string(e.Action),
+			ResourceType: e.ResourceType,
+			ResourceID:   e.ResourceID,
+			RequestID:    e.RequestID,
+			Details:      e.Details,
+			CreatedAt:    e.CreatedAt,
+			PrevHash:     e.PrevHash,
+			Hash:         e.Hash,
+		}
+	}
+	return out, nil
+}
+
+// Verify проходит весь журнал по порядку и проверяет цепочку хешей.
+func (s *AuditService) Verify(ctx context.Context) (*AuditVerification, error) {
+	var prev *audit.Entry
+	var checked int64
+	for {
+		var after int64
+		if prev != nil {
+			after = prev.Seq
+		}
+		batch, err := s.repo.After(ctx, after, auditVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			return &AuditVerification{Valid: true, Checked: checked}, nil
+		}
+
+		if err := audit.VerifyChain(prev, batch); err != nil {
+			var ce *audit.ChainError
+			if errors.As(err, &ce) {
+				return &AuditVerification{Checked: checked + ce.Seq - after - 1, BrokenAt: ce.Seq, Reason: ce.Reason}, nil
+			}
+			return nil, err
+		}
+		checked += int64(len(batch))
+		prev = batch[len(batch)-1]
+	}
+}
+
+// ParseAuditFilter разбирает параметры запроса журнала: actor_id, action, resource_type,
+// resource_id, from и to (RFC 3339), limit и offset. Шлюз и API принимают одинаковые параметры.
+func ParseAuditFilter(q url.Values) (audit.Filter, error) {
+	f := audit.Filter{
+		Action:       audit.Action(q.Get("action")),
+		ResourceType: q.Get("resource_type"),
+		ResourceID:   q.Get("resource_id"),
+	}
+
+	if raw := q.Get("actor_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return f, fmt.Errorf("%w: actor_id must be a UUID", shared.ErrInvalidInput)
+		}
+		f.ActorID = &id
+	}
+	for name, dst := range map[string]**time.Time{"from": &f.From, "to": &f.To} {
+		if raw := q.Get(name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return f, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", shared.ErrInvalidInput, name)
+			}
+			*dst = &t
+		}
+	}
+	for name, dst := range map[string]*int{"limit": &f.Limit, "offset": &f.Offset} {
+		if raw := q.Get(name); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil || v < 0 {
+				return f, fmt.Errorf("%w: %s must be a non-negative integer", shared.ErrInvalidInput, name)
+			}
+			*dst = v
+		}
+	}
+	return f, nil
+}
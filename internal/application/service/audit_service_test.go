@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/audit"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+)
+
+func TestAuditService_RecordPolicy(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryAudit{}
+	svc := NewAuditService(repo)
+	policy := plagiarism.Policy{Parameters: plagiarism.NewShingleDetector().Parameters(), Threshold: 0.5}
+
+	require.NoError(t, svc.RecordPolicy(ctx, "api", policy))
+	require.Len(t, repo.entries, 1, "первый запуск записывает политику")
+	first := repo.entries[0]
+	assert.Equal(t, audit.ActionThresholdChange, first.Action)
+	assert.Equal(t, audit.ActorSystem, first.ActorType)
+	assert.NotContains(t, first.Details, "previous_policy")
+
+	require.NoError(t, svc.RecordPolicy(ctx, "api", policy))
+	assert.Len(t, repo.entries, 1, "та же политика не записывается повторно")
+
+	require.NoError(t, svc.RecordPolicy(ctx, "analysis", policy))
+	assert.Len(t, repo.entries, 2, "политика каждого сервиса ведется отдельно")
+
+	policy.Threshold = 0.7
+	require.NoError(t, svc.RecordPolicy(ctx, "api", policy))
+	require.Len(t, repo.entries, 3)
+	changed := repo.entries[2]
+	assert.Equal(t, "api", changed.ResourceID)
+	assert.Equal(t, first.Details["policy"], changed.Details["previous_policy"])
+	assert.NotEqual(t, changed.Details["previous_policy"], changed.Details["policy"])
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/batch"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/file"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+// batchLease — на сколько пакет закрепляется за обработчиком. Если проход не завершился
+// за это время (процесс упал), пакет снова попадает в очередь.
+const batchLease = 30 * time.Minute
+
+// batchMimeTypes — форматы, которые принимает и одиночная загрузка.
+var batchMimeTypes = map[string]string{
+	".txt": "text/plain",
+	".md":  "text/markdown",
+}
+
+// BatchService импортирует архив работ по манифесту и проверяет весь пакет одним проходом:
+// попарная матрица считается один раз вместо проверки каждой работы по отдельности.
+type BatchService struct {
+	batchRepo batch.Repository
+	workRepo  work.Repository
+	plagRepo  plagiarism.Repository
+	store     workStore
+	texts     workTextLoader
+	detector  plagiarism.Detector
+	events    WebhookEvents
+
+	policy      plagiarism.Policy
+	maxFileSize int64
+	now         func() time.Time
+}
+
+func NewBatchService(
+	br batch.Repository,
+	wr work.Repository,
+	fr file.Repository,
+	pr plagiarism.Repository,
+	fs file.Storage,
+	te file.TextExtractor,
+	det plagiarism.Detector,
+	ev WebhookEvents,
+	policy plagiarism.Policy,
+	maxFileSize int64,
+) *BatchService {
+	return &BatchService{
+		batchRepo:   br,
+		workRepo:    wr,
+		plagRepo:    pr,
+		store:       workStore{workRepo: wr, fileRepo: fr, fileStorage: fs},
+		texts:       workTextLoader{fileRepo: fr, fileStorage: fs, textExtractor: te},
+		detector:    det,
+		events:      ev,
+		policy:      policy,
+		maxFileSize: maxFileSize,
+		now:         time.Now,
+	}
+}
+
+type BatchItemResponse struct {
+	File      string     `json:"file"`
+	StudentID uuid.UUID  `json:"student_id"`
+	WorkID    *uuid.UUID `json:"work_id,omitempty"`
+	// Error — почему файл не стал работой (нет в архиве, формат, размер).
+	Error string `json:"error,omitempty"`
+	// Status — pending, clean или flagged; пуст для непринятых файлов.
+	Status          string   `json:"status,omitempty"`
+	SimilarityScore *float64 `json:"similarity_score,omitempty"`
+}
+
+type BatchResponse struct {
+	ID           uuid.UUID `json:"id"`
+	AssignmentID uuid.UUID `json:"assignment_id"`
+	CreatedBy    uuid.UUID `json:"created_by"`
+	Status       string    `json:"status"`
+	// Total — файлов в манифесте; Imported из них стали работами, Rejected — нет.
+	Total    int    `json:"total"`
+	Imported int    `json:"imported"`
+	Rejected int    `json:"rejected"`
+	Checked  int    `json:"checked"`
+	Flagged  int    `json:"flagged"`
+	Error    string `json:"error,omitempty"`
+
+	CreatedAt  string              `json:"created_at"`
+	StartedAt  string              `json:"started_at,omitempty"`
+	FinishedAt string              `json:"finished_at,omitempty"`
+	Items      []BatchItemResponse `json:"items"`
+}
+
+// Create принимает zip-архив и манифест: создает работы по всем найденным файлам и ставит
+// пакет в очередь проверки. Файлы, которые не удалось принять, остаются в пакете с причиной.
+// Пакет сохраняется до импорта, и каждая созданная работа сразу записывается в него: если
+// импорт прервется, пакет останется failed, а уже созданные работы — видны в нем.
+func (s *BatchService) Create(ctx context.Context, assignmentID, createdBy uuid.UUID, archive io.ReaderAt, size int64, manifest []batch.ManifestEntry) (*BatchResponse, error) {
+	zr, err := zip.NewReader(archive, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: archive is not a valid zip: %v", shared.ErrInvalidInput, err)
+	}
+	files := indexArchive(zr)
+
+	items := make([]batch.Item, len(manifest))
+	for i, entry := range manifest {
+		items[i] = batch.Item{FileName: entry.File, StudentID: entry.StudentID}
+	}
+	b := batch.New(assignmentID, createdBy, items, s.now())
+	if err := s.batchRepo.Create(ctx, b); err != nil {
+		return nil, err
+	}
+
+	if err := s.importItems(ctx, b, files); err != nil {
+		s.failImport(ctx, b, err)
+		return nil, err
+	}
+	if err := b.Queue(); err != nil {
+		return nil, err
+	}
+	if err := s.batchRepo.Update(ctx, b); err != nil {
+		return nil, err
+	}
+	return s.response(ctx, b)
+}
+
+// importItems создает работы по файлам пакета и сохраняет итог каждого файла.
+func (s *BatchService) importItems(ctx context.Context, b *batch.Batch, files map[string]*zip.File) error {
+	imported := 0
+	for i := range b.Items {
+		item := &b.Items[i]
+		entry := batch.ManifestEntry{File: item.FileName, StudentID: item.StudentID}
+		w, err := s.importFile(ctx, b.AssignmentID, entry, files)
+		switch {
+		case err == nil:
+			item.WorkID = &w.ID
+			imported++
+		case errors.Is(err, shared.ErrInvalidInput):
+			item.Error = strings.TrimPrefix(err.Error(), shared.ErrInvalidInput.Error()+": ")
+		default:
+			return fmt.Errorf("failed to import %s: %w", entry.File, err)
+		}
+		if err := s.batchRepo.SaveItem(ctx, b.ID, i, *item); err != nil {
+			return err
+		}
+	}
+	if imported == 0 {
+		return fmt.Errorf("%w: none of the manifest files could be imported", shared.ErrInvalidInput)
+	}
+	return nil
+}
+
+// failImport отмечает пакет, импорт которого прерван. Запрос мог быть уже отменен,
+// поэтому статус сохраняется без его отмены.
+func (s *BatchService) failImport(ctx context.Context, b *batch.Batch, cause error) {
+	if err := b.Fail(cause.Error(), s.now()); err != nil {
+		log.Printf("Batch %s: %v", b.ID, err)
+		return
+	}
+	if err := s.batchRepo.Update(context.WithoutCancel(ctx), b); err != nil {
+		log.Printf("Batch %s: failed to record import failure: %v", b.ID, err)
+	}
+}
+
+// Get возвращает пакет задания с ходом проверки и итогом по каждому файлу.
+func (s *BatchService) Get(ctx context.Context, assignmentID, batchID uuid.UUID) (*BatchResponse, error) {
+	b, err := s.batchRepo.GetByID(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if b.AssignmentID != assignmentID {
+		return nil, shared.ErrNotFound
+	}
+	return s.response(ctx, b)
+}
+
+// Run проверяет пакеты из очереди каждые interval до отмены ctx.
+func (s *BatchService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for {
+			processed, err := s.ProcessNext(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Batch processing failed: %v", err)
+			}
+			if !processed || err != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessNext берет из очереди один пакет и проверяет его. false — очередь пуста.
+func (s *BatchService) ProcessNext(ctx context.Context) (bool, error) {
+	b, err := s.batchRepo.Claim(ctx, s.now(), batchLease)
+	if errors.Is(err, shared.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := b.Start(s.now()); err != nil {
+		return true, err
+	}
+	if err := s.batchRepo.Update(ctx, b); err != nil {
+		return true, err
+	}
+
+	if err := s.analyze(ctx, b); err != nil {
+		if ctx.Err() != nil {
+			// Остановка процесса: пакет вернется в очередь по истечении аренды.
+			return true, err
+		}
+		log.Printf("Batch %s failed: %v", b.ID, err)
+		if failErr := b.Fail(err.Error(), s.now()); failErr != nil {
+			return true, failErr
+		}
+		return true, s.batchRepo.Update(ctx, b)
+	}
+
+	if err := b.Complete(s.now()); err != nil {
+		return true, err
+	}
+	return true, s.batchRepo.Update(ctx, b)
+}
+
+// analyze сравнивает каждую работу пакета со всеми работами задания, включая другие
+// работы пакета, и сохраняет отчеты.
+func (s *BatchService) analyze(ctx context.Context, b *batch.Batch) error {
+	works, err := s.workRepo.FindByAssignmentID(ctx, b.AssignmentID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch works: %w", err)
+	}
+
+	inBatch := make(map[uuid.UUID]bool)
+	for _, id := range b.WorkIDs() {
+		inBatch[id] = true
+	}
+
+	pass := analysisPass{
+		texts:        s.texts,
+		detector:     s.detector,
+		policy:       s.policy,
+		reason:       plagiarism.ReasonSubmission,
+		counterparts: NewCounterpartUpdater(s.plagRepo, s.detector, s.events, s.policy),
+	}
+	return pass.run(ctx, works, func(w *work.Work) bool { return inBatch[w.ID] }, func(r *plagiarism.Report, w *work.Work) error {
+		if err := s.plagRepo.Save(ctx, r); err != nil {
+			return fmt.Errorf("report save failed: %w", err)
+		}
+		s.events.ReportSaved(ctx, r, w)
+
+		b.Checked++
+		return s.batchRepo.Update(ctx, b)
+	})
+}
+
+// indexArchive — файлы архива по нормализованному пути, без каталогов.
+func indexArchive(zr *zip.Reader) map[string]*zip.File {
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		files[batch.CleanName(f.Name)] = f
+	}
+	return files
+}
+
+// findInArchive ищет файл манифеста: по точному пути, а если его нет — по окончанию пути,
+// если оно однозначно (выгрузки LMS часто кладут все в общий каталог).
+func findInArchive(files map[string]*zip.File, name string) (*zip.File, error) {
+	if f, ok := files[name]; ok {
+		return f, nil
+	}
+	var found *zip.File
+	for p, f := range files {
+		if strings.HasSuffix(p, "/"+name) {
+			if found != nil {
+				return nil, fmt.Errorf("%w: several files in the archive match %q", shared.ErrInvalidInput, name)
+			}
+			found = f
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: file not found in the archive", shared.ErrInvalidInput)
+	}
+	return found, nil
+}
+
+// importFile создает работу по файлу манифеста. Ошибки shared.ErrInvalidInput относятся
+// к самому файлу и не прерывают импорт пакета.
+func (s *BatchService) importFile(ctx context.Context, assignmentID uuid.UUID, entry batch.ManifestEntry, files map[string]*zip.File) (*work.Work, error) {
+	f, err := findInArchive(files, entry.File)
+	if err != nil {
+		return nil, err
+	}
+	mimeType, ok := batchMimeTypes[strings.ToLower(path.Ext(f.Name))]
+	if !ok {
+		return nil, fmt.Errorf("%w: only TXT and MD files are supported", shared.ErrInvalidInput)
+	}
+	if f.UncompressedSize64 > uint64(s.maxFileSize) {
+		return nil, fmt.Errorf("%w: file exceeds max size of %d bytes", shared.ErrInvalidInput, s.maxFileSize)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read file from the archive: %v", shared.ErrInvalidInput, err)
+	}
+	defer rc.Close()
+	// Заголовок zip может занижать размер, поэтому чтение ограничено отдельно.
+	content, err := io.ReadAll(io.LimitReader(rc, s.maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read file from the archive: %v", shared.ErrInvalidInput, err)
+	}
+	if int64(len(content)) > s.maxFileSize {
+		return nil, fmt.Errorf("%w: file exceeds max size of %d bytes", shared.ErrInvalidInput, s.maxFileSize)
+	}
+
+	return s.store.Create(ctx, assignmentID, entry.StudentID, path.Base(f.Name), mimeType, content)
+}
+
+func (s *BatchService) response(ctx context.Context, b *batch.Batch) (*BatchResponse, error) {
+	resp := &BatchResponse{
+		ID:           b.ID,
+		AssignmentID: b.AssignmentID,
+		CreatedBy:    b.CreatedBy,
+		Status:       string(b.Status),
+		Total:        len(b.Items),
+		Imported:     len(b.WorkIDs()),
+		Rejected:     b.Rejected(),
+		Checked:      b.Checked,
+		Error:        b.Error,
+		CreatedAt:    formatTimestamp(b.CreatedAt),
+		Items:        make([]BatchItemResponse, 0, len(b.Items)),
+	}
+	if b.StartedAt != nil {
+		resp.StartedAt = formatTimestamp(*b.StartedAt)
+	}
+	if b.FinishedAt != nil {
+		resp.FinishedAt = formatTimestamp(*b.FinishedAt)
+	}
+
+	// Отчеты всех работ задания одним запросом; до начала проверки их нет.
+	reports := make(map[uuid.UUID]*plagiarism.Report)
+	if b.Checked > 0 || b.Done() {
+		entries, err := s.plagRepo.ListByAssignmentID(ctx, b.AssignmentID)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			reports[e.WorkID] = e.Report
+		}
+	}
+
+	for _, it := range b.Items {
+		item := BatchItemResponse{File: it.FileName, StudentID: it.StudentID, WorkID: it.WorkID, Error: it.Error}
+		if it.WorkID != nil {
+			item.Status = ReportStatusPending
+			if r := reports[*it.WorkID]; r != nil {
+				rr := newReportResponse(r)
+				item.Status = rr.Status
+				item.SimilarityScore = &rr.SimilarityScore
+				if r.IsPlagiarized {
+					resp.Flagged++
+				}
+			}
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	return resp, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/batch"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+// failingUpload отказывает в загрузке после limit успешных файлов.
+type failingUpload struct {
+	*memoryFiles
+	limit int
+}
+
+func (s *failingUpload) Upload(ctx context.Context, fileID uuid.UUID, content io.Reader) (string, error) {
+	if s.limit == 0 {
+		return "", errors.New("storage unavailable")
+	}
+	s.limit--
+	return s.memoryFiles.Upload(ctx, fileID, content)
+}
+
+func zipArchive(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestBatchService_Create(t *testing.T) {
+	ctx := context.Background()
+	f := newServiceFixture()
+	batches := &memoryBatches{batches: map[uuid.UUID]*batch.Batch{}}
+	svc := NewBatchService(batches, f.works, f.files, f.reports, f.files, plainText{}, f.detector, f.events, f.policy, 1<<20)
+	archive := zipArchive(t, map[string]string{"a.txt": essay, "b.pdf": essay})
+	manifest := []batch.ManifestEntry{
+		{File: "a.txt", StudentID: uuid.New()},
+		{File: "b.pdf", StudentID: uuid.New()},
+		{File: "c.txt", StudentID: uuid.New()},
+	}
+
+	resp, err := svc.Create(ctx, uuid.New(), uuid.New(), archive, archive.Size(), manifest)
+	require.NoError(t, err)
+	assert.Equal(t, string(batch.StatusQueued), resp.Status)
+	assert.Equal(t, 1, resp.Imported)
+	assert.Equal(t, 2, resp.Rejected)
+
+	saved, err := batches.GetByID(ctx, resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, batch.StatusQueued, saved.Status)
+	require.NotNil(t, saved.Items[0].WorkID, "итог импорта сохранен в пакете")
+	assert.Equal(t, "file not found in the archive", saved.Items[2].Error)
+}
+
+func TestBatchService_CreateKeepsWorksOfFailedImport(t *testing.T) {
+	ctx := context.Background()
+	f := newServiceFixture()
+	batches := &memoryBatches{batches: map[uuid.UUID]*batch.Batch{}}
+	storage := &failingUpload{memoryFiles: f.files, limit: 1}
+	svc := NewBatchService(batches, f.works, f.files, f.reports, storage, plainText{}, f.detector, f.events, f.policy, 1<<20)
+	archive := zipArchive(t, map[string]string{"a.txt": essay, "b.txt": essay})
+	manifest := []batch.ManifestEntry{
+		{File: "a.txt", StudentID: uuid.New()},
+		{File: "b.txt", StudentID: uuid.New()},
+	}
+
+	_, err := svc.Create(ctx, uuid.New(), uuid.New(), archive, archive.Size(), manifest)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, shared.ErrInvalidInput)
+
+	require.Len(t, batches.batches, 1)
+	for _, b := range batches.batches {
+		assert.Equal(t, batch.StatusFailed, b.Status)
+		assert.Contains(t, b.Error, "storage unavailable")
+		require.NotNil(t, b.Items[0].WorkID, "созданная до сбоя работа привязана к пакету")
+		_, err := f.works.GetByID(ctx, *b.Items[0].WorkID)
+		assert.NoError(t, err)
+		assert.Nil(t, b.Items[1].WorkID)
+	}
+}
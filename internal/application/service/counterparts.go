@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+// CounterpartUpdater дописывает совпадения в отчеты работ, с которыми совпала только что
+// проверенная. Проверка сравнивает новую работу с уже сданными, но не наоборот: без этого
+// у работы, с которой списали, отчет остался бы чистым. Общий для монолита и Analysis Service.
+type CounterpartUpdater struct {
+	plagRepo plagiarism.Repository
+	detector plagiarism.Detector
+	events   WebhookEvents
+	policy   plagiarism.Policy
+}
+
+func NewCounterpartUpdater(pr plagiarism.Repository, det plagiarism.Detector, ev WebhookEvents, policy plagiarism.Policy) *CounterpartUpdater {
+	return &CounterpartUpdater{plagRepo: pr, detector: det, events: ev, policy: policy}
+}
+
+// Counterpart — ранее сданная работа, совпавшая с проверенной, ее текст и сходство пары
+// со стороны проверенной работы.
+type Counterpart struct {
+	Work    *work.Work
+	Text    string
+	Overlap plagiarism.Overlap
+}
+
+// AddMatches добавляет совпадение с проверенной работой checked в отчеты ранее сданных
+// работ. Отчет проверенной работы уже сохранен, поэтому ошибки только логируются.
+func (u *CounterpartUpdater) AddMatches(ctx context.Context, checked *work.Work, text string, counterparts []Counterpart) {
+	for _, c := range counterparts {
+		found := []counterpartMatch{{
+			match: plagiarism.NewMatch(checked.ID, c.Overlap.Similarity, c.Overlap.MatchedContainment, c.Work.SubmittedAt, checked.SubmittedAt),
+			text:  text,
+		}}
+		if err := u.update(ctx, c.Work, c.Text, found); err != nil {
+			log.Printf("Failed to update report of work %s: %v", c.Work.ID, err)
+		}
+	}
+}
+
+// counterpartMatch — совпадение с проверенной работой с точки зрения другой работы
+// и текст проверенной работы для фрагментов.
+type counterpartMatch struct {
+	match plagiarism.Match
+	text  string
+}
+
+// update добавляет ревизию отчета работы w с найденными совпадениями, если они меняют
+// отчет (см. Report.GainsMatch). Работы без отчета и с устаревшим отчетом пропускаются:
+// их пересчитает повторный анализ. Последняя ревизия читается под блокировкой отчета
+// (см. plagiarism.Repository.Amend), поэтому две работы, одновременно совпавшие с w,
+// обе попадут в его отчет.
+func (u *CounterpartUpdater) update(ctx context.Context, w *work.Work, text string, found []counterpartMatch) error {
+	next, err := u.plagRepo.Amend(ctx, w.ID, func(prev *plagiarism.Report) (*plagiarism.Report, error) {
+		return u.withMatches(prev, text, found), nil
+	})
+	if err != nil {
+		return fmt.Errorf("counterpart report save failed: %w", err)
+	}
+	if next != nil {
+		u.events.ReportSaved(ctx, next, w)
+	}
+	return nil
+}
+
+// withMatches — следующая ревизия отчета prev с новыми совпадениями или nil, если менять нечего.
+func (u *CounterpartUpdater) withMatches(prev *plagiarism.Report, text string, found []counterpartMatch) *plagiarism.Report {
+	if prev == nil || prev.IsStale(u.policy) {
+		return nil
+	}
+
+	var gained []plagiarism.Match
+	var best *counterpartMatch
+	for i, f := range found {
+		if !prev.GainsMatch(f.match) {
+			continue
+		}
+		gained = append(gained, f.match)
+		if best == nil || f.match.Score > best.match.Score {
+			best = &found[i]
+		}
+	}
+	if len(gained) == 0 {
+		return nil
+	}
+
+	details := prev.Details
+	if best.match.Score > prev.Score {
+		details = plagiarism.NewAnalysisDetails(
+			u.detector.CountTokens(text),
+			u.detector.Passages(text, best.text),
+			u.detector.Exclusions(text),
+		)
+	}
+	return prev.WithMatches(gained, details)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+func TestCounterpartUpdater_Update(t *testing.T) {
+	ctx := context.Background()
+	f := newServiceFixture()
+	u := f.counterparts()
+	submitted := time.Now().Add(-time.Hour)
+	source := f.addWork(t, uuid.New(), essay, submitted)
+	copierID := uuid.New()
+	found := []counterpartMatch{{
+		match: plagiarism.NewMatch(copierID, 0.9, 0.95, submitted, submitted.Add(time.Minute)),
+		text:  essay,
+	}}
+
+	require.NoError(t, u.update(ctx, source, essay, found))
+	assert.Zero(t, f.reports.revisions(source.ID), "работу без отчета пересчитает повторный анализ")
+
+	stale := plagiarism.NewReport(source.ID, 0, plagiarism.Policy{Threshold: 0.1})
+	require.NoError(t, f.reports.Save(ctx, stale))
+	require.NoError(t, u.update(ctx, source, essay, found))
+	assert.Equal(t, 1, f.reports.revisions(source.ID), "устаревший отчет не дополняется")
+
+	require.NoError(t, f.reports.Save(ctx, plagiarism.NewReport(source.ID, 0, f.policy)))
+	require.NoError(t, u.update(ctx, source, essay, found))
+
+	next, err := f.reports.GetByWorkID(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, next.Revision)
+	assert.Equal(t, plagiarism.ReasonCounterpart, next.Reason)
+	require.NotNil(t, next.MatchedWorkID)
+	assert.Equal(t, copierID, *next.MatchedWorkID)
+	assert.True(t, next.IsPlagiarized)
+	assert.True(t, next.FlaggedByLaterOnly(), "с работы списали позже")
+	assert.Positive(t, next.Details.MatchedTokens, "фрагменты пересчитаны по новому лучшему совпадению")
+	require.Len(t, f.events.reports, 1)
+
+	require.NoError(t, u.update(ctx, source, essay, found))
+	assert.Equal(t, 3, f.reports.revisions(source.ID), "уже известное совпадение не дает новой ревизии")
+}
+
+func TestCounterpartUpdater_ConcurrentMatchesAreKept(t *testing.T) {
+	ctx := context.Background()
+	f := newServiceFixture()
+	u := f.counterparts()
+	submitted := time.Now().Add(-time.Hour)
+	source := f.addWork(t, uuid.New(), essay, submitted)
+	require.NoError(t, f.reports.Save(ctx, plagiarism.NewReport(source.ID, 0, f.policy)))
+
+	copiers := make([]*work.Work, 8)
+	for i := range copiers {
+		copiers[i] = f.addWork(t, source.AssignmentID, essay, submitted.Add(time.Duration(i+1)*time.Minute))
+	}
+	var wg sync.WaitGroup
+	for _, c := range copiers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u.AddMatches(ctx, c, essay, []Counterpart{{Work: source, Text: essay, Overlap: plagiarism.Overlap{Similarity: 0.9, MatchedContainment: 0.95}}})
+		}()
+	}
+	wg.Wait()
+
+	latest, err := f.reports.GetByWorkID(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, len(copiers)+1, latest.Revision)
+	var matched []uuid.UUID
+	for _, m := range latest.Matches {
+		matched = append(matched, m.WorkID)
+	}
+	for _, c := range copiers {
+		assert.Contains(t, matched, c.ID, "последняя ревизия содержит совпадения всех параллельных проверок")
+	}
+}
+
+func TestAnalysisPass_UpdatesCounterparts(t *testing.T) {
+	ctx := context.Background()
+	f := newServiceFixture()
+	assignmentID := uuid.New()
+	submitted := time.Now().Add(-time.Hour)
+	reanalysis := f.reanalysisService()
+
+	source := f.addWork(t, assignmentID, essay, submitted)
+	_, err := reanalysis.ReanalyzeWork(ctx, source.ID, false)
+	require.NoError(t, err)
+	clean, err := f.reports.GetByWorkID(ctx, source.ID)
+	require.NoError(t, err)
+	require.False(t, clean.IsPlagiarized, "в задании нет других работ")
+
+	copier := f.addWork(t, assignmentID, essay, submitted.Add(time.Minute))
+	_, err = reanalysis.ReanalyzeWork(ctx, copier.ID, false)
+	require.NoError(t, err)
+
+	updated, err := f.reports.GetByWorkID(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Revision, "отчет исходной работы дополнен совпадением с копией")
+	assert.Equal(t, plagiarism.ReasonCounterpart, updated.Reason)
+	require.NotNil(t, updated.MatchedWorkID)
+	assert.Equal(t, copier.ID, *updated.MatchedWorkID)
+	assert.True(t, updated.FlaggedByLaterOnly())
+
+	copied, err := f.reports.GetByWorkID(ctx, copier.ID)
+	require.NoError(t, err)
+	assert.True(t, copied.IsPlagiarized)
+	assert.False(t, copied.FlaggedByLaterOnly(), "копия сдана позже исходной")
+}
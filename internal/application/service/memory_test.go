@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/audit"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/batch"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/file"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/review"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+// memoryWorks — работы в памяти.
+type memoryWorks struct {
+	mu    sync.Mutex
+	works map[uuid.UUID]*work.Work
+}
+
+func (r *memoryWorks) Save(_ context.Context, w *work.Work) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.works[w.ID] = w
+	return nil
+}
+
+func (r *memoryWorks) GetByID(_ context.Context, id uuid.UUID) (*work.Work, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if w, ok := r.works[id]; ok {
+		return w, nil
+	}
+	return nil, shared.ErrNotFound
+}
+
+func (r *memoryWorks) FindByAssignmentID(_ context.Context, assignmentID uuid.UUID) ([]*work.Work, error) {
+	return r.find(func(w *work.Work) bool { return w.AssignmentID == assignmentID }), nil
+}
+
+func (r *memoryWorks) FindByStudentID(_ context.Context, studentID uuid.UUID) ([]*work.Work, error) {
+	return r.find(func(w *work.Work) bool { return w.StudentID == studentID }), nil
+}
+
+func (r *memoryWorks) Exists(_ context.Context, studentID, assignmentID uuid.UUID) (bool, error) {
+	return len(r.find(func(w *work.Work) bool {
+		return w.StudentID == studentID && w.AssignmentID == assignmentID
+	})) > 0, nil
+}
+
+func (r *memoryWorks) find(match func(*work.Work) bool) []*work.Work {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*work.Work
+	for _, w := range r.works {
+		if match(w) {
+			found = append(found, w)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].SubmittedAt.Before(found[j].SubmittedAt) })
+	return found
+}
+
+// memoryFiles — метаданные файлов и хранилище в памяти; путь хранилища совпадает с ID файла.
+type memoryFiles struct {
+	mu       sync.Mutex
+	files    map[uuid.UUID]*file.File
+	contents map[string]string
+}
+
+func (r *memoryFiles) Save(_ context.Context, f *file.File) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files[f.ID] = f
+	return nil
+}
+
+func (r *memoryFiles) GetByID(_ context.Context, id uuid.UUID) (*file.File, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.files[id]; ok {
+		return f, nil
+	}
+	return nil, shared.ErrNotFound
+}
+
+func (r *memoryFiles) GetByHash(context.Context, string) (*file.File, error) {
+	return nil, shared.ErrNotFound
+}
+
+func (r *memoryFiles) List(context.Context) ([]*file.File, error) { return nil, nil }
+
+func (r *memoryFiles) UpdateHash(context.Context, uuid.UUID, string) error { return nil }
+
+func (r *memoryFiles) StoragePaths(context.Context) (map[string]struct{}, error) { return nil, nil }
+
+func (r *memoryFiles) Upload(_ context.Context, fileID uuid.UUID, content io.Reader) (string, error) {
+	b, err := io.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.contents[fileID.String()] = string(b)
+	return fileID.String(), nil
+}
+
+func (r *memoryFiles) Download(_ context.Context, path string) (io.ReadCloser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	content, ok := r.contents[path]
+	if !ok {
+		return nil, shared.ErrNotFound
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func (r *memoryFiles) Delete(_ context.Context, path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.contents, path)
+	return nil
+}
+
+// plainText извлекает текст как есть, независимо от MIME.
+type plainText struct{}
+
+func (plainText) ExtractText(content io.Reader, _ string) (string, error) {
+	b, err := io.ReadAll(content)
+	return string(b), err
+}
+
+// memoryReports — ревизии отчетов в памяти, номер ревизии назначается при сохранении.
+type memoryReports struct {
+	mu      sync.Mutex
+	byWork  map[uuid.UUID][]*plagiarism.Report
+	workRef *memoryWorks
+}
+
+func (r *memoryReports) Save(_ context.Context, report *plagiarism.Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report.Revision = len(r.byWork[report.WorkID]) + 1
+	saved := *report
+	r.byWork[report.WorkID] = append(r.byWork[report.WorkID], &saved)
+	return nil
+}
+
+func (r *memoryReports) Amend(_ context.Context, workID uuid.UUID, change func(*plagiarism.Report) (*plagiarism.Report, error)) (*plagiarism.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var latest *plagiarism.Report
+	if reports := r.byWork[workID]; len(reports) > 0 {
+		copied := *reports[len(reports)-1]
+		latest = &copied
+	}
+	next, err := change(latest)
+	if err != nil || next == nil {
+		return nil, err
+	}
+	next.Revision = len(r.byWork[workID]) + 1
+	saved := *next
+	r.byWork[workID] = append(r.byWork[workID], &saved)
+	return next, nil
+}
+
+func (r *memoryReports) GetByWorkID(_ context.Context, workID uuid.UUID) (*plagiarism.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reports := r.byWork[workID]
+	if len(reports) == 0 {
+		return nil, shared.ErrNotFound
+	}
+	latest := *reports[len(reports)-1]
+	return &latest, nil
+}
+
+func (r *memoryReports) ListByWorkID(_ context.Context, workID uuid.UUID) ([]*plagiarism.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*plagiarism.Report{}, r.byWork[workID]...), nil
+}
+
+func (r *memoryReports) GetByID(_ context.Context, id uuid.UUID) (*plagiarism.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, reports := range r.byWork {
+		for _, report := range reports {
+			if report.ID == id {
+				return report, nil
+			}
+		}
+	}
+	return nil, shared.ErrNotFound
+}
+
+func (r *memoryReports) ListByAssignmentID(ctx context.Context, assignmentID uuid.UUID) ([]plagiarism.AssignmentEntry, error) {
+	works, _ := r.workRef.FindByAssignmentID(ctx, assignmentID)
+	entries := make([]plagiarism.AssignmentEntry, len(works))
+	for i, w := range works {
+		entries[i] = plagiarism.AssignmentEntry{WorkID: w.ID, StudentID: w.StudentID, SubmittedAt: w.SubmittedAt}
+		if report, err := r.GetByWorkID(ctx, w.ID); err == nil {
+			entries[i].Report = report
+		}
+	}
+	return entries, nil
+}
+
+// revisions — сколько ревизий отчета сохранено по работе.
+func (r *memoryReports) revisions(workID uuid.UUID) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.byWork[workID])
+}
+
+// memoryReviews — проверки работ в памяти.
+type memoryReviews struct {
+	mu      sync.Mutex
+	reviews map[uuid.UUID]*review.Review
+}
+
+func (r *memoryReviews) Get(_ context.Context, workID uuid.UUID) (*review.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rv, ok := r.reviews[workID]; ok {
+		copied := *rv
+		return &copied, nil
+	}
+	return nil, shared.ErrNotFound
+}
+
+func (r *memoryReviews) Save(_ context.Context, rv *review.Review, _ review.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *rv
+	r.reviews[rv.WorkID] = &copied
+	return nil
+}
+
+func (r *memoryReviews) ListByAssignment(_ context.Context, assignmentID uuid.UUID) (map[uuid.UUID]*review.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found := make(map[uuid.UUID]*review.Review)
+	for workID, rv := range r.reviews {
+		if rv.AssignmentID == assignmentID {
+			found[workID] = rv
+		}
+	}
+	return found, nil
+}
+
+// recordedEvents запоминает отчеты, о которых ушел вебхук.
+type recordedEvents struct {
+	mu      sync.Mutex
+	reports []*plagiarism.Report
+}
+
+func (e *recordedEvents) ReportSaved(_ context.Context, r *plagiarism.Report, _ *work.Work) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.reports = append(e.reports, r)
+}
+
+func (e *recordedEvents) ReviewUpdated(context.Context, *review.Review, bool) {}
+
+// serviceFixture — репозитории в памяти и детектор для тестов сервисов.
+type serviceFixture struct {
+	works    *memoryWorks
+	files    *memoryFiles
+	reports  *memoryReports
+	reviews  *memoryReviews
+	events   *recordedEvents
+	detector *plagiarism.ShingleDetector
+	policy   plagiarism.Policy
+}
+
+func newServiceFixture() *serviceFixture {
+	works := &memoryWorks{works: map[uuid.UUID]*work.Work{}}
+	detector := plagiarism.NewShingleDetector()
+	return &serviceFixture{
+		works:    works,
+		files:    &memoryFiles{files: map[uuid.UUID]*file.File{}, contents: map[string]string{}},
+		reports:  &memoryReports{byWork: map[uuid.UUID][]*plagiarism.Report{}, workRef: works},
+		reviews:  &memoryReviews{reviews: map[uuid.UUID]*review.Review{}},
+		events:   &recordedEvents{},
+		detector: detector,
+		policy:   plagiarism.Policy{Parameters: detector.Parameters(), Threshold: 0.5},
+	}
+}
+
+// addWork сохраняет работу с текстом, сданную в момент submittedAt.
+func (f *serviceFixture) addWork(t *testing.T, assignmentID uuid.UUID, text string, submittedAt time.Time) *work.Work {
+	t.Helper()
+	ctx := context.Background()
+	fl := file.NewFile("work.txt", "", "text/plain", "", int64(len(text)))
+	path, err := f.files.Upload(ctx, fl.ID, strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fl.StoragePath = path
+	if err := f.files.Save(ctx, fl); err != nil {
+		t.Fatal(err)
+	}
+
+	w := work.NewWork(assignmentID, uuid.New(), fl.ID)
+	w.SubmittedAt = submittedAt
+	if err := f.works.Save(ctx, w); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func (f *serviceFixture) texts() workTextLoader {
+	return workTextLoader{fileRepo: f.files, fileStorage: f.files, textExtractor: plainText{}}
+}
+
+func (f *serviceFixture) counterparts() *CounterpartUpdater {
+	return NewCounterpartUpdater(f.reports, f.detector, f.events, f.policy)
+}
+
+func (f *serviceFixture) reanalysisService() *ReanalysisService {
+	return NewReanalysisService(f.works, f.files, f.reports, f.files, plainText{}, f.detector, f.events, f.policy)
+}
+
+// memoryAudit — журнал аудита в памяти, фильтрует по действию и ресурсу.
+type memoryAudit struct {
+	mu      sync.Mutex
+	entries []*audit.Entry
+}
+
+func (r *memoryAudit) Append(_ context.Context, e *audit.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.Seq = int64(len(r.entries) + 1)
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func (r *memoryAudit) Query(_ context.Context, f audit.Filter) ([]*audit.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*audit.Entry
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		e := r.entries[i]
+		if (f.Action == "" || e.Action == f.Action) &&
+			(f.ResourceType == "" || e.ResourceType == f.ResourceType) &&
+			(f.ResourceID == "" || e.ResourceID == f.ResourceID) {
+			found = append(found, e)
+		}
+		if f.Limit > 0 && len(found) == f.Limit {
+			break
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryAudit) After(context.Context, int64, int) ([]*audit.Entry, error) { return nil, nil }
+
+// memoryBatches — пакеты в памяти; очередь не нужна, поэтому Claim ничего не выдает.
+type memoryBatches struct {
+	mu      sync.Mutex
+	batches map[uuid.UUID]*batch.Batch
+}
+
+func (r *memoryBatches) Create(_ context.Context, b *batch.Batch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := *b
+	saved.Items = append([]batch.Item{}, b.Items...)
+	r.batches[b.ID] = &saved
+	return nil
+}
+
+func (r *memoryBatches) SaveItem(_ context.Context, batchID uuid.UUID, position int, it batch.Item) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.batches[batchID]
+	if !ok || position >= len(b.Items) {
+		return shared.ErrNotFound
+	}
+	b.Items[position] = it
+	return nil
+}
+
+func (r *memoryBatches) Update(_ context.Context, b *batch.Batch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved, ok := r.batches[b.ID]
+	if !ok {
+		return shared.ErrNotFound
+	}
+	items := saved.Items
+	*saved = *b
+	saved.Items = items
+	return nil
+}
+
+func (r *memoryBatches) GetByID(_ context.Context, id uuid.UUID) (*batch.Batch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.batches[id]; ok {
+		copied := *b
+		copied.Items = append([]batch.Item{}, b.Items...)
+		return &copied, nil
+	}
+	return nil, shared.ErrNotFound
+}
+
+func (r *memoryBatches) Claim(context.Context, time.Time, time.Duration) (*batch.Batch, error) {
+	return nil, shared.ErrNotFound
+}
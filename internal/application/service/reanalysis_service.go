@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/file"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+// ReanalysisService пересчитывает отчеты уже загруженных работ с текущей политикой,
+// например после смены порога или длины шингла. Каждый пересчет добавляет новую ревизию
+// отчета, прежние остаются.
+type ReanalysisService struct {
+	workRepo work.Repository
+	plagRepo plagiarism.Repository
+	events   WebhookEvents
+	pass     analysisPass
+}
+
+func NewReanalysisService(
+	wr work.Repository,
+	fr file.Repository,
+	pr plagiarism.Repository,
+	fs file.Storage,
+	te file.TextExtractor,
+	det plagiarism.Detector,
+	ev WebhookEvents,
+	policy plagiarism.Policy,
+) *ReanalysisService {
+	return &ReanalysisService{
+		workRepo: wr,
+		plagRepo: pr,
+		events:   ev,
+		pass: analysisPass{
+			texts:        workTextLoader{fileRepo: fr, fileStorage: fs, textExtractor: te},
+			detector:     det,
+			policy:       policy,
+			reason:       plagiarism.ReasonReanalysis,
+			counterparts: NewCounterpartUpdater(pr, det, ev, policy),
+		},
+	}
+}
+
+// ReanalyzedReport — итог пересчета одной работы в сравнении с прежней ревизией.
+type ReanalyzedReport struct {
+	WorkID       uuid.UUID `json:"work_id"`
+	AssignmentID uuid.UUID `json:"assignment_id"`
+	Revision     int       `json:"revision"`
+	// Previous* пусты, если раньше отчета не было.
+	PreviousRevision      int        `json:"previous_revision,omitempty"`
+	PreviousScore         *float64   `json:"previous_score,omitempty"`
+	PreviousIsPlagiarized *bool      `json:"previous_is_plagiarized,omitempty"`
+	SimilarityScore       float64    `json:"similarity_score"`
+	IsPlagiarized         bool       `json:"is_plagiarized"`
+	MatchedWorkID         *uuid.UUID `json:"matched_work_id,omitempty"`
+	// VerdictChanged: у работы сменился вывод о плагиате.
+	VerdictChanged bool `json:"verdict_changed"`
+}
+
+type ReanalysisResponse struct {
+	Policy plagiarism.Policy `json:"policy"`
+	// Skipped — работы с актуальными отчетами, пропущенные при stale_only.
+	Analyzed int                `json:"analyzed"`
+	Skipped  int                `json:"skipped"`
+	Changed  int                `json:"changed"`
+	Reports  []ReanalyzedReport `json:"reports"`
+}
+
+// ReanalyzeWork пересчитывает отчет одной работы против всех остальных работ задания.
+func (s *ReanalysisService) ReanalyzeWork(ctx context.Context, workID uuid.UUID, staleOnly bool) (*ReanalysisResponse, error) {
+	w, err := s.workRepo.GetByID(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.newResponse()
+	err = s.reanalyze(ctx, w.AssignmentID, staleOnly, func(other *work.Work) bool { return other.ID == workID }, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ReanalyzeAssignments пересчитывает отчеты всех работ перечисленных заданий: одного
+// задания или всех заданий курса.
+func (s *ReanalysisService) ReanalyzeAssignments(ctx context.Context, assignmentIDs []uuid.UUID, staleOnly bool) (*ReanalysisResponse, error) {
+	if len(assignmentIDs) == 0 {
+		return nil, fmt.Errorf("%w: no assignments to reanalyze", shared.ErrInvalidInput)
+	}
+
+	resp := s.newResponse()
+	all := func(*work.Work) bool { return true }
+	for _, id := range assignmentIDs {
+		if err := s.reanalyze(ctx, id, staleOnly, all, resp); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (s *ReanalysisService) newResponse() *ReanalysisResponse {
+	return &ReanalysisResponse{Policy: s.pass.policy, Reports: []ReanalyzedReport{}}
+}
+
+// reanalyze пересчитывает отобранные работы задания и дописывает итоги в resp.
+func (s *ReanalysisService) reanalyze(ctx context.Context, assignmentID uuid.UUID, staleOnly bool, selected func(w *work.Work) bool, resp *ReanalysisResponse) error {
+	entries, err := s.plagRepo.ListByAssignmentID(ctx, assignmentID)
+	if err != nil {
+		return err
+	}
+	previous := make(map[uuid.UUID]*plagiarism.Report, len(entries))
+	for _, e := range entries {
+		previous[e.WorkID] = e.Report
+	}
+
+	works, err := s.workRepo.FindByAssignmentID(ctx, assignmentID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch works: %w", err)
+	}
+
+	pick := func(w *work.Work) bool {
+		if !selected(w) {
+			return false
+		}
+		if prev := previous[w.ID]; staleOnly && prev != nil && !prev.IsStale(s.pass.policy) {
+			resp.Skipped++
+			return false
+		}
+		return true
+	}
+	return s.pass.run(ctx, works, pick, func(r *plagiarism.Report, w *work.Work) error {
+		if err := s.plagRepo.Save(ctx, r); err != nil {
+			return fmt.Errorf("report save failed: %w", err)
+		}
+		s.events.ReportSaved(ctx, r, w)
+
+		item := ReanalyzedReport{
+			WorkID:          w.ID,
+			AssignmentID:    w.AssignmentID,
+			Revision:        r.Revision,
+			SimilarityScore: r.Score,
+			IsPlagiarized:   r.IsPlagiarized,
+			MatchedWorkID:   r.MatchedWorkID,
+		}
+		if prev := previous[w.ID]; prev != nil {
+			item.PreviousRevision = prev.Revision
+			item.PreviousScore = &prev.Score
+			item.PreviousIsPlagiarized = &prev.IsPlagiarized
+			item.VerdictChanged = prev.IsPlagiarized != r.IsPlagiarized
+		}
+		if item.VerdictChanged {
+			resp.Changed++
+		}
+		resp.Analyzed++
+		resp.Reports = append(resp.Reports, item)
+		return nil
+	})
+}
@@ -2,32 +2,119 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/review"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
 )
 
+const (
+	ReportStatusPending = "pending"
+	ReportStatusClean   = "clean"
+	ReportStatusFlagged = "flagged"
+	// ReportStatusInsufficient — в работе слишком мало слов для сравнения, оценки нет.
+	ReportStatusInsufficient = "insufficient_content"
+)
+
 type ReportService struct {
-	plagRepo plagiarism.Repository
-	workRepo work.Repository
+	plagRepo   plagiarism.Repository
+	workRepo   work.Repository
+	reviewRepo review.Repository
+	// policy — текущая политика анализа; отчеты, построенные с другой, помечаются stale.
+	policy plagiarism.Policy
 }
 
-func NewReportService(pr plagiarism.Repository, wr work.Repository) *ReportService {
+func NewReportService(pr plagiarism.Repository, wr work.Repository, rr review.Repository, policy plagiarism.Policy) *ReportService {
 	return &ReportService{
-		plagRepo: pr,
-		workRepo: wr,
+		plagRepo:   pr,
+		workRepo:   wr,
+		reviewRepo: rr,
+		policy:     policy,
 	}
 }
 
 type ReportResponse struct {
-	WorkID          uuid.UUID                  `json:"work_id"`
-	IsPlagiarized   bool                       `json:"is_plagiarized"`
-	SimilarityScore float64                    `json:"similarity_score"`
-	MatchedWorkID   *uuid.UUID                 `json:"matched_work_id,omitempty"`
-	CreatedAt       string                     `json:"created_at"`
-	Details         plagiarism.AnalysisDetails `json:"details"`
+	WorkID          uuid.UUID  `json:"work_id"`
+	StudentID       *uuid.UUID `json:"student_id,omitempty"`
+	SubmittedAt     string     `json:"submitted_at,omitempty"`
+	Status          string     `json:"status"`
+	IsPlagiarized   bool       `json:"is_plagiarized"`
+	SimilarityScore float64    `json:"similarity_score"`
+	MatchedWorkID   *uuid.UUID `json:"matched_work_id,omitempty"`
+	// MatchRelation — сдана ли лучшая совпавшая работа раньше (earlier) или позже (later).
+	MatchRelation string `json:"match_relation,omitempty"`
+	// FlaggedByLaterOnly: плагиат найден только в работах, сданных позже, — списали с этой работы.
+	FlaggedByLaterOnly bool                       `json:"flagged_by_later_only"`
+	Matches            []plagiarism.Match         `json:"matches,omitempty"`
+	CreatedAt          string                     `json:"created_at,omitempty"`
+	Details            plagiarism.AnalysisDetails `json:"details"`
+	// Decision — уровень по правилам политики и сработавшие правила; Explanation — то же
+	// одной строкой. Пусто у отчетов, построенных до появления правил.
+	Decision    *plagiarism.Decision `json:"decision,omitempty"`
+	Explanation string               `json:"explanation,omitempty"`
+	// Revision — номер ревизии отчета по работе; Policy — с какими параметрами она построена.
+	Revision int                `json:"revision,omitempty"`
+	Policy   *plagiarism.Policy `json:"policy,omitempty"`
+	// Stale: параметры отчета расходятся с текущей политикой, нужен повторный анализ.
+	Stale bool `json:"stale"`
+	// Review — решение преподавателя; автоматический результат выше не меняется.
+	Review *ReviewSummary `json:"review,omitempty"`
+}
+
+// ReviewSummary — состояние проверки отчета преподавателем рядом с автоматическим результатом.
+type ReviewSummary struct {
+	State      string     `json:"state"`
+	Verdict    string     `json:"verdict,omitempty"`
+	AssigneeID *uuid.UUID `json:"assignee_id,omitempty"`
+	VerdictBy  *uuid.UUID `json:"verdict_by,omitempty"`
+	Comment    string     `json:"comment,omitempty"`
+	DecidedAt  string     `json:"decided_at,omitempty"`
+	// OverridesAutomatic: вердикт противоречит автоматическому is_plagiarized.
+	OverridesAutomatic bool `json:"overrides_automatic"`
+}
+
+func newReviewSummary(r *review.Review, isPlagiarized bool) *ReviewSummary {
+	summary := &ReviewSummary{State: string(r.State()), OverridesAutomatic: r.Overrides(isPlagiarized)}
+	if r != nil {
+		summary.Verdict = string(r.Verdict)
+		summary.AssigneeID = r.AssigneeID
+		summary.VerdictBy = r.VerdictBy
+		summary.Comment = r.VerdictComment
+		if r.DecidedAt != nil {
+			summary.DecidedAt = formatTimestamp(*r.DecidedAt)
+		}
+	}
+	return summary
+}
+
+// ReportSummary — итог проверки без совпавшей работы и фрагментов: то, что видит автор работы.
+type ReportSummary struct {
+	WorkID          uuid.UUID `json:"work_id"`
+	Status          string    `json:"status"`
+	IsPlagiarized   bool      `json:"is_plagiarized"`
+	SimilarityScore float64   `json:"similarity_score"`
+	CreatedAt       string    `json:"created_at,omitempty"`
+}
+
+func (r *ReportResponse) Summary() ReportSummary {
+	return ReportSummary{
+		WorkID:          r.WorkID,
+		Status:          r.Status,
+		IsPlagiarized:   r.IsPlagiarized,
+		SimilarityScore: r.SimilarityScore,
+		CreatedAt:       r.CreatedAt,
+	}
+}
+
+// GetWork нужен для проверки доступа: кто автор работы и к какому заданию она относится.
+func (s *ReportService) GetWork(ctx context.Context, workID uuid.UUID) (*work.Work, error) {
+	return s.workRepo.GetByID(ctx, workID)
 }
 
 func (s *ReportService) GetReportByWorkID(ctx context.Context, workID uuid.UUID) (*ReportResponse, error) {
@@ -36,39 +123,194 @@ func (s *ReportService) GetReportByWorkID(ctx context.Context, workID uuid.UUID)
 		return nil, err
 	}
 
-	return &ReportResponse{
-		WorkID:          report.WorkID,
-		IsPlagiarized:   report.IsPlagiarized,
-		SimilarityScore: report.Score,
-		MatchedWorkID:   report.MatchedWorkID,
-		CreatedAt:       report.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		Details:         report.Details,
-	}, nil
+	rv, err := s.reviewRepo.Get(ctx, workID)
+	if err != nil && !errors.Is(err, shared.ErrNotFound) {
+		return nil, err
+	}
+
+	resp := newReportResponse(report)
+	resp.Stale = report.IsStale(s.policy)
+	resp.Review = newReviewSummary(rv, report.IsPlagiarized)
+	return &resp, nil
 }
 
-func (s *ReportService) GetReportsByAssignmentID(ctx context.Context, assignmentID uuid.UUID) ([]ReportResponse, error) {
-	works, err := s.workRepo.FindByAssignmentID(ctx, assignmentID)
+// GetReportsByAssignmentID возвращает отчеты по всем проверенным работам задания.
+// Непустой reviewState оставляет только отчеты в этом состоянии проверки.
+func (s *ReportService) GetReportsByAssignmentID(ctx context.Context, assignmentID uuid.UUID, reviewState review.State) ([]ReportResponse, error) {
+	entries, err := s.plagRepo.ListByAssignmentID(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.reviewRepo.ListByAssignment(ctx, assignmentID)
 	if err != nil {
 		return nil, err
 	}
 
 	var reports []ReportResponse
-	for _, w := range works {
-		report, err := s.plagRepo.GetByWorkID(ctx, w.ID)
-		if err != nil {
-			// Если отчета нет, пропускаем или логируем
+	for _, e := range entries {
+		if e.Report == nil {
+			continue
+		}
+		rv := reviews[e.WorkID]
+		if reviewState != "" && rv.State() != reviewState {
 			continue
 		}
+		resp := newEntryResponse(e)
+		resp.Stale = e.Report.IsStale(s.policy)
+		resp.Review = newReviewSummary(rv, e.Report.IsPlagiarized)
+		reports = append(reports, resp)
+	}
 
-		reports = append(reports, ReportResponse{
-			WorkID:          report.WorkID,
-			IsPlagiarized:   report.IsPlagiarized,
-			SimilarityScore: report.Score,
-			MatchedWorkID:   report.MatchedWorkID,
-			CreatedAt:       report.CreatedAt.Format("2006-01-02T15:04:05Z"),
-			Details:         report.Details,
-		})
+	return reports, nil
+}
+
+// ExportAssignmentReports — как GetReportsByAssignmentID, но включает и непроверенные
+// работы со статусом pending: в ведомости должна быть строка на каждую работу.
+func (s *ReportService) ExportAssignmentReports(ctx context.Context, assignmentID uuid.UUID) ([]ReportResponse, error) {
+	entries, err := s.plagRepo.ListByAssignmentID(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]ReportResponse, 0, len(entries))
+	for _, e := range entries {
+		resp := newEntryResponse(e)
+		resp.Stale = e.Report != nil && e.Report.IsStale(s.policy)
+		reports = append(reports, resp)
 	}
 
 	return reports, nil
 }
+
+// ReportRevision — одна ревизия отчета в истории работы.
+type ReportRevision struct {
+	Revision           int                  `json:"revision"`
+	Reason             string               `json:"reason"`
+	CreatedAt          string               `json:"created_at"`
+	Policy             *plagiarism.Policy   `json:"policy,omitempty"`
+	Stale              bool                 `json:"stale"`
+	Status             string               `json:"status"`
+	Decision           *plagiarism.Decision `json:"decision,omitempty"`
+	IsPlagiarized      bool                 `json:"is_plagiarized"`
+	SimilarityScore    float64              `json:"similarity_score"`
+	MatchedWorkID      *uuid.UUID           `json:"matched_work_id,omitempty"`
+	MatchRelation      string               `json:"match_relation,omitempty"`
+	FlaggedByLaterOnly bool                 `json:"flagged_by_later_only"`
+	Matches            []plagiarism.Match   `json:"matches"`
+}
+
+// GetReportHistory возвращает все ревизии отчета по работе, начиная с последней.
+func (s *ReportService) GetReportHistory(ctx context.Context, workID uuid.UUID) ([]ReportRevision, error) {
+	reports, err := s.plagRepo.ListByWorkID(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, shared.ErrNotFound
+	}
+
+	history := make([]ReportRevision, 0, len(reports))
+	for i := len(reports) - 1; i >= 0; i-- {
+		r := reports[i]
+		resp := newReportResponse(r)
+		history = append(history, ReportRevision{
+			Revision:           r.Revision,
+			Reason:             r.Reason,
+			CreatedAt:          resp.CreatedAt,
+			Policy:             r.Policy,
+			Stale:              r.IsStale(s.policy),
+			Status:             resp.Status,
+			Decision:           r.Decision,
+			IsPlagiarized:      r.IsPlagiarized,
+			SimilarityScore:    r.Score,
+			MatchedWorkID:      r.MatchedWorkID,
+			MatchRelation:      resp.MatchRelation,
+			FlaggedByLaterOnly: resp.FlaggedByLaterOnly,
+			Matches:            r.AllMatches(),
+		})
+	}
+	return history, nil
+}
+
+// DiffReports сравнивает две ревизии отчета по работе. Нулевой to — последняя ревизия,
+// нулевой from — предпоследняя.
+func (s *ReportService) DiffReports(ctx context.Context, workID uuid.UUID, from, to int) (*plagiarism.ReportDiff, error) {
+	reports, err := s.plagRepo.ListByWorkID(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, shared.ErrNotFound
+	}
+
+	byRevision := make(map[int]*plagiarism.Report, len(reports))
+	for _, r := range reports {
+		byRevision[r.Revision] = r
+	}
+	if to == 0 {
+		to = reports[len(reports)-1].Revision
+	}
+	if from == 0 {
+		from = to - 1
+	}
+	if from == to {
+		return nil, fmt.Errorf("%w: revisions to compare must differ", shared.ErrInvalidInput)
+	}
+
+	for _, rev := range []int{from, to} {
+		if byRevision[rev] == nil {
+			return nil, fmt.Errorf("%w: work has no revision %d", shared.ErrNotFound, rev)
+		}
+	}
+	diff := plagiarism.DiffReports(byRevision[from], byRevision[to])
+	return &diff, nil
+}
+
+// ReportStatus — статус проверенной работы: clean, flagged или insufficient_content.
+func ReportStatus(report *plagiarism.Report) string {
+	switch {
+	case report.Details.InsufficientContent:
+		return ReportStatusInsufficient
+	case report.IsPlagiarized:
+		return ReportStatusFlagged
+	}
+	return ReportStatusClean
+}
+
+func newReportResponse(report *plagiarism.Report) ReportResponse {
+	resp := ReportResponse{
+		WorkID:             report.WorkID,
+		Status:             ReportStatus(report),
+		IsPlagiarized:      report.IsPlagiarized,
+		SimilarityScore:    report.Score,
+		MatchedWorkID:      report.MatchedWorkID,
+		MatchRelation:      report.MatchRelation(),
+		FlaggedByLaterOnly: report.FlaggedByLaterOnly(),
+		Matches:            report.Matches,
+		CreatedAt:          formatTimestamp(report.CreatedAt),
+		Details:            report.Details,
+		Decision:           report.Decision,
+		Revision:           report.Revision,
+		Policy:             report.Policy,
+	}
+	if report.Decision != nil {
+		resp.Explanation = report.Decision.Explanation()
+	}
+	return resp
+}
+
+func newEntryResponse(e plagiarism.AssignmentEntry) ReportResponse {
+	resp := ReportResponse{WorkID: e.WorkID, Status: ReportStatusPending}
+	if e.Report != nil {
+		resp = newReportResponse(e.Report)
+	}
+
+	studentID := e.StudentID
+	resp.StudentID = &studentID
+	resp.SubmittedAt = formatTimestamp(e.SubmittedAt)
+	return resp
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
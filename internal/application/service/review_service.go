@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/review"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+// ReviewService ведет проверку последнего отчета по работе преподавателем:
+// назначение проверяющего, комментарии и вердикт с историей.
+type ReviewService struct {
+	plagRepo   plagiarism.Repository
+	workRepo   work.Repository
+	reviewRepo review.Repository
+	events     WebhookEvents
+	now        func() time.Time
+}
+
+func NewReviewService(pr plagiarism.Repository, wr work.Repository, rr review.Repository, ev WebhookEvents) *ReviewService {
+	return &ReviewService{plagRepo: pr, workRepo: wr, reviewRepo: rr, events: ev, now: time.Now}
+}
+
+type ReviewEventResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	ActorID    uuid.UUID  `json:"actor_id"`
+	Kind       string     `json:"kind"`
+	AssigneeID *uuid.UUID `json:"assignee_id,omitempty"`
+	Verdict    string     `json:"verdict,omitempty"`
+	Comment    string     `json:"comment,omitempty"`
+	CreatedAt  string     `json:"created_at"`
+}
+
+type ReviewResponse struct {
+	WorkID uuid.UUID `json:"work_id"`
+	// Автоматический результат отчета, к которому относится проверка.
+	IsPlagiarized   bool    `json:"is_plagiarized"`
+	SimilarityScore float64 `json:"similarity_score"`
+	ReviewSummary
+	History []ReviewEventResponse `json:"history"`
+}
+
+func (s *ReviewService) GetReview(ctx context.Context, workID uuid.UUID) (*ReviewResponse, error) {
+	report, rv, err := s.load(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	return newReviewResponse(report, rv), nil
+}
+
+func (s *ReviewService) Assign(ctx context.Context, workID, actorID, assigneeID uuid.UUID) (*ReviewResponse, error) {
+	return s.update(ctx, workID, func(rv *review.Review) (review.Event, error) {
+		return rv.Assign(actorID, assigneeID, s.now()), nil
+	})
+}
+
+func (s *ReviewService) Comment(ctx context.Context, workID, actorID uuid.UUID, text string) (*ReviewResponse, error) {
+	return s.update(ctx, workID, func(rv *review.Review) (review.Event, error) {
+		return rv.Comment(actorID, text, s.now())
+	})
+}
+
+func (s *ReviewService) SetVerdict(ctx context.Context, workID, actorID uuid.UUID, verdict, comment string) (*ReviewResponse, error) {
+	v, err := review.ParseVerdict(verdict)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown verdict %q", shared.ErrInvalidInput, verdict)
+	}
+	return s.update(ctx, workID, func(rv *review.Review) (review.Event, error) {
+		return rv.SetVerdict(actorID, v, comment, s.now())
+	})
+}
+
+func (s *ReviewService) update(ctx context.Context, workID uuid.UUID, apply func(*review.Review) (review.Event, error)) (*ReviewResponse, error) {
+	report, rv, err := s.load(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	e, err := apply(rv)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reviewRepo.Save(ctx, rv, e); err != nil {
+		return nil, err
+	}
+	s.events.ReviewUpdated(ctx, rv, report.IsPlagiarized)
+	return newReviewResponse(report, rv), nil
+}
+
+// load возвращает последний отчет по работе и проверку работы (новую, если ее еще не было).
+func (s *ReviewService) load(ctx context.Context, workID uuid.UUID) (*plagiarism.Report, *review.Review, error) {
+	report, err := s.plagRepo.GetByWorkID(ctx, workID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rv, err := s.reviewRepo.Get(ctx, workID)
+	if errors.Is(err, shared.ErrNotFound) {
+		w, werr := s.workRepo.GetByID(ctx, workID)
+		if werr != nil {
+			return nil, nil, werr
+		}
+		return report, review.New(report.ID, workID, w.AssignmentID), nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return report, rv, nil
+}
+
+func newReviewResponse(report *plagiarism.Report, rv *review.Review) *ReviewResponse {
+	resp := &ReviewResponse{
+		WorkID:          report.WorkID,
+		IsPlagiarized:   report.IsPlagiarized,
+		SimilarityScore: report.Score,
+		ReviewSummary:   *newReviewSummary(rv, report.IsPlagiarized),
+		History:         make([]ReviewEventResponse, 0, len(rv.History)),
+	}
+	for _, e := range rv.History {
+		resp.History = append(resp.History, ReviewEventResponse{
+			ID:         e.ID,
+			ActorID:    e.ActorID,
+			Kind:       string(e.Kind),
+			AssigneeID: e.AssigneeID,
+			Verdict:    string(e.Verdict),
+			Comment:    e.Comment,
+			CreatedAt:  formatTimestamp(e.CreatedAt),
+		})
+	}
+	return resp
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/review"
+)
+
+const essay = "the quick brown fox jumps over the lazy dog while the farmer watches from the old wooden fence near the river bank"
+
+func TestReviewService_VerdictSurvivesReanalysis(t *testing.T) {
+	ctx := context.Background()
+	f := newServiceFixture()
+	assignmentID := uuid.New()
+	submitted := time.Now().Add(-time.Hour)
+	f.addWork(t, assignmentID, essay, submitted)
+	copied := f.addWork(t, assignmentID, essay, submitted.Add(time.Minute))
+
+	reanalysis := f.reanalysisService()
+	_, err := reanalysis.ReanalyzeWork(ctx, copied.ID, false)
+	require.NoError(t, err)
+
+	reviews := NewReviewService(f.reports, f.works, f.reviews, f.events)
+	_, err = reviews.SetVerdict(ctx, copied.ID, uuid.New(), string(review.VerdictConfirmed), "списано")
+	require.NoError(t, err)
+
+	_, err = reanalysis.ReanalyzeWork(ctx, copied.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, f.reports.revisions(copied.ID), "повторный анализ сохраняет новую ревизию")
+
+	reports := NewReportService(f.reports, f.works, f.reviews, f.policy)
+	resp, err := reports.GetReportByWorkID(ctx, copied.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Revision)
+	require.NotNil(t, resp.Review, "вердикт виден у новой ревизии")
+	assert.Equal(t, string(review.VerdictConfirmed), resp.Review.Verdict)
+
+	confirmed, err := reports.GetReportsByAssignmentID(ctx, assignmentID, review.StateConfirmed)
+	require.NoError(t, err)
+	require.Len(t, confirmed, 1, "фильтр по состоянию проверки находит работу после повторного анализа")
+	assert.Equal(t, copied.ID, confirmed[0].WorkID)
+}
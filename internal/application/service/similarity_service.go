@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/file"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+type SimilarityService struct {
+	workRepo work.Repository
+	plagRepo plagiarism.Repository
+	texts    workTextLoader
+	detector plagiarism.Detector
+
+	threshold float64
+}
+
+func NewSimilarityService(
+	wr work.Repository,
+	pr plagiarism.Repository,
+	fr file.Repository,
+	fs file.Storage,
+	te file.TextExtractor,
+	det plagiarism.Detector,
+	threshold float64,
+) *SimilarityService {
+	return &SimilarityService{
+		workRepo:  wr,
+		plagRepo:  pr,
+		texts:     workTextLoader{fileRepo: fr, fileStorage: fs, textExtractor: te},
+		detector:  det,
+		threshold: threshold,
+	}
+}
+
+type MatrixWork struct {
+	WorkID    uuid.UUID `json:"work_id"`
+	StudentID uuid.UUID `json:"student_id"`
+}
+
+type SimilarityMatrixResponse struct {
+	AssignmentID uuid.UUID            `json:"assignment_id"`
+	Threshold    float64              `json:"threshold"`
+	Works        []MatrixWork         `json:"works"`
+	Matrix       [][]float64          `json:"matrix"`
+	Clusters     []plagiarism.Cluster `json:"clusters"`
+
+	matrix *plagiarism.SimilarityMatrix
+}
+
+// SimilarityMatrix возвращает доменную матрицу для экспорта в CSV/DOT.
+func (r *SimilarityMatrixResponse) SimilarityMatrix() *plagiarism.SimilarityMatrix {
+	return r.matrix
+}
+
+// Labels сопоставляет работу со студентом для подписей в экспортируемых графах.
+func (r *SimilarityMatrixResponse) Labels() map[uuid.UUID]string {
+	labels := make(map[uuid.UUID]string, len(r.Works))
+	for _, w := range r.Works {
+		labels[w.WorkID] = w.StudentID.String()
+	}
+	return labels
+}
+
+func (s *SimilarityService) DefaultThreshold() float64 {
+	return s.threshold
+}
+
+// GetAssignmentMatrix считает полную матрицу N×N по всем работам задания и кластеризует ее.
+// threshold <= 0 означает порог по умолчанию.
+func (s *SimilarityService) GetAssignmentMatrix(ctx context.Context, assignmentID uuid.UUID, threshold float64) (*SimilarityMatrixResponse, error) {
+	if threshold <= 0 {
+		threshold = s.threshold
+	}
+
+	works, err := s.workRepo.FindByAssignmentID(ctx, assignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch works: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(works))
+	texts := make([]string, 0, len(works))
+	items := make([]MatrixWork, 0, len(works))
+	for _, w := range works {
+		text, err := s.texts.Load(ctx, w)
+		if err != nil {
+			fmt.Printf("Skipping work %s in matrix: %v\n", w.ID, err)
+			continue
+		}
+		ids = append(ids, w.ID)
+		texts = append(texts, text)
+		items = append(items, MatrixWork{WorkID: w.ID, StudentID: w.StudentID})
+	}
+
+	matrix, err := plagiarism.BuildSimilarityMatrix(s.detector, ids, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build similarity matrix: %w", err)
+	}
+
+	return &SimilarityMatrixResponse{
+		AssignmentID: assignmentID,
+		Threshold:    threshold,
+		Works:        items,
+		Matrix:       matrix.Scores,
+		Clusters:     matrix.Clusters(threshold),
+		matrix:       matrix,
+	}, nil
+}
+
+// CompareWorks строит подробное сравнение двух работ для отчета «бок о бок».
+func (s *SimilarityService) CompareWorks(ctx context.Context, workID, matchedID uuid.UUID) (*plagiarism.Comparison, error) {
+	source, err := s.comparedText(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := s.comparedText(ctx, matchedID)
+	if err != nil {
+		return nil, err
+	}
+
+	return plagiarism.NewComparison(s.detector, *source, *match, s.threshold)
+}
+
+// AttributeMatch оценивает, какая из двух совпавших работ вероятнее источник, а какая —
+// копия: по порядку сдачи, контексту общих фрагментов и более ранним версиям авторов.
+func (s *SimilarityService) AttributeMatch(ctx context.Context, workID, matchedID uuid.UUID) (*plagiarism.Attribution, error) {
+	w, err := s.workRepo.GetByID(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	matched, err := s.workRepo.GetByID(ctx, matchedID)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := s.versionedText(ctx, w, matched.SubmittedAt)
+	if err != nil {
+		return nil, err
+	}
+	other, err := s.versionedText(ctx, matched, w.SubmittedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	attr := plagiarism.BuildAttribution(s.detector, *source, *other)
+	return &attr, nil
+}
+
+// versionedText загружает текст работы и версии того же автора по тому же заданию,
+// сданные раньше before: только они могут говорить об авторстве общего текста.
+func (s *SimilarityService) versionedText(ctx context.Context, w *work.Work, before time.Time) (*plagiarism.VersionedText, error) {
+	text, err := s.texts.Load(ctx, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load text of work %s: %w", w.ID, err)
+	}
+	vt := &plagiarism.VersionedText{WorkID: w.ID, SubmittedAt: w.SubmittedAt, Text: text}
+
+	works, err := s.workRepo.FindByAssignmentID(ctx, w.AssignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch works: %w", err)
+	}
+	for _, v := range works {
+		if v.ID == w.ID || v.StudentID != w.StudentID || !v.SubmittedAt.Before(before) {
+			continue
+		}
+		vText, err := s.texts.Load(ctx, v)
+		if err != nil {
+			log.Printf("Skipping version %s in attribution: %v", v.ID, err)
+			continue
+		}
+		vt.Versions = append(vt.Versions, plagiarism.Version{WorkID: v.ID, SubmittedAt: v.SubmittedAt, Text: vText})
+	}
+	return vt, nil
+}
+
+// GetEvidence собирает данные для печатного отчета: сохраненный отчет по работе
+// и topN самых похожих работ задания с совпавшими фрагментами.
+func (s *SimilarityService) GetEvidence(ctx context.Context, workID uuid.UUID, topN int) (*plagiarism.Evidence, error) {
+	report, err := s.plagRepo.GetByWorkID(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := s.workRepo.GetByID(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Доказательства описывают сохраненный отчет, поэтому считаются с его политикой,
+	// даже если порог или параметры с тех пор сменились.
+	detector, threshold := s.detector, s.threshold
+	if report.Policy != nil && report.Policy.Parameters.Algorithm == plagiarism.AlgorithmShingle {
+		detector = plagiarism.NewShingleDetectorFor(report.Policy.Parameters)
+		threshold = report.Policy.Threshold
+	}
+
+	source, err := s.loadCompared(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+
+	others, err := s.workRepo.FindByAssignmentID(ctx, w.AssignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch works: %w", err)
+	}
+
+	var matches []*plagiarism.Comparison
+	for _, other := range others {
+		if other.ID == w.ID {
+			continue
+		}
+		match, err := s.loadCompared(ctx, other)
+		if err != nil {
+			fmt.Printf("Skipping work %s in evidence: %v\n", other.ID, err)
+			continue
+		}
+		comparison, err := plagiarism.NewComparison(detector, *source, *match, threshold)
+		if err != nil {
+			return nil, err
+		}
+		if comparison.Score > 0 {
+			matches = append(matches, comparison)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > topN {
+		matches = matches[:topN]
+	}
+
+	source.Excluded = detector.Exclusions(source.Text)
+
+	return &plagiarism.Evidence{
+		Report:     report,
+		Work:       *source,
+		Matches:    matches,
+		Threshold:  threshold,
+		Parameters: detector.Parameters(),
+	}, nil
+}
+
+func (s *SimilarityService) comparedText(ctx context.Context, workID uuid.UUID) (*plagiarism.ComparedText, error) {
+	w, err := s.workRepo.GetByID(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadCompared(ctx, w)
+}
+
+func (s *SimilarityService) loadCompared(ctx context.Context, w *work.Work) (*plagiarism.ComparedText, error) {
+	text, f, err := s.texts.LoadWithFile(ctx, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load text of work %s: %w", w.ID, err)
+	}
+
+	return &plagiarism.ComparedText{
+		WorkID:      w.ID,
+		StudentID:   w.StudentID,
+		SubmittedAt: w.SubmittedAt,
+		FileHash:    f.Hash,
+		Text:        text,
+	}, nil
+}
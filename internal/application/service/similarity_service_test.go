@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+)
+
+func TestSimilarityService_GetEvidenceUsesReportPolicy(t *testing.T) {
+	ctx := context.Background()
+	f := newServiceFixture()
+	assignmentID := uuid.New()
+	submitted := time.Now().Add(-time.Hour)
+	f.addWork(t, assignmentID, essay, submitted)
+	copied := f.addWork(t, assignmentID, essay, submitted.Add(time.Minute))
+
+	stored := f.detector.Parameters()
+	stored.ShingleLen = 5
+	require.NoError(t, f.reports.Save(ctx, plagiarism.NewReport(copied.ID, 1, plagiarism.Policy{Parameters: stored, Threshold: 0.9})))
+
+	svc := NewSimilarityService(f.works, f.reports, f.files, f.files, plainText{}, f.detector, 0.3)
+	evidence, err := svc.GetEvidence(ctx, copied.ID, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 0.9, evidence.Threshold, "порог из политики отчета, а не текущий")
+	assert.Equal(t, stored, evidence.Parameters)
+	require.Len(t, evidence.Matches, 1)
+	assert.Equal(t, 0.9, evidence.Matches[0].Threshold)
+	assert.Equal(t, 5, evidence.Matches[0].Parameters.ShingleLen)
+}
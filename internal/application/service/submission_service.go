@@ -3,8 +3,6 @@ package service
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"io"
 
@@ -20,11 +18,14 @@ type SubmissionService struct {
 	workRepo      work.Repository
 	fileRepo      file.Repository
 	plagRepo      plagiarism.Repository
+	store         workStore
 	fileStorage   file.Storage
 	textExtractor file.TextExtractor
 	detector      plagiarism.Detector
+	events        WebhookEvents
+	counterparts  *CounterpartUpdater
 
-	threshold float64
+	policy plagiarism.Policy
 }
 
 func NewSubmissionService(
@@ -34,15 +35,20 @@ func NewSubmissionService(
 	fs file.Storage,
 	te file.TextExtractor,
 	det plagiarism.Detector,
+	ev WebhookEvents,
+	policy plagiarism.Policy,
 ) *SubmissionService {
 	return &SubmissionService{
 		workRepo:      wr,
 		fileRepo:      fr,
 		plagRepo:      pr,
+		store:         workStore{workRepo: wr, fileRepo: fr, fileStorage: fs},
 		fileStorage:   fs,
 		textExtractor: te,
 		detector:      det,
-		threshold:     0.85,
+		events:        ev,
+		counterparts:  NewCounterpartUpdater(pr, det, ev, policy),
+		policy:        policy,
 	}
 }
 
@@ -63,43 +69,60 @@ func (s *SubmissionService) SubmitWork(
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	hash := sha256.Sum256(contentBytes)
-	hashString := hex.EncodeToString(hash[:])
-
-	fileID := uuid.New()
-	storagePath, err := s.fileStorage.Upload(ctx, fileID, bytes.NewReader(contentBytes))
+	workEntity, err := s.store.Create(ctx, assignmentID, studentID, fileName, mimeType, contentBytes)
 	if err != nil {
-		return nil, fmt.Errorf("storage upload failed: %w", err)
+		return nil, err
 	}
 
-	fileEntity := file.NewFile(fileName, storagePath, mimeType, hashString, fileSize)
-	fileEntity.ID = fileID
+	currentText, err := s.textExtractor.ExtractText(bytes.NewReader(contentBytes), mimeType)
+	if err != nil {
+		fmt.Printf("Error extracting text: %v\n", err)
+		currentText = ""
+	}
 
-	if err := s.fileRepo.Save(ctx, fileEntity); err != nil {
-		if delErr := s.fileStorage.Delete(ctx, storagePath); delErr != nil {
-			fmt.Printf("Failed to cleanup storage after DB error: %v\n", delErr)
+	tokens := s.detector.CountTokens(currentText)
+	status := "checked"
+	var report *plagiarism.Report
+	var counterparts []Counterpart
+	if s.detector.Sufficient(tokens) {
+		report, counterparts, err = s.analyze(ctx, workEntity, currentText, tokens)
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("file metadata save failed: %w", err)
+	} else {
+		report = plagiarism.NewInsufficientReport(workEntity.ID, tokens, s.detector.Exclusions(currentText), s.policy)
+		status = ReportStatusInsufficient
 	}
 
-	workEntity := work.NewWork(assignmentID, studentID, fileID)
-	if err := s.workRepo.Save(ctx, workEntity); err != nil {
-		return nil, fmt.Errorf("work save failed: %w", err)
+	if err := s.plagRepo.Save(ctx, report); err != nil {
+		return nil, fmt.Errorf("report save failed: %w", err)
 	}
+	s.events.ReportSaved(ctx, report, workEntity)
+	s.counterparts.AddMatches(ctx, workEntity, currentText, counterparts)
 
-	currentText, err := s.textExtractor.ExtractText(bytes.NewReader(contentBytes), mimeType)
-	if err != nil {
-		fmt.Printf("Error extracting text: %v\n", err)
-		currentText = ""
-	}
+	return &dto.SubmitWorkResponse{
+		WorkID:      workEntity.ID,
+		SubmittedAt: workEntity.SubmittedAt,
+		Plagiarism: dto.PlagiarismInfo{
+			IsPlagiarized: report.IsPlagiarized,
+			Score:         report.Score,
+			Status:        status,
+		},
+	}, nil
+}
 
-	otherWorks, err := s.workRepo.FindByAssignmentID(ctx, assignmentID)
+// analyze сравнивает новую работу с остальными работами задания и строит отчет.
+func (s *SubmissionService) analyze(ctx context.Context, workEntity *work.Work, currentText string, tokens int) (*plagiarism.Report, []Counterpart, error) {
+	otherWorks, err := s.workRepo.FindByAssignmentID(ctx, workEntity.AssignmentID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch previous works: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch previous works: %w", err)
 	}
 
 	maxScore := 0.0
 	var matchID *uuid.UUID
+	var matchText string
+	var matches []plagiarism.Match
+	var counterparts []Counterpart
 
 	for _, w := range otherWorks {
 		if w.ID == workEntity.ID {
@@ -122,37 +145,35 @@ func (s *SubmissionService) SubmitWork(
 			continue
 		}
 
-		score, err := s.detector.Compare(currentText, otherText)
+		overlap, err := s.detector.Overlap(currentText, otherText)
 		if err != nil {
 			continue
 		}
+		score := overlap.Similarity
+		matches = append(matches, plagiarism.NewMatch(w.ID, score, overlap.Containment, workEntity.SubmittedAt, w.SubmittedAt))
+		if score > 0 {
+			counterparts = append(counterparts, Counterpart{Work: w, Text: otherText, Overlap: overlap})
+		}
 
 		if score > maxScore {
 			maxScore = score
 			id := w.ID
 			matchID = &id
+			matchText = otherText
 		}
 	}
 
-	report := plagiarism.NewReport(workEntity.ID, maxScore, s.threshold)
-	if matchID != nil {
-		report.SetMatch(*matchID, plagiarism.AnalysisDetails{
-			AlgorithmUsed: "shingle",
-			TotalTokens:   len(currentText),
-		})
-	}
+	details := plagiarism.NewAnalysisDetails(
+		tokens,
+		s.detector.Passages(currentText, matchText),
+		s.detector.Exclusions(currentText),
+	)
 
-	if err := s.plagRepo.Save(ctx, report); err != nil {
-		return nil, fmt.Errorf("report save failed: %w", err)
+	report := plagiarism.NewReport(workEntity.ID, maxScore, s.policy)
+	report.Details = details
+	if matchID != nil {
+		report.SetMatch(*matchID, details)
 	}
-
-	return &dto.SubmitWorkResponse{
-		WorkID:      workEntity.ID,
-		SubmittedAt: workEntity.SubmittedAt,
-		Plagiarism: dto.PlagiarismInfo{
-			IsPlagiarized: report.IsPlagiarized,
-			Score:         report.Score,
-			Status:        "checked",
-		},
-	}, nil
+	report.SetMatches(matches)
+	return report, counterparts, nil
 }
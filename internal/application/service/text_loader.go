@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/file"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+// workTextLoader достает текст работы: метаданные файла -> хранилище -> извлечение текста.
+type workTextLoader struct {
+	fileRepo      file.Repository
+	fileStorage   file.Storage
+	textExtractor file.TextExtractor
+}
+
+func (l workTextLoader) Load(ctx context.Context, w *work.Work) (string, error) {
+	text, _, err := l.LoadWithFile(ctx, w)
+	return text, err
+}
+
+// LoadWithFile возвращает вместе с текстом метаданные файла (хеш, имя, MIME).
+func (l workTextLoader) LoadWithFile(ctx context.Context, w *work.Work) (string, *file.File, error) {
+	f, err := l.fileRepo.GetByID(ctx, w.FileID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rc, err := l.fileStorage.Download(ctx, f.StoragePath)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rc.Close()
+
+	text, err := l.textExtractor.ExtractText(rc, f.MimeType)
+	if err != nil {
+		return "", nil, err
+	}
+	return text, f, nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/review"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/webhook"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// WebhookEvents — события, которые сервисы передают подписчикам вебхуков. Ошибки публикации
+// только логируются: результат проверки уже сохранен и доступен через API.
+type WebhookEvents interface {
+	ReportSaved(ctx context.Context, r *plagiarism.Report, w *work.Work)
+	ReviewUpdated(ctx context.Context, rv *review.Review, isPlagiarized bool)
+}
+
+// WebhookService управляет подписками и ставит события в журнал доставок.
+// Отправкой занимается dispatch.Dispatcher.
+type WebhookService struct {
+	repo webhook.Repository
+	now  func() time.Time
+}
+
+func NewWebhookService(repo webhook.Repository) *WebhookService {
+	return &WebhookService{repo: repo, now: time.Now}
+}
+
+type CreateWebhookRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events" binding:"required,min=1"`
+	// Secret можно не передавать: тогда он будет сгенерирован.
+	Secret       string     `json:"secret,omitempty"`
+	AssignmentID *uuid.UUID `json:"assignment_id,omitempty"`
+}
+
+// WebhookInfo — описание подписки без секрета.
+type WebhookInfo struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	URL          string     `json:"url"`
+	Events       []string   `json:"events"`
+	AssignmentID *uuid.UUID `json:"assignment_id,omitempty"`
+	CreatedBy    uuid.UUID  `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CreatedWebhook возвращается один раз при создании вместе с секретом подписи.
+type CreatedWebhook struct {
+	WebhookInfo
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryInfo struct {
+	ID             uuid.UUID  `json:"id"`
+	SubscriptionID uuid.UUID  `json:"subscription_id"`
+	EventID        uuid.UUID  `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	ReplayOf       *uuid.UUID `json:"replay_of,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+func (s *WebhookService) Create(ctx context.Context, req CreateWebhookRequest, createdBy uuid.UUID) (*CreatedWebhook, error) {
+	events := make([]webhook.EventType, 0, len(req.Events))
+	for _, raw := range req.Events {
+		t, err := webhook.ParseEventType(raw)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, t)
+	}
+
+	sub, err := webhook.NewSubscription(req.Name, req.URL, events, req.Secret, req.AssignmentID, createdBy, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return &CreatedWebhook{WebhookInfo: webhookInfo(sub), Secret: sub.Secret}, nil
+}
+
+func (s *WebhookService) List(ctx context.Context) ([]WebhookInfo, error) {
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]WebhookInfo, len(subs))
+	for i, sub := range subs {
+		infos[i] = webhookInfo(sub)
+	}
+	return infos, nil
+}
+
+// Delete удаляет подписку; ожидающие доставки по ней будут отменены отправителем.
+func (s *WebhookService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+func (s *WebhookService) Deliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]WebhookDeliveryInfo, error) {
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	if limit > maxDeliveryLimit {
+		limit = maxDeliveryLimit
+	}
+	ds, err := s.repo.ListDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]WebhookDeliveryInfo, len(ds))
+	for i, d := range ds {
+		infos[i] = deliveryInfo(d)
+	}
+	return infos, nil
+}
+
+// Replay ставит событие доставки в очередь повторно, независимо от ее исхода.
+func (s *WebhookService) Replay(ctx context.Context, subscriptionID, deliveryID uuid.UUID) (*WebhookDeliveryInfo, error) {
+	d, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d.SubscriptionID != subscriptionID {
+		return nil, shared.ErrNotFound
+	}
+	if _, err := s.repo.GetSubscription(ctx, d.SubscriptionID); err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			return nil, fmt.Errorf("%w: subscription of this delivery was deleted", shared.ErrInvalidInput)
+		}
+		return nil, err
+	}
+
+	replay := d.Replay(s.now())
+	if err := s.repo.SaveDeliveries(ctx, []*webhook.Delivery{replay}); err != nil {
+		return nil, err
+	}
+	info := deliveryInfo(replay)
+	return &info, nil
+}
+
+// Publish создает доставки события для всех подходящих подписок.
+func (s *WebhookService) Publish(ctx context.Context, e webhook.Event) error {
+	subs, err := s.repo.SubscriptionsFor(ctx, e.Type)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	var ds []*webhook.Delivery
+	for _, sub := range subs {
+		if sub.Matches(e) {
+			ds = append(ds, webhook.NewDelivery(sub, e, now))
+		}
+	}
+	return s.repo.SaveDeliveries(ctx, ds)
+}
+
+// ReportEventData — данные событий report.created и report.flagged.
+type ReportEventData struct {
+	ReportID        uuid.UUID  `json:"report_id"`
+	WorkID          uuid.UUID  `json:"work_id"`
+	AssignmentID    uuid.UUID  `json:"assignment_id"`
+	StudentID       uuid.UUID  `json:"student_id"`
+	IsPlagiarized   bool       `json:"is_plagiarized"`
+	SimilarityScore float64    `json:"similarity_score"`
+	Coverage        float64    `json:"coverage"`
+	MatchedWorkID   *uuid.UUID `json:"matched_work_id,omitempty"`
+	Severity        string     `json:"severity,omitempty"`
+	Explanation     string     `json:"explanation,omitempty"`
+	CreatedAt       string     `json:"created_at"`
+}
+
+// ReviewEventData — данные события review.updated.
+type ReviewEventData struct {
+	ReportID           uuid.UUID  `json:"report_id"`
+	WorkID             uuid.UUID  `json:"work_id"`
+	AssignmentID       uuid.UUID  `json:"assignment_id"`
+	State              string     `json:"state"`
+	Verdict            string     `json:"verdict,omitempty"`
+	AssigneeID         *uuid.UUID `json:"assignee_id,omitempty"`
+	OverridesAutomatic bool       `json:"overrides_automatic"`
+	UpdatedAt          string     `json:"updated_at"`
+}
+
+func (s *WebhookService) ReportSaved(ctx context.Context, r *plagiarism.Report, w *work.Work) {
+	data := ReportEventData{
+		ReportID:        r.ID,
+		WorkID:          w.ID,
+		AssignmentID:    w.AssignmentID,
+		StudentID:       w.StudentID,
+		IsPlagiarized:   r.IsPlagiarized,
+		SimilarityScore: r.Score,
+		Coverage:        r.Details.Coverage,
+		MatchedWorkID:   r.MatchedWorkID,
+		CreatedAt:       formatTimestamp(r.CreatedAt),
+	}
+	if r.Decision != nil {
+		data.Severity = string(r.Decision.Severity)
+		data.Explanation = r.Decision.Explanation()
+	}
+	s.publish(ctx, webhook.EventReportCreated, w.AssignmentID, data)
+	if r.IsPlagiarized {
+		s.publish(ctx, webhook.EventReportFlagged, w.AssignmentID, data)
+	}
+}
+
+func (s *WebhookService) ReviewUpdated(ctx context.Context, rv *review.Review, isPlagiarized bool) {
+	s.publish(ctx, webhook.EventReviewUpdated, rv.AssignmentID, ReviewEventData{
+		ReportID:           rv.ReportID,
+		WorkID:             rv.WorkID,
+		AssignmentID:       rv.AssignmentID,
+		State:              string(rv.State()),
+		Verdict:            string(rv.Verdict),
+		AssigneeID:         rv.AssigneeID,
+		OverridesAutomatic: rv.Overrides(isPlagiarized),
+		UpdatedAt:          formatTimestamp(rv.UpdatedAt),
+	})
+}
+
+func (s *WebhookService) publish(ctx context.Context, t webhook.EventType, assignmentID uuid.UUID, data interface{}) {
+	e, err := webhook.NewEvent(t, assignmentID, data, s.now())
+	if err == nil {
+		err = s.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Printf("Failed to publish %s webhook event: %v", t, err)
+	}
+}
+
+func webhookInfo(sub *webhook.Subscription) WebhookInfo {
+	events := make([]string, len(sub.Events))
+	for i, e := range sub.Events {
+		events[i] = string(e)
+	}
+	return WebhookInfo{
+		ID:           sub.ID,
+		Name:         sub.Name,
+		URL:          sub.URL,
+		Events:       events,
+		AssignmentID: sub.AssignmentID,
+		CreatedBy:    sub.CreatedBy,
+		CreatedAt:    sub.CreatedAt,
+	}
+}
+
+func deliveryInfo(d *webhook.Delivery) WebhookDeliveryInfo {
+	info := WebhookDeliveryInfo{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		ReplayOf:       d.ReplayOf,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	if d.Status == webhook.DeliveryPending {
+		next := d.NextAttemptAt
+		info.NextAttemptAt = &next
+	}
+	return info
+}
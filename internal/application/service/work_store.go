@@ -0,0 +1,49 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/file"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+)
+
+// workStore сохраняет содержимое работы в хранилище, его метаданные и саму работу.
+// Общий путь для одиночной загрузки и пакетного импорта.
+type workStore struct {
+	workRepo    work.Repository
+	fileRepo    file.Repository
+	fileStorage file.Storage
+}
+
+func (s workStore) Create(ctx context.Context, assignmentID, studentID uuid.UUID, fileName, mimeType string, content []byte) (*work.Work, error) {
+	hash := sha256.Sum256(content)
+	hashString := hex.EncodeToString(hash[:])
+
+	fileID := uuid.New()
+	storagePath, err := s.fileStorage.Upload(ctx, fileID, bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("storage upload failed: %w", err)
+	}
+
+	fileEntity := file.NewFile(fileName, storagePath, mimeType, hashString, int64(len(content)))
+	fileEntity.ID = fileID
+
+	if err := s.fileRepo.Save(ctx, fileEntity); err != nil {
+		if delErr := s.fileStorage.Delete(ctx, storagePath); delErr != nil {
+			fmt.Printf("Failed to cleanup storage after DB error: %v\n", delErr)
+		}
+		return nil, fmt.Errorf("file metadata save failed: %w", err)
+	}
+
+	workEntity := work.NewWork(assignmentID, studentID, fileID)
+	if err := s.workRepo.Save(ctx, workEntity); err != nil {
+		return nil, fmt.Errorf("work save failed: %w", err)
+	}
+	return workEntity, nil
+}
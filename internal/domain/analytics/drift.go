@@ -0,0 +1,156 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// MinBaselineWorks — сколько прежних работ нужно, чтобы судить о «привычном» стиле.
+	MinBaselineWorks = 2
+	// DriftZThreshold — отклонение признака (в стандартных отклонениях базы), считающееся необычным.
+	DriftZThreshold = 2.5
+	// DriftFlagMinFeatures — сколько необычных признаков нужно для флага: один выброс встречается часто.
+	DriftFlagMinFeatures = 2
+
+	// relativeSpreadFloor не дает разбросу базы быть меньше 10% среднего: при двух-трех
+	// очень похожих работах иначе любое мелкое отличие давало бы огромный z.
+	relativeSpreadFloor = 0.1
+	distanceFloor       = 0.05
+)
+
+type FeatureDrift struct {
+	Feature  string  `json:"feature"`
+	Value    float64 `json:"value"`
+	Baseline float64 `json:"baseline"`
+	Spread   float64 `json:"spread"`
+	ZScore   float64 `json:"z_score"`
+	Unusual  bool    `json:"unusual"`
+}
+
+// StyleDrift — сравнение профиля работы с прежними работами того же студента.
+type StyleDrift struct {
+	BaselineWorks   int            `json:"baseline_works"`
+	Sufficient      bool           `json:"sufficient"`
+	LanguageChanged bool           `json:"language_changed"`
+	Features        []FeatureDrift `json:"features"`
+	Flagged         bool           `json:"flagged"`
+	Reasons         []string       `json:"reasons"`
+}
+
+type scalarFeature struct {
+	name  string
+	value func(StyleProfile) float64
+}
+
+var driftFeatures = []scalarFeature{
+	{"sentence_length_mean", func(p StyleProfile) float64 { return p.SentenceLength.Mean }},
+	{"sentence_length_std_dev", func(p StyleProfile) float64 { return p.SentenceLength.StdDev }},
+	{"type_token_ratio", func(p StyleProfile) float64 { return p.TypeTokenRatio }},
+	{"avg_word_length", func(p StyleProfile) float64 { return p.Readability.AvgWordLength }},
+	{"flesch_reading_ease", func(p StyleProfile) float64 { return p.Readability.FleschReadingEase }},
+	{"commas_per_1000", func(p StyleProfile) float64 { return p.Punctuation[","] }},
+}
+
+// CompareStyle сопоставляет профиль с базой прежних работ. Для скалярных признаков считается
+// z-оценка, для частот служебных слов и пунктуации — косинусное расстояние до среднего профиля
+// базы, которое сравнивается с разбросом самих работ базы вокруг этого среднего.
+func CompareStyle(current StyleProfile, baseline []StyleProfile) StyleDrift {
+	d := StyleDrift{
+		BaselineWorks: len(baseline),
+		Sufficient:    len(baseline) >= MinBaselineWorks,
+		Features:      []FeatureDrift{},
+		Reasons:       []string{},
+	}
+	if !d.Sufficient {
+		return d
+	}
+
+	for _, f := range driftFeatures {
+		values := make([]float64, len(baseline))
+		for i, p := range baseline {
+			values[i] = f.value(p)
+		}
+		mean, sd := meanStdDev(values)
+		d.Features = append(d.Features, featureDrift(f.name, f.value(current), mean,
+			math.Max(sd, relativeSpreadFloor*math.Abs(mean))))
+	}
+
+	// Частоты служебных слов разных языков несопоставимы.
+	sameLanguage := make([]StyleProfile, 0, len(baseline))
+	for _, p := range baseline {
+		if p.Language == current.Language {
+			sameLanguage = append(sameLanguage, p)
+		}
+	}
+	d.LanguageChanged = len(sameLanguage) == 0
+	if len(sameLanguage) >= MinBaselineWorks {
+		d.Features = append(d.Features,
+			distanceDrift("function_word_distance", current, sameLanguage, func(p StyleProfile) map[string]float64 { return p.FunctionWords }),
+			distanceDrift("punctuation_distance", current, sameLanguage, func(p StyleProfile) map[string]float64 { return p.Punctuation }),
+		)
+	}
+
+	for _, f := range d.Features {
+		if f.Unusual {
+			d.Reasons = append(d.Reasons, f.Feature)
+		}
+	}
+	d.Flagged = len(d.Reasons) >= DriftFlagMinFeatures
+	return d
+}
+
+func featureDrift(name string, value, baseline, spread float64) FeatureDrift {
+	f := FeatureDrift{Feature: name, Value: value, Baseline: baseline, Spread: spread}
+	if spread > 0 {
+		f.ZScore = (value - baseline) / spread
+	}
+	f.Unusual = math.Abs(f.ZScore) >= DriftZThreshold
+	return f
+}
+
+// distanceDrift: Value — расстояние текущей работы до центра базы, Baseline/Spread —
+// среднее и разброс расстояний работ базы до того же центра.
+func distanceDrift(name string, current StyleProfile, baseline []StyleProfile, vector func(StyleProfile) map[string]float64) FeatureDrift {
+	center := make(map[string]float64)
+	for _, p := range baseline {
+		for k, v := range vector(p) {
+			center[k] += v / float64(len(baseline))
+		}
+	}
+
+	distances := make([]float64, len(baseline))
+	for i, p := range baseline {
+		distances[i] = cosineDistance(vector(p), center)
+	}
+	mean, sd := meanStdDev(distances)
+
+	return featureDrift(name, cosineDistance(vector(current), center), mean, math.Max(sd, distanceFloor))
+}
+
+func cosineDistance(a, b map[string]float64) float64 {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	dot, na, nb := 0.0, 0.0, 0.0
+	for _, k := range keys {
+		dot += a[k] * b[k]
+		na += a[k] * a[k]
+		nb += b[k] * b[k]
+	}
+	if na == 0 || nb == 0 {
+		if na == nb {
+			return 0
+		}
+		return 1
+	}
+	return 1 - dot/math.Sqrt(na*nb)
+}
@@ -0,0 +1,40 @@
+package analytics
+
+// stopwords — служебные слова русского и английского языков, не несущие смысла при подсчете частот.
+var stopwords = map[string]struct{}{
+	"a": {}, "about": {}, "above": {}, "after": {}, "again": {}, "against": {}, "all": {}, "also": {},
+	"am": {}, "an": {}, "and": {}, "any": {}, "are": {}, "as": {}, "at": {}, "be": {}, "because": {},
+	"been": {}, "before": {}, "being": {}, "below": {}, "between": {}, "both": {}, "but": {},
+	"by": {}, "can": {}, "could": {}, "did": {}, "do": {}, "does": {}, "doing": {}, "down": {},
+	"during": {}, "each": {}, "etc": {}, "few": {}, "for": {}, "from": {}, "further": {}, "had": {},
+	"has": {}, "have": {}, "having": {}, "he": {}, "her": {}, "here": {}, "hers": {}, "herself": {},
+	"him": {}, "himself": {}, "his": {}, "how": {}, "i": {}, "if": {}, "in": {}, "into": {}, "is": {},
+	"it": {}, "its": {}, "itself": {}, "just": {}, "may": {}, "me": {}, "might": {}, "more": {},
+	"most": {}, "must": {}, "my": {}, "myself": {}, "no": {}, "nor": {}, "not": {}, "now": {},
+	"of": {}, "off": {}, "on": {}, "once": {}, "only": {}, "or": {}, "other": {}, "our": {},
+	"ours": {}, "ourselves": {}, "out": {}, "over": {}, "own": {}, "same": {}, "shall": {}, "she": {},
+	"should": {}, "so": {}, "some": {}, "such": {}, "than": {}, "that": {}, "the": {}, "their": {},
+	"theirs": {}, "them": {}, "themselves": {}, "then": {}, "there": {}, "these": {}, "they": {},
+	"this": {}, "those": {}, "through": {}, "to": {}, "too": {}, "under": {}, "until": {}, "up": {},
+	"upon": {}, "very": {}, "via": {}, "was": {}, "we": {}, "were": {}, "what": {}, "when": {},
+	"where": {}, "which": {}, "while": {}, "who": {}, "whom": {}, "why": {}, "will": {}, "with": {},
+	"would": {}, "you": {}, "your": {}, "yours": {}, "yourself": {}, "yourselves": {}, "а": {},
+	"без": {}, "более": {}, "будет": {}, "будут": {}, "бы": {}, "был": {}, "была": {}, "были": {},
+	"было": {}, "быть": {}, "в": {}, "вам": {}, "вас": {}, "весь": {}, "во": {}, "вообще": {},
+	"вот": {}, "все": {}, "всего": {}, "всех": {}, "всё": {}, "вы": {}, "где": {}, "да": {},
+	"даже": {}, "для": {}, "до": {}, "его": {}, "ее": {}, "если": {}, "есть": {}, "еще": {},
+	"ещё": {}, "её": {}, "же": {}, "за": {}, "здесь": {}, "и": {}, "из": {}, "или": {}, "им": {},
+	"именно": {}, "их": {}, "к": {}, "как": {}, "ко": {}, "когда": {}, "которая": {}, "которое": {},
+	"которые": {}, "который": {}, "которых": {}, "кто": {}, "ли": {}, "либо": {}, "лишь": {},
+	"между": {}, "меня": {}, "мне": {}, "много": {}, "может": {}, "можно": {}, "мой": {}, "мы": {},
+	"на": {}, "над": {}, "надо": {}, "наш": {}, "не": {}, "него": {}, "нее": {}, "нет": {}, "неё": {},
+	"ни": {}, "них": {}, "но": {}, "ну": {}, "о": {}, "об": {}, "однако": {}, "он": {}, "она": {},
+	"они": {}, "оно": {}, "от": {}, "очень": {}, "по": {}, "под": {}, "после": {}, "потому": {},
+	"поэтому": {}, "при": {}, "про": {}, "раз": {}, "с": {}, "сам": {}, "сама": {}, "сами": {},
+	"само": {}, "своего": {}, "своей": {}, "свои": {}, "своих": {}, "свой": {}, "себе": {},
+	"себя": {}, "со": {}, "так": {}, "также": {}, "такой": {}, "там": {}, "те": {}, "тем": {},
+	"то": {}, "тогда": {}, "того": {}, "тоже": {}, "той": {}, "только": {}, "том": {}, "ты": {},
+	"у": {}, "уже": {}, "хотя": {}, "чего": {}, "чей": {}, "чем": {}, "что": {}, "чтобы": {},
+	"чье": {}, "чья": {}, "эта": {}, "эти": {}, "этих": {}, "это": {}, "этого": {}, "этой": {},
+	"этом": {}, "этот": {}, "я": {}, "является": {}, "являются": {},
+}
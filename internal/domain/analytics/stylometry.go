@@ -0,0 +1,295 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	LanguageRussian = "ru"
+	LanguageEnglish = "en"
+)
+
+// sentenceLengthBuckets — верхние границы корзин гистограммы длины предложения в словах.
+var sentenceLengthBuckets = []int{5, 10, 15, 20, 30, 40}
+
+// functionWords — частотные служебные слова, по которым обычно различают авторов.
+// Их употребление мало зависит от темы текста и плохо контролируется пишущим.
+var functionWords = map[string][]string{
+	LanguageRussian: {
+		"и", "в", "не", "на", "что", "с", "по", "как", "а", "но", "к", "это", "из", "у", "за",
+		"от", "о", "же", "для", "то", "так", "бы", "или", "ли", "если", "уже", "даже", "только",
+		"также", "поэтому",
+	},
+	LanguageEnglish: {
+		"the", "of", "and", "to", "a", "in", "that", "is", "it", "for", "as", "with", "was", "on",
+		"by", "but", "not", "be", "this", "which", "or", "from", "at", "an", "however", "therefore",
+		"thus", "also", "although", "whereas",
+	},
+}
+
+// punctuationMarks — знаки, частота которых входит в пунктуационный профиль.
+var punctuationMarks = []string{",", ".", ";", ":", "!", "?", "—", "-", "(", "\"", "«"}
+
+type SentenceLengthStats struct {
+	Count     int            `json:"count"`
+	Mean      float64        `json:"mean"`
+	Median    float64        `json:"median"`
+	StdDev    float64        `json:"std_dev"`
+	Min       int            `json:"min"`
+	Max       int            `json:"max"`
+	Histogram map[string]int `json:"histogram"`
+}
+
+type Readability struct {
+	// FleschReadingEase: для русского — адаптация Оборневой (206.835 − 1.3·ASL − 60.1·ASW).
+	FleschReadingEase   float64 `json:"flesch_reading_ease"`
+	FleschKincaidGrade  float64 `json:"flesch_kincaid_grade"`
+	AvgSentenceLength   float64 `json:"avg_sentence_length"`
+	AvgSyllablesPerWord float64 `json:"avg_syllables_per_word"`
+	AvgWordLength       float64 `json:"avg_word_length"`
+}
+
+// StyleProfile — стилометрические признаки одного текста. Частоты служебных слов
+// и знаков препинания нормированы на 1000 слов, чтобы тексты разной длины были сравнимы.
+type StyleProfile struct {
+	Language       string              `json:"language"`
+	Words          int                 `json:"words"`
+	SentenceLength SentenceLengthStats `json:"sentence_length"`
+	TypeTokenRatio float64             `json:"type_token_ratio"`
+	FunctionWords  map[string]float64  `json:"function_words"`
+	Punctuation    map[string]float64  `json:"punctuation"`
+	Readability    Readability         `json:"readability"`
+}
+
+// AnalyzeStyle строит стилометрический профиль текста.
+func AnalyzeStyle(text string) StyleProfile {
+	words := Words(text)
+	lang := DetectLanguage(text)
+
+	p := StyleProfile{
+		Language:       lang,
+		Words:          len(words),
+		SentenceLength: sentenceLengthStats(Sentences(text)),
+		TypeTokenRatio: typeTokenRatio(words),
+		FunctionWords:  make(map[string]float64),
+		Punctuation:    make(map[string]float64),
+	}
+
+	counts := make(map[string]int, len(words))
+	for _, w := range words {
+		counts[w]++
+	}
+	for _, fw := range functionWords[lang] {
+		p.FunctionWords[fw] = per1000(counts[fw], len(words))
+	}
+	for _, mark := range punctuationMarks {
+		p.Punctuation[mark] = per1000(strings.Count(text, mark), len(words))
+	}
+
+	p.Readability = readability(words, p.SentenceLength, lang)
+	return p
+}
+
+// DetectLanguage выбирает русский, если кириллических букв больше, чем латинских.
+func DetectLanguage(text string) string {
+	cyrillic, latin := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if cyrillic > latin {
+		return LanguageRussian
+	}
+	return LanguageEnglish
+}
+
+// Sentences возвращает число слов в каждом непустом предложении текста.
+func Sentences(text string) []int {
+	var lengths []int
+	for _, sentence := range splitSentences(text) {
+		if n := len(Words(sentence)); n > 0 {
+			lengths = append(lengths, n)
+		}
+	}
+	return lengths
+}
+
+// splitSentences делит текст на предложения по '.', '!', '?', '…' и пустым строкам.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i, r := range text {
+		switch r {
+		case '.', '!', '?', '…':
+			sentences = append(sentences, text[start:i])
+			start = i + len(string(r))
+		case '\n':
+			if strings.HasPrefix(text[i+1:], "\n") || strings.HasPrefix(text[i+1:], "\r\n") {
+				sentences = append(sentences, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(sentences, text[start:])
+}
+
+func sentenceLengthStats(lengths []int) SentenceLengthStats {
+	s := SentenceLengthStats{Count: len(lengths), Histogram: make(map[string]int)}
+	for i, upper := range sentenceLengthBuckets {
+		lower := 1
+		if i > 0 {
+			lower = sentenceLengthBuckets[i-1] + 1
+		}
+		s.Histogram[bucketLabel(lower, upper)] = 0
+	}
+	s.Histogram[bucketLabel(sentenceLengthBuckets[len(sentenceLengthBuckets)-1]+1, 0)] = 0
+
+	if len(lengths) == 0 {
+		return s
+	}
+
+	sorted := append([]int(nil), lengths...)
+	sort.Ints(sorted)
+	s.Min, s.Max = sorted[0], sorted[len(sorted)-1]
+	if n := len(sorted); n%2 == 1 {
+		s.Median = float64(sorted[n/2])
+	} else {
+		s.Median = float64(sorted[n/2-1]+sorted[n/2]) / 2
+	}
+
+	values := make([]float64, len(lengths))
+	for i, l := range lengths {
+		values[i] = float64(l)
+		s.Histogram[histogramBucket(l)]++
+	}
+	s.Mean, s.StdDev = meanStdDev(values)
+
+	return s
+}
+
+func histogramBucket(length int) string {
+	lower := 1
+	for _, upper := range sentenceLengthBuckets {
+		if length <= upper {
+			return bucketLabel(lower, upper)
+		}
+		lower = upper + 1
+	}
+	return bucketLabel(lower, 0)
+}
+
+// bucketLabel: upper == 0 означает открытую корзину «N+».
+func bucketLabel(lower, upper int) string {
+	if upper == 0 {
+		return strconv.Itoa(lower) + "+"
+	}
+	return strconv.Itoa(lower) + "-" + strconv.Itoa(upper)
+}
+
+// typeTokenRatio считается по первым ttrWindow словам: TTR сильно падает с длиной
+// текста, и без окна короткая работа всегда выглядела бы «богаче» длинной.
+const ttrWindow = 1000
+
+func typeTokenRatio(words []string) float64 {
+	if len(words) == 0 {
+		return 0
+	}
+	if len(words) > ttrWindow {
+		words = words[:ttrWindow]
+	}
+	types := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		types[w] = struct{}{}
+	}
+	return float64(len(types)) / float64(len(words))
+}
+
+func readability(words []string, sentences SentenceLengthStats, lang string) Readability {
+	if len(words) == 0 || sentences.Count == 0 {
+		return Readability{}
+	}
+
+	syllables, letters := 0, 0
+	for _, w := range words {
+		syllables += Syllables(w, lang)
+		letters += len([]rune(w))
+	}
+
+	asl := float64(len(words)) / float64(sentences.Count)
+	asw := float64(syllables) / float64(len(words))
+	r := Readability{
+		AvgSentenceLength:   asl,
+		AvgSyllablesPerWord: asw,
+		AvgWordLength:       float64(letters) / float64(len(words)),
+	}
+
+	if lang == LanguageRussian {
+		r.FleschReadingEase = 206.835 - 1.3*asl - 60.1*asw
+		r.FleschKincaidGrade = 0.5*asl + 8.4*asw - 15.59
+	} else {
+		r.FleschReadingEase = 206.835 - 1.015*asl - 84.6*asw
+		r.FleschKincaidGrade = 0.39*asl + 11.8*asw - 15.59
+	}
+	return r
+}
+
+// Syllables оценивает число слогов: в русском — по числу гласных,
+// в английском — по группам гласных без немой конечной «e». Минимум один слог.
+func Syllables(word, lang string) int {
+	n := 0
+	if lang == LanguageRussian {
+		for _, r := range word {
+			if strings.ContainsRune("аеёиоуыэюя", r) {
+				n++
+			}
+		}
+	} else {
+		prevVowel := false
+		for _, r := range word {
+			vowel := strings.ContainsRune("aeiouy", r)
+			if vowel && !prevVowel {
+				n++
+			}
+			prevVowel = vowel
+		}
+		if strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") && n > 1 {
+			n--
+		}
+	}
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+func per1000(count, words int) float64 {
+	if words == 0 {
+		return 0
+	}
+	return float64(count) * 1000 / float64(words)
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const plainStyle = "Я пошел в магазин. Там было много людей. Я купил хлеб и молоко. Потом я вернулся домой. " +
+	"Дома я поел и лег спать. Утром я снова пошел на учебу. На учебе было скучно. Я сидел и слушал."
+
+const ornateStyle = "Несмотря на очевидную сложность поставленной задачи, исследователи, опираясь на обширный " +
+	"эмпирический материал, последовательно сформулировали методологические принципы; тем не менее, " +
+	"интерпретация полученных результатов, безусловно, требует дополнительной верификации, " +
+	"поскольку существующие теоретические конструкции, по-видимому, недостаточно учитывают " +
+	"многообразие социокультурных факторов, определяющих поведение индивидов в современном обществе."
+
+func TestAnalyzeStyle_Basics(t *testing.T) {
+	p := AnalyzeStyle(plainStyle)
+
+	assert.Equal(t, LanguageRussian, p.Language)
+	assert.Equal(t, 8, p.SentenceLength.Count)
+	assert.InDelta(t, 37.0/8, p.SentenceLength.Mean, 1e-9)
+	assert.Greater(t, p.FunctionWords["и"], 0.0)
+	assert.Greater(t, p.Punctuation["."], 0.0)
+	assert.Greater(t, p.TypeTokenRatio, 0.0)
+	assert.LessOrEqual(t, p.TypeTokenRatio, 1.0)
+
+	ornate := AnalyzeStyle(ornateStyle)
+	assert.Greater(t, ornate.SentenceLength.Mean, p.SentenceLength.Mean)
+	assert.Less(t, ornate.Readability.FleschReadingEase, p.Readability.FleschReadingEase,
+		"канцелярский текст должен читаться тяжелее")
+}
+
+func TestAnalyzeStyle_English(t *testing.T) {
+	p := AnalyzeStyle("The cat sat on the mat. It was happy. However, the dog was not.")
+
+	assert.Equal(t, LanguageEnglish, p.Language)
+	assert.Equal(t, 3, p.SentenceLength.Count)
+	assert.Greater(t, p.FunctionWords["the"], 0.0)
+	assert.Greater(t, p.Readability.FleschReadingEase, 60.0)
+}
+
+func TestSyllables(t *testing.T) {
+	assert.Equal(t, 3, Syllables("молоко", LanguageRussian))
+	assert.Equal(t, 1, Syllables("вз", LanguageRussian))
+	assert.Equal(t, 1, Syllables("cake", LanguageEnglish))
+	assert.Equal(t, 3, Syllables("readable", LanguageEnglish))
+}
+
+func TestCompareStyle_InsufficientBaseline(t *testing.T) {
+	d := CompareStyle(AnalyzeStyle(ornateStyle), []StyleProfile{AnalyzeStyle(plainStyle)})
+
+	assert.False(t, d.Sufficient)
+	assert.False(t, d.Flagged)
+	assert.Empty(t, d.Features)
+}
+
+func TestCompareStyle_FlagsGhostWriting(t *testing.T) {
+	baseline := []StyleProfile{
+		AnalyzeStyle(plainStyle),
+		AnalyzeStyle(strings.ReplaceAll(plainStyle, "магазин", "парк")),
+		AnalyzeStyle(plainStyle + " Вечером я гулял."),
+	}
+
+	same := CompareStyle(AnalyzeStyle(plainStyle+" Было тепло."), baseline)
+	assert.True(t, same.Sufficient)
+	assert.False(t, same.Flagged, "работа в привычном стиле не должна помечаться: %v", same.Reasons)
+
+	drift := CompareStyle(AnalyzeStyle(ornateStyle), baseline)
+	assert.True(t, drift.Flagged)
+	assert.Contains(t, drift.Reasons, "sentence_length_mean")
+}
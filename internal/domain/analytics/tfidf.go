@@ -0,0 +1,206 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+type Keyword struct {
+	Word  string  `json:"word"`
+	Count int     `json:"count"`
+	TF    float64 `json:"tf"`
+	IDF   float64 `json:"idf"`
+	Score float64 `json:"score"`
+}
+
+type VocabularyEntry struct {
+	Word              string `json:"word"`
+	Count             int    `json:"count"`
+	DocumentFrequency int    `json:"document_frequency"`
+}
+
+// TermIndex — частоты значимых слов по набору документов (работ одного задания).
+// Документы адресуются индексом в исходном срезе текстов.
+type TermIndex struct {
+	counts []map[string]int
+	totals []int
+	df     map[string]int
+}
+
+// NewTermIndex строит индекс, отбрасывая стоп-слова, числа и слова короче minLen рун.
+func NewTermIndex(texts []string, minLen int) *TermIndex {
+	x := &TermIndex{
+		counts: make([]map[string]int, len(texts)),
+		totals: make([]int, len(texts)),
+		df:     make(map[string]int),
+	}
+	for i, text := range texts {
+		counts := make(map[string]int)
+		for _, w := range Words(text) {
+			if len([]rune(w)) < minLen || IsStopword(w) || isNumber(w) {
+				continue
+			}
+			counts[w]++
+			x.totals[i]++
+		}
+		for w := range counts {
+			x.df[w]++
+		}
+		x.counts[i] = counts
+	}
+	return x
+}
+
+func (x *TermIndex) Len() int {
+	return len(x.counts)
+}
+
+// IDF — сглаженная обратная документная частота ln((1+N)/(1+df)) + 1: слово, встречающееся
+// во всех работах, получает вес 1, а не 0, чтобы работа без уникальных слов не оставалась пустой.
+func (x *TermIndex) IDF(word string) float64 {
+	return math.Log(float64(1+len(x.counts))/float64(1+x.df[word])) + 1
+}
+
+// Keywords возвращает limit слов документа doc с наибольшим TF-IDF; limit <= 0 — все.
+func (x *TermIndex) Keywords(doc, limit int) []Keyword {
+	result := make([]Keyword, 0, len(x.counts[doc]))
+	for w, c := range x.counts[doc] {
+		tf := float64(c) / float64(x.totals[doc])
+		idf := x.IDF(w)
+		result = append(result, Keyword{Word: w, Count: c, TF: tf, IDF: idf, Score: tf * idf})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Word < result[j].Word
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// Vocabulary — общий словарь задания по убыванию суммарной частоты; limit <= 0 — весь.
+func (x *TermIndex) Vocabulary(limit int) []VocabularyEntry {
+	totals := make(map[string]int, len(x.df))
+	for _, counts := range x.counts {
+		for w, c := range counts {
+			totals[w] += c
+		}
+	}
+
+	result := make([]VocabularyEntry, 0, len(totals))
+	for w, c := range totals {
+		result = append(result, VocabularyEntry{Word: w, Count: c, DocumentFrequency: x.df[w]})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Word < result[j].Word
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// PhraseGroup — набор работ и общие только для них редкие фразы.
+// Чем больше таких фраз у одной группы, тем сильнее сигнал сговора.
+type PhraseGroup struct {
+	Documents []int    `json:"-"`
+	Phrases   []string `json:"phrases"`
+}
+
+type RarePhraseOptions struct {
+	// N — длина фразы в словах.
+	N int
+	// MaxGroup — фраза считается редкой, если встречается не более чем в MaxGroup работах.
+	MaxGroup int
+	// MinContentWords — сколько слов фразы должны быть значимыми (не стоп-словами):
+	// иначе в результат попадают обороты вроде «в то же время».
+	MinContentWords int
+}
+
+func DefaultRarePhraseOptions() RarePhraseOptions {
+	return RarePhraseOptions{N: 4, MaxGroup: 3, MinContentWords: 2}
+}
+
+// RarePhrases находит n-граммы, общие для 2..MaxGroup документов, и группирует их
+// по набору документов. N-граммы не пересекают границы предложений и абзацев.
+// Группы отсортированы по числу общих фраз (по убыванию).
+func RarePhrases(texts []string, opts RarePhraseOptions) []PhraseGroup {
+	docsByPhrase := make(map[string][]int)
+	for i, text := range texts {
+		seen := make(map[string]struct{})
+		for _, sentence := range splitSentences(text) {
+			words := Words(sentence)
+			for j := 0; j+opts.N <= len(words); j++ {
+				gram := words[j : j+opts.N]
+				if contentWords(gram) < opts.MinContentWords {
+					continue
+				}
+				phrase := strings.Join(gram, " ")
+				if _, ok := seen[phrase]; ok {
+					continue
+				}
+				seen[phrase] = struct{}{}
+				docsByPhrase[phrase] = append(docsByPhrase[phrase], i)
+			}
+		}
+	}
+
+	groups := make(map[string]*PhraseGroup)
+	for phrase, docs := range docsByPhrase {
+		if len(docs) < 2 || len(docs) > opts.MaxGroup {
+			continue
+		}
+		key := groupKey(docs)
+		g, ok := groups[key]
+		if !ok {
+			g = &PhraseGroup{Documents: docs}
+			groups[key] = g
+		}
+		g.Phrases = append(g.Phrases, phrase)
+	}
+
+	result := make([]PhraseGroup, 0, len(groups))
+	for _, g := range groups {
+		sort.Strings(g.Phrases)
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].Phrases) != len(result[j].Phrases) {
+			return len(result[i].Phrases) > len(result[j].Phrases)
+		}
+		return groupKey(result[i].Documents) < groupKey(result[j].Documents)
+	})
+	return result
+}
+
+func contentWords(words []string) int {
+	n := 0
+	for _, w := range words {
+		if !IsStopword(w) && !isNumber(w) {
+			n++
+		}
+	}
+	return n
+}
+
+// groupKey — документы добавляются по возрастанию индекса, поэтому ключ однозначен.
+func groupKey(docs []int) string {
+	var b strings.Builder
+	for i, d := range docs {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%06d", d)
+	}
+	return b.String()
+}
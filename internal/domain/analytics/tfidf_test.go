@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var assignmentTexts = []string{
+	"Микросервисы общаются через брокер сообщений. Брокер сообщений обеспечивает доставку.",
+	"Микросервисы используют базу данных. Репликация базы данных повышает надежность.",
+	"Микросервисы развертываются в контейнерах. Оркестратор контейнеров следит за ними.",
+}
+
+func TestTermIndex_KeywordsPreferDistinctiveWords(t *testing.T) {
+	x := NewTermIndex(assignmentTexts, 3)
+
+	keywords := x.Keywords(0, 3)
+	require.Len(t, keywords, 3)
+
+	words := []string{keywords[0].Word, keywords[1].Word}
+	assert.ElementsMatch(t, []string{"брокер", "сообщений"}, words)
+	for _, k := range keywords {
+		assert.NotEqual(t, "микросервисы", k.Word, "слово есть во всех работах и не должно быть среди отличительных")
+	}
+	assert.InDelta(t, 1.0, x.IDF("микросервисы"), 1e-9)
+}
+
+func TestTermIndex_Vocabulary(t *testing.T) {
+	vocab := NewTermIndex(assignmentTexts, 3).Vocabulary(1)
+
+	require.Len(t, vocab, 1)
+	assert.Equal(t, "микросервисы", vocab[0].Word)
+	assert.Equal(t, 3, vocab[0].Count)
+	assert.Equal(t, 3, vocab[0].DocumentFrequency)
+}
+
+func TestRarePhrases_GroupsColludingWorks(t *testing.T) {
+	shared := " Квантовая запутанность нелокальных корреляций опровергает скрытые параметры Белла."
+	texts := []string{
+		"Первая работа." + shared,
+		"Совсем другая работа о литературе.",
+		"Третий текст." + shared,
+		"Четвертое эссе." + shared,
+		"Пятый реферат." + shared,
+	}
+
+	opts := DefaultRarePhraseOptions()
+
+	assert.Empty(t, RarePhrases(texts, opts), "фраза в четырех работах при MaxGroup=3 не редкая")
+
+	groups := RarePhrases(texts[:3], opts)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []int{0, 2}, groups[0].Documents)
+	assert.Contains(t, groups[0].Phrases, "квантовая запутанность нелокальных корреляций")
+}
+
+func TestRarePhrases_SkipsFunctionWordPhrases(t *testing.T) {
+	texts := []string{"и в то же время", "и в то же время"}
+
+	assert.Empty(t, RarePhrases(texts, DefaultRarePhraseOptions()))
+}
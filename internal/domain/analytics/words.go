@@ -0,0 +1,87 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MinWordLen — слова короче не учитываются в частотах и TF-IDF (предлоги, союзы, сокращения).
+const MinWordLen = 3
+
+type WordCount struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// Words разбивает текст на слова в нижнем регистре: непрерывные последовательности букв,
+// цифр и внутренних дефисов/апострофов.
+func Words(text string) []string {
+	var words []string
+	var current []rune
+
+	flush := func() {
+		w := strings.Trim(string(current), "-'’")
+		if w != "" {
+			words = append(words, w)
+		}
+		current = current[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			current = append(current, r)
+		case (r == '-' || r == '\'' || r == '’') && len(current) > 0:
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return words
+}
+
+// IsStopword сообщает, входит ли слово в список служебных слов RU/EN.
+func IsStopword(word string) bool {
+	_, ok := stopwords[word]
+	return ok
+}
+
+// Frequencies считает частоты значимых слов: без стоп-слов, чисел и слов короче minLen рун.
+// Результат отсортирован по убыванию частоты, при равенстве — по алфавиту; limit <= 0 — без ограничения.
+func Frequencies(text string, minLen, limit int) []WordCount {
+	counts := make(map[string]int)
+	for _, w := range Words(text) {
+		if len([]rune(w)) < minLen || IsStopword(w) || isNumber(w) {
+			continue
+		}
+		counts[w]++
+	}
+
+	result := make([]WordCount, 0, len(counts))
+	for w, c := range counts {
+		result = append(result, WordCount{Word: w, Count: c})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Word < result[j].Word
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
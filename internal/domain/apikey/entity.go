@@ -0,0 +1,109 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+const (
+	// TokenPrefix отличает API-ключи от JWT и помогает сканерам секретов находить их в коде.
+	TokenPrefix = "ak_"
+	// displayPrefixLen — сколько первых символов ключа хранится открыто, чтобы его можно было узнать в списке.
+	displayPrefixLen = 10
+	secretBytes      = 32
+)
+
+var (
+	ErrInvalidKey = errors.New("invalid api key")
+	ErrRevoked    = errors.New("api key revoked")
+	ErrExpired    = errors.New("api key expired")
+)
+
+// APIKey — ключ для неинтерактивного доступа (плагин LMS, скрипты оценивания).
+// Сам ключ не хранится, только его хеш; показать его можно один раз — при создании.
+type APIKey struct {
+	ID     uuid.UUID
+	Name   string
+	Prefix string
+	Hash   string
+	Scopes []auth.Scope
+	// AssignmentID ограничивает ключ одним заданием; nil — все задания.
+	AssignmentID *uuid.UUID
+	CreatedBy    uuid.UUID
+	CreatedAt    time.Time
+	ExpiresAt    *time.Time
+	LastUsedAt   *time.Time
+	RevokedAt    *time.Time
+}
+
+// New создает ключ и возвращает его вместе с открытым значением.
+func New(name string, scopes []auth.Scope, assignmentID *uuid.UUID, expiresAt *time.Time, createdBy uuid.UUID, now time.Time) (*APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", shared.ErrInvalidInput)
+	}
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", shared.ErrInvalidInput)
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", fmt.Errorf("%w: expires_at must be in the future", shared.ErrInvalidInput)
+	}
+
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	token := TokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &APIKey{
+		ID:           uuid.New(),
+		Name:         name,
+		Prefix:       token[:displayPrefixLen],
+		Hash:         HashToken(token),
+		Scopes:       scopes,
+		AssignmentID: assignmentID,
+		CreatedBy:    createdBy,
+		CreatedAt:    now,
+		ExpiresAt:    expiresAt,
+	}, token, nil
+}
+
+// HashToken: ключ содержит 256 случайных бит, подбирать его по хешу бессмысленно,
+// поэтому медленный хеш не нужен, а SHA-256 позволяет искать ключ по индексу.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Check проверяет, что ключ не отозван и не истек.
+func (k *APIKey) Check(now time.Time) error {
+	if k.RevokedAt != nil {
+		return ErrRevoked
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return ErrExpired
+	}
+	return nil
+}
+
+// Principal: ключ, привязанный к заданию, действует как преподаватель этого задания,
+// ключ без привязки — как администратор. В обоих случаях права сужены областями ключа.
+func (k *APIKey) Principal() *auth.Principal {
+	p := &auth.Principal{UserID: k.ID, Role: auth.RoleAdmin, Scopes: k.Scopes}
+	if k.AssignmentID != nil {
+		p.Role = auth.RoleTeacher
+		p.Assignments = []uuid.UUID{*k.AssignmentID}
+	}
+	return p
+}
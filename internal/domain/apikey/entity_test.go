@@ -0,0 +1,78 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+func TestNew_StoresOnlyHash(t *testing.T) {
+	now := time.Now()
+	key, token, err := New(" LMS ", []auth.Scope{auth.ScopeWorksSubmit}, nil, nil, uuid.New(), now)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(token, TokenPrefix))
+	assert.Equal(t, "LMS", key.Name)
+	assert.Equal(t, HashToken(token), key.Hash)
+	assert.NotContains(t, key.Hash, token)
+	assert.True(t, strings.HasPrefix(token, key.Prefix))
+	assert.Less(t, len(key.Prefix), len(token))
+
+	_, other, err := New("LMS", []auth.Scope{auth.ScopeWorksSubmit}, nil, nil, uuid.New(), now)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestNew_Validation(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+
+	_, _, err := New("", []auth.Scope{auth.ScopeReportsRead}, nil, nil, uuid.New(), now)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+	_, _, err = New("ci", nil, nil, nil, uuid.New(), now)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+	_, _, err = New("ci", []auth.Scope{auth.ScopeReportsRead}, nil, &past, uuid.New(), now)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	expires := now.Add(time.Hour)
+	key, _, err := New("ci", []auth.Scope{auth.ScopeReportsRead}, nil, &expires, uuid.New(), now)
+	require.NoError(t, err)
+
+	assert.NoError(t, key.Check(now))
+	assert.ErrorIs(t, key.Check(expires), ErrExpired)
+
+	key.RevokedAt = &now
+	assert.ErrorIs(t, key.Check(now), ErrRevoked)
+}
+
+func TestPrincipal_ScopedToAssignment(t *testing.T) {
+	assignment := uuid.New()
+	key, _, err := New("lms", []auth.Scope{auth.ScopeWorksSubmit}, &assignment, nil, uuid.New(), time.Now())
+	require.NoError(t, err)
+
+	p := key.Principal()
+	assert.Equal(t, auth.RoleTeacher, p.Role)
+	assert.True(t, p.TeachesAssignment(assignment))
+	assert.False(t, p.TeachesAssignment(uuid.New()))
+	assert.True(t, p.CanSubmitAs(uuid.New(), assignment))
+	assert.True(t, p.HasScope(auth.ScopeWorksSubmit))
+	assert.False(t, p.HasScope(auth.ScopeReportsRead))
+	assert.False(t, p.HasScope(auth.ScopeAdmin))
+
+	unrestricted, _, err := New("grading", []auth.Scope{auth.ScopeReportsRead}, nil, nil, uuid.New(), time.Now())
+	require.NoError(t, err)
+	assert.True(t, unrestricted.Principal().TeachesAssignment(uuid.New()))
+
+	user := &auth.Principal{Role: auth.RoleAdmin}
+	assert.True(t, user.HasScope(auth.ScopeAdmin), "у пользователя с JWT ограничений по областям нет")
+}
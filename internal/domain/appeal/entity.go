@@ -0,0 +1,189 @@
+package appeal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+// Status — этап рассмотрения апелляции: submitted → under_review → upheld | rejected.
+type Status string
+
+const (
+	StatusSubmitted   Status = "submitted"
+	StatusUnderReview Status = "under_review"
+	// StatusUpheld — апелляция удовлетворена, вывод о плагиате снят.
+	StatusUpheld Status = "upheld"
+	// StatusRejected — апелляция отклонена, вывод о плагиате остается.
+	StatusRejected Status = "rejected"
+)
+
+// Decision — итог рассмотрения, выносимый преподавателем.
+type Decision string
+
+const (
+	DecisionUphold Decision = "upheld"
+	DecisionReject Decision = "rejected"
+)
+
+const (
+	MaxStatementLength = 10000
+	MaxEvidenceFiles   = 5
+)
+
+var (
+	// ErrNotAppealable: обжаловать можно только подтвержденный преподавателем вывод о плагиате.
+	ErrNotAppealable = errors.New("report has no confirmed plagiarism finding")
+	ErrFilingClosed  = errors.New("appeal filing deadline has passed")
+	ErrAlreadyOpen   = errors.New("report already has an open appeal")
+	ErrInvalidState  = errors.New("appeal is not in a state that allows this action")
+)
+
+func ParseDecision(s string) (Decision, error) {
+	switch d := Decision(s); d {
+	case DecisionUphold, DecisionReject:
+		return d, nil
+	}
+	return "", fmt.Errorf("%w: unknown decision %q", shared.ErrInvalidInput, s)
+}
+
+func ParseStatus(s string) (Status, error) {
+	switch st := Status(s); st {
+	case StatusSubmitted, StatusUnderReview, StatusUpheld, StatusRejected:
+		return st, nil
+	}
+	return "", fmt.Errorf("%w: unknown appeal status %q", shared.ErrInvalidInput, s)
+}
+
+// Policy — сроки подачи и рассмотрения апелляций.
+type Policy struct {
+	// FilingWindow отсчитывается от вердикта преподавателя.
+	FilingWindow time.Duration
+	// ReviewWindow отсчитывается от подачи апелляции.
+	ReviewWindow time.Duration
+}
+
+func DefaultPolicy() Policy {
+	return Policy{FilingWindow: 14 * 24 * time.Hour, ReviewWindow: 14 * 24 * time.Hour}
+}
+
+// Evidence — файл, приложенный студентом. Содержимое хранится в file.Storage.
+type Evidence struct {
+	ID          uuid.UUID
+	AppealID    uuid.UUID
+	FileName    string
+	StoragePath string
+	MimeType    string
+	Size        int64
+	CreatedAt   time.Time
+}
+
+// Appeal — обжалование студентом подтвержденного вывода о плагиате по отчету.
+type Appeal struct {
+	ID             uuid.UUID
+	ReportID       uuid.UUID
+	WorkID         uuid.UUID
+	AssignmentID   uuid.UUID
+	StudentID      uuid.UUID
+	Statement      string
+	Status         Status
+	ReviewerID     *uuid.UUID
+	Resolution     string
+	ResolvedBy     *uuid.UUID
+	SubmittedAt    time.Time
+	ReviewDeadline time.Time
+	ResolvedAt     *time.Time
+	// OverdueNotifiedAt — когда участникам сообщили о пропуске срока рассмотрения.
+	OverdueNotifiedAt *time.Time
+	UpdatedAt         time.Time
+	Evidence          []Evidence
+}
+
+// FilingDeadline — крайний срок подачи апелляции на вердикт, вынесенный в decidedAt.
+func (p Policy) FilingDeadline(decidedAt time.Time) time.Time {
+	return decidedAt.Add(p.FilingWindow)
+}
+
+// New создает апелляцию. decidedAt — время вердикта, подтвердившего плагиат.
+func New(reportID, workID, assignmentID, studentID uuid.UUID, statement string, decidedAt time.Time, p Policy, now time.Time) (*Appeal, error) {
+	statement = strings.TrimSpace(statement)
+	if statement == "" {
+		return nil, fmt.Errorf("%w: statement is empty", shared.ErrInvalidInput)
+	}
+	if len([]rune(statement)) > MaxStatementLength {
+		return nil, fmt.Errorf("%w: statement exceeds %d characters", shared.ErrInvalidInput, MaxStatementLength)
+	}
+	if now.After(p.FilingDeadline(decidedAt)) {
+		return nil, ErrFilingClosed
+	}
+
+	return &Appeal{
+		ID:             uuid.New(),
+		ReportID:       reportID,
+		WorkID:         workID,
+		AssignmentID:   assignmentID,
+		StudentID:      studentID,
+		Statement:      statement,
+		Status:         StatusSubmitted,
+		SubmittedAt:    now,
+		ReviewDeadline: now.Add(p.ReviewWindow),
+		UpdatedAt:      now,
+	}, nil
+}
+
+// AddEvidence прикрепляет уже сохраненный файл; после начала рассмотрения файлы не принимаются.
+func (a *Appeal) AddEvidence(e Evidence) error {
+	if a.Status != StatusSubmitted {
+		return ErrInvalidState
+	}
+	if len(a.Evidence) >= MaxEvidenceFiles {
+		return fmt.Errorf("%w: at most %d evidence files", shared.ErrInvalidInput, MaxEvidenceFiles)
+	}
+	e.AppealID = a.ID
+	a.Evidence = append(a.Evidence, e)
+	return nil
+}
+
+func (a *Appeal) Open() bool {
+	return a.Status == StatusSubmitted || a.Status == StatusUnderReview
+}
+
+// Overdue: апелляция не рассмотрена к сроку.
+func (a *Appeal) Overdue(now time.Time) bool {
+	return a.Open() && now.After(a.ReviewDeadline)
+}
+
+// StartReview берет апелляцию в работу.
+func (a *Appeal) StartReview(reviewerID uuid.UUID, now time.Time) error {
+	if a.Status != StatusSubmitted {
+		return ErrInvalidState
+	}
+	a.Status = StatusUnderReview
+	a.ReviewerID = &reviewerID
+	a.UpdatedAt = now
+	return nil
+}
+
+// Resolve выносит решение. Решать можно только апелляцию на рассмотрении.
+func (a *Appeal) Resolve(actorID uuid.UUID, d Decision, resolution string, now time.Time) error {
+	if _, err := ParseDecision(string(d)); err != nil {
+		return err
+	}
+	if a.Status != StatusUnderReview {
+		return ErrInvalidState
+	}
+	resolution = strings.TrimSpace(resolution)
+	if resolution == "" {
+		return fmt.Errorf("%w: resolution is empty", shared.ErrInvalidInput)
+	}
+
+	resolved := now
+	a.Status = Status(d)
+	a.Resolution, a.ResolvedBy, a.ResolvedAt, a.UpdatedAt = resolution, &actorID, &resolved, now
+	return nil
+}
@@ -0,0 +1,69 @@
+package appeal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+func newAppeal(t *testing.T, now time.Time) *Appeal {
+	a, err := New(uuid.New(), uuid.New(), uuid.New(), uuid.New(), "Текст написан мной, черновики прилагаю", now.Add(-time.Hour), DefaultPolicy(), now)
+	require.NoError(t, err)
+	return a
+}
+
+func TestAppeal_Lifecycle(t *testing.T) {
+	now := time.Now()
+	teacher := uuid.New()
+	a := newAppeal(t, now)
+	assert.Equal(t, StatusSubmitted, a.Status)
+	assert.Equal(t, now.Add(DefaultPolicy().ReviewWindow), a.ReviewDeadline)
+
+	require.NoError(t, a.AddEvidence(Evidence{ID: uuid.New(), FileName: "draft.txt"}))
+	assert.Equal(t, a.ID, a.Evidence[0].AppealID)
+
+	assert.ErrorIs(t, a.Resolve(teacher, DecisionUphold, "ok", now), ErrInvalidState, "решение только после начала рассмотрения")
+
+	require.NoError(t, a.StartReview(teacher, now))
+	assert.Equal(t, StatusUnderReview, a.Status)
+	assert.ErrorIs(t, a.StartReview(teacher, now), ErrInvalidState)
+	assert.ErrorIs(t, a.AddEvidence(Evidence{ID: uuid.New()}), ErrInvalidState)
+
+	assert.ErrorIs(t, a.Resolve(teacher, DecisionReject, " ", now), shared.ErrInvalidInput)
+	require.NoError(t, a.Resolve(teacher, DecisionUphold, "Черновики подтверждают авторство", now))
+	assert.Equal(t, StatusUpheld, a.Status)
+	assert.False(t, a.Open())
+	assert.ErrorIs(t, a.Resolve(teacher, DecisionReject, "передумал", now), ErrInvalidState)
+}
+
+func TestAppeal_Deadlines(t *testing.T) {
+	now := time.Now()
+	p := DefaultPolicy()
+
+	_, err := New(uuid.New(), uuid.New(), uuid.New(), uuid.New(), "поздно", now.Add(-p.FilingWindow-time.Minute), p, now)
+	assert.ErrorIs(t, err, ErrFilingClosed)
+
+	_, err = New(uuid.New(), uuid.New(), uuid.New(), uuid.New(), "   ", now, p, now)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+
+	a := newAppeal(t, now)
+	assert.False(t, a.Overdue(now))
+	assert.True(t, a.Overdue(a.ReviewDeadline.Add(time.Second)))
+
+	require.NoError(t, a.StartReview(uuid.New(), now))
+	require.NoError(t, a.Resolve(uuid.New(), DecisionReject, "совпадение дословное", now))
+	assert.False(t, a.Overdue(a.ReviewDeadline.Add(time.Second)), "решенная апелляция не просрочена")
+}
+
+func TestAppeal_EvidenceLimit(t *testing.T) {
+	a := newAppeal(t, time.Now())
+	for i := 0; i < MaxEvidenceFiles; i++ {
+		require.NoError(t, a.AddEvidence(Evidence{ID: uuid.New()}))
+	}
+	assert.ErrorIs(t, a.AddEvidence(Evidence{ID: uuid.New()}), shared.ErrInvalidInput)
+}
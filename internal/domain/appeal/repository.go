@@ -0,0 +1,62 @@
+package appeal
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Create сохраняет новую апелляцию вместе с приложенными файлами.
+	Create(ctx context.Context, a *Appeal) error
+	// Update сохраняет статус и решение.
+	Update(ctx context.Context, a *Appeal) error
+	// GetByID возвращает апелляцию с файлами или shared.ErrNotFound.
+	GetByID(ctx context.Context, id uuid.UUID) (*Appeal, error)
+	// ListByWork возвращает апелляции по работе без файлов, новые первыми.
+	ListByWork(ctx context.Context, workID uuid.UUID) ([]*Appeal, error)
+	// HasOpen сообщает, есть ли по отчету нерассмотренная апелляция.
+	HasOpen(ctx context.Context, reportID uuid.UUID) (bool, error)
+	// ListOverdue возвращает открытые апелляции с истекшим сроком рассмотрения,
+	// о которых еще не уведомляли.
+	ListOverdue(ctx context.Context, now time.Time) ([]*Appeal, error)
+}
+
+// NotificationKind — событие апелляции, о котором сообщают участникам.
+type NotificationKind string
+
+const (
+	NotifySubmitted   NotificationKind = "appeal.submitted"
+	NotifyUnderReview NotificationKind = "appeal.under_review"
+	NotifyResolved    NotificationKind = "appeal.resolved"
+	NotifyOverdue     NotificationKind = "appeal.overdue"
+)
+
+// Notification адресована студенту и преподавателям задания. Отдельного справочника
+// преподавателей нет, поэтому получатели задаются заданием, а не списком пользователей.
+type Notification struct {
+	Kind         NotificationKind
+	AppealID     uuid.UUID
+	WorkID       uuid.UUID
+	AssignmentID uuid.UUID
+	StudentID    uuid.UUID
+	Status       Status
+	Deadline     time.Time
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+func NewNotification(kind NotificationKind, a *Appeal) Notification {
+	return Notification{
+		Kind:         kind,
+		AppealID:     a.ID,
+		WorkID:       a.WorkID,
+		AssignmentID: a.AssignmentID,
+		StudentID:    a.StudentID,
+		Status:       a.Status,
+		Deadline:     a.ReviewDeadline,
+	}
+}
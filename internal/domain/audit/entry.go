@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Action string
+
+const (
+	ActionReportView      Action = "report.view"
+	ActionReportExport    Action = "report.export"
+	ActionThresholdChange Action = "config.threshold_change"
+	ActionOverride        Action = "report.override"
+	ActionDelete          Action = "resource.delete"
+	ActionAPIKeyCreate    Action = "api_key.create"
+	ActionAPIKeyRevoke    Action = "api_key.revoke"
+	ActionAppealDecide    Action = "appeal.decide"
+	ActionWebhookCreate   Action = "webhook.create"
+	ActionWebhookDelete   Action = "webhook.delete"
+	ActionReanalyze       Action = "report.reanalyze"
+)
+
+const (
+	ActorUser   = "user"
+	ActorAPIKey = "api_key"
+	// ActorSystem — запись без пользователя: запуск сервиса или команда CLI.
+	ActorSystem = "system"
+)
+
+// Event — что произошло с ресурсом; актора, время и место в цепочке добавляет запись журнала.
+type Event struct {
+	Action       Action
+	ResourceType string
+	ResourceID   string
+	Details      map[string]string
+}
+
+// GenesisHash — PrevHash первой записи журнала.
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// Entry — запись журнала аудита. Каждая запись хранит хеш предыдущей, поэтому
+// изменение, удаление или перестановка записей задним числом обнаруживается VerifyChain.
+type Entry struct {
+	Seq          int64
+	ActorID      uuid.UUID
+	ActorType    string
+	ActorRole    string
+	Action       Action
+	ResourceType string
+	ResourceID   string
+	RequestID    string
+	Details      map[string]string
+	CreatedAt    time.Time
+	PrevHash     string
+	Hash         string
+}
+
+// Link делает e следующей записью после prev (nil — первая запись) и вычисляет ее хеш.
+// Время округляется до микросекунд: с такой точностью его хранит PostgreSQL.
+func Link(e, prev *Entry) {
+	e.Seq, e.PrevHash = 1, GenesisHash
+	if prev != nil {
+		e.Seq, e.PrevHash = prev.Seq+1, prev.Hash
+	}
+	e.CreatedAt = e.CreatedAt.UTC().Truncate(time.Microsecond)
+	e.Hash = e.ComputeHash()
+}
+
+// ComputeHash — SHA-256 от всех полей записи, кроме самого хеша.
+func (e *Entry) ComputeHash() string {
+	details := []byte("{}")
+	if len(e.Details) > 0 {
+		// json.Marshal сортирует ключи map, поэтому представление однозначно.
+		details, _ = json.Marshal(e.Details)
+	}
+
+	h := sha256.New()
+	for _, field := range []string{
+		strconv.FormatInt(e.Seq, 10),
+		e.ActorID.String(),
+		e.ActorType,
+		e.ActorRole,
+		string(e.Action),
+		e.ResourceType,
+		e.ResourceID,
+		e.RequestID,
+		string(details),
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		e.PrevHash,
+	} {
+		// Длина перед каждым полем исключает неоднозначность при склейке.
+		fmt.Fprintf(h, "%d:%s;", len(field), field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ChainError указывает первую запись, на которой цепочка нарушена.
+type ChainError struct {
+	Seq    int64
+	Reason string
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("audit chain broken at entry %d: %s", e.Seq, e.Reason)
+}
+
+// VerifyChain проверяет подряд идущие записи, начиная сразу после prev (nil — с начала журнала).
+func VerifyChain(prev *Entry, entries []*Entry) error {
+	wantSeq, wantPrev := int64(1), GenesisHash
+	if prev != nil {
+		wantSeq, wantPrev = prev.Seq+1, prev.Hash
+	}
+
+	for _, e := range entries {
+		if e.Seq != wantSeq {
+			return &ChainError{Seq: wantSeq, Reason: fmt.Sprintf("entry missing, next present is %d", e.Seq)}
+		}
+		if e.PrevHash != wantPrev {
+			return &ChainError{Seq: e.Seq, Reason: "previous hash mismatch"}
+		}
+		if e.ComputeHash() != e.Hash {
+			return &ChainError{Seq: e.Seq, Reason: "entry content does not match its hash"}
+		}
+		wantSeq, wantPrev = e.Seq+1, e.Hash
+	}
+	return nil
+}
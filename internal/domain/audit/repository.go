@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Filter struct {
+	ActorID      *uuid.UUID
+	Action       Action
+	ResourceType string
+	ResourceID   string
+	From         *time.Time
+	To           *time.Time
+	Limit        int
+	Offset       int
+}
+
+// Repository — журнал только на добавление: методов изменения и удаления нет.
+type Repository interface {
+	// Append связывает запись с последней записью журнала (см. Link) и сохраняет ее.
+	// Добавления сериализуются, чтобы у двух записей не оказалось одного предшественника.
+	Append(ctx context.Context, e *Entry) error
+	// Query возвращает записи по фильтру, новые первыми.
+	Query(ctx context.Context, f Filter) ([]*Entry, error)
+	// After возвращает до limit записей с Seq > seq по возрастанию Seq.
+	After(ctx context.Context, seq int64, limit int) ([]*Entry, error)
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type Role string
+
+const (
+	RoleStudent Role = "student"
+	RoleTeacher Role = "teacher"
+	RoleAdmin   Role = "admin"
+)
+
+var (
+	ErrUnknownRole  = errors.New("unknown role")
+	ErrUnknownScope = errors.New("unknown scope")
+)
+
+func ParseRole(s string) (Role, error) {
+	switch r := Role(s); r {
+	case RoleStudent, RoleTeacher, RoleAdmin:
+		return r, nil
+	}
+	return "", ErrUnknownRole
+}
+
+// Scope — группа операций, доступных по API-ключу.
+type Scope string
+
+const (
+	ScopeWorksSubmit   Scope = "works:submit"
+	ScopeReportsRead   Scope = "reports:read"
+	ScopeAnalyticsRead Scope = "analytics:read"
+	ScopeReviewsWrite  Scope = "reviews:write"
+	// ScopeAdmin не выдается ключам: управлять ключами могут только пользователи с JWT.
+	ScopeAdmin Scope = "admin"
+)
+
+// ParseScope принимает только области, которые можно выдать API-ключу.
+func ParseScope(s string) (Scope, error) {
+	switch sc := Scope(s); sc {
+	case ScopeWorksSubmit, ScopeReportsRead, ScopeAnalyticsRead, ScopeReviewsWrite:
+		return sc, nil
+	}
+	return "", ErrUnknownScope
+}
+
+// AccessLevel — что пользователь может видеть о конкретной работе.
+type AccessLevel int
+
+const (
+	AccessNone AccessLevel = iota
+	// AccessSummary — итог проверки без совпавших работ и фрагментов (автор работы).
+	AccessSummary
+	// AccessFull — полный отчет (преподаватель задания, администратор).
+	AccessFull
+)
+
+// Principal — аутентифицированный пользователь. Отдельной сущности «курс» в системе нет,
+// поэтому курсы преподавателя задаются списком заданий, которые он ведет.
+type Principal struct {
+	UserID      uuid.UUID
+	Role        Role
+	Assignments []uuid.UUID
+	// Scopes задан только для API-ключей; у пользователя с JWT ограничений по областям нет.
+	Scopes []Scope
+}
+
+func (p *Principal) HasScope(scope Scope) bool {
+	if p.Scopes == nil {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// TeachesAssignment: администратор видит все задания, преподаватель — только свои.
+func (p *Principal) TeachesAssignment(assignmentID uuid.UUID) bool {
+	switch p.Role {
+	case RoleAdmin:
+		return true
+	case RoleTeacher:
+		for _, a := range p.Assignments {
+			if a == assignmentID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanSubmitAs: студент сдает работы только от своего имени, преподаватель — за студентов своих заданий.
+func (p *Principal) CanSubmitAs(studentID, assignmentID uuid.UUID) bool {
+	if p.Role == RoleStudent {
+		return p.UserID == studentID
+	}
+	return p.TeachesAssignment(assignmentID)
+}
+
+func (p *Principal) WorkAccess(studentID, assignmentID uuid.UUID) AccessLevel {
+	if p.TeachesAssignment(assignmentID) {
+		return AccessFull
+	}
+	if p.Role == RoleStudent && p.UserID == studentID {
+		return AccessSummary
+	}
+	return AccessNone
+}
@@ -0,0 +1,130 @@
+package batch
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status — этап пакетной загрузки: importing → queued → processing → completed | failed.
+type Status string
+
+const (
+	// StatusImporting — пакет сохранен, файлы архива превращаются в работы.
+	StatusImporting Status = "importing"
+	// StatusQueued — работы созданы, общий проход проверки ждет обработчика.
+	StatusQueued     Status = "queued"
+	StatusProcessing Status = "processing"
+	StatusCompleted  Status = "completed"
+	StatusFailed     Status = "failed"
+)
+
+// MaxItems — сколько файлов принимается в одном пакете.
+const MaxItems = 2000
+
+var ErrInvalidState = errors.New("batch is not in a state that allows this action")
+
+// Item — файл из манифеста. WorkID пуст, пока файл не принят; если принять не удалось,
+// причина в Error.
+type Item struct {
+	FileName  string
+	StudentID uuid.UUID
+	WorkID    *uuid.UUID
+	Error     string
+}
+
+// Batch — пакет работ одного задания, загруженный архивом с манифестом. Пакет сохраняется
+// до импорта, работы создаются сразу при загрузке и привязываются к нему, а проверка
+// выполняется одним проходом по всему заданию.
+type Batch struct {
+	ID           uuid.UUID
+	AssignmentID uuid.UUID
+	CreatedBy    uuid.UUID
+	Status       Status
+	Items        []Item
+	// Checked — по скольким работам пакета уже сохранен отчет.
+	Checked    int
+	Error      string
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+func New(assignmentID, createdBy uuid.UUID, items []Item, now time.Time) *Batch {
+	return &Batch{
+		ID:           uuid.New(),
+		AssignmentID: assignmentID,
+		CreatedBy:    createdBy,
+		Status:       StatusImporting,
+		Items:        items,
+		CreatedAt:    now,
+	}
+}
+
+// WorkIDs — работы, созданные пакетом, в порядке манифеста.
+func (b *Batch) WorkIDs() []uuid.UUID {
+	var ids []uuid.UUID
+	for _, it := range b.Items {
+		if it.WorkID != nil {
+			ids = append(ids, *it.WorkID)
+		}
+	}
+	return ids
+}
+
+// Rejected — сколько файлов манифеста не удалось принять.
+func (b *Batch) Rejected() int {
+	n := 0
+	for _, it := range b.Items {
+		if it.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Queue ставит импортированный пакет в очередь проверки.
+func (b *Batch) Queue() error {
+	if b.Status != StatusImporting {
+		return ErrInvalidState
+	}
+	b.Status = StatusQueued
+	return nil
+}
+
+// Start отмечает начало прохода. Пакет в processing можно начать заново: обработчик,
+// взявший его раньше, не уложился в аренду.
+func (b *Batch) Start(now time.Time) error {
+	if b.Status != StatusQueued && b.Status != StatusProcessing {
+		return ErrInvalidState
+	}
+	b.Status = StatusProcessing
+	b.Checked = 0
+	b.StartedAt = &now
+	return nil
+}
+
+func (b *Batch) Complete(now time.Time) error {
+	if b.Status != StatusProcessing {
+		return ErrInvalidState
+	}
+	b.Status = StatusCompleted
+	b.FinishedAt = &now
+	return nil
+}
+
+// Fail отмечает, что импорт или проход проверки прерван ошибкой.
+func (b *Batch) Fail(reason string, now time.Time) error {
+	if b.Status != StatusImporting && b.Status != StatusProcessing {
+		return ErrInvalidState
+	}
+	b.Status = StatusFailed
+	b.Error = reason
+	b.FinishedAt = &now
+	return nil
+}
+
+func (b *Batch) Done() bool {
+	return b.Status == StatusCompleted || b.Status == StatusFailed
+}
@@ -0,0 +1,107 @@
+package batch
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+func TestParseManifest_CSV(t *testing.T) {
+	s1, s2 := uuid.New(), uuid.New()
+	manifest := "\ufeffStudent Name,student_id,File\n" +
+		"Иванов," + s1.String() + ",essays/ivanov.txt\n" +
+		"Петров, " + s2.String() + ",./essays\\petrov.md\n"
+
+	entries, err := ParseManifest(strings.NewReader(manifest), ManifestCSV)
+	require.NoError(t, err)
+	assert.Equal(t, []ManifestEntry{
+		{File: "essays/ivanov.txt", StudentID: s1},
+		{File: "essays/petrov.md", StudentID: s2},
+	}, entries)
+}
+
+func TestParseManifest_JSON(t *testing.T) {
+	s1 := uuid.New()
+	entries, err := ParseManifest(strings.NewReader(`[{"file":"/a.txt","student_id":"`+s1.String()+`"}]`), ManifestJSON)
+	require.NoError(t, err)
+	assert.Equal(t, []ManifestEntry{{File: "a.txt", StudentID: s1}}, entries)
+}
+
+func TestParseManifest_Invalid(t *testing.T) {
+	id := uuid.New().String()
+	cases := map[string]struct {
+		format   ManifestFormat
+		manifest string
+		contains string
+	}{
+		"пустой":             {ManifestCSV, "file,student_id\n", "empty"},
+		"нет колонки":        {ManifestCSV, "file,name\na.txt,x\n", "student_id columns"},
+		"неверный студент":   {ManifestCSV, "file,student_id\na.txt,42\n", "line 2"},
+		"повтор файла":       {ManifestCSV, "file,student_id\na.txt," + id + "\n./a.txt," + id + "\n", "listed twice"},
+		"пустое имя":         {ManifestJSON, `[{"file":" ","student_id":"` + id + `"}]`, "item 0"},
+		"не массив":          {ManifestJSON, `{"file":"a.txt"}`, "manifest"},
+		"неизвестный формат": {"xml", "<x/>", "unknown manifest format"},
+		"короткая строка":    {ManifestCSV, "file,student_id\na.txt\n", "missing columns"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseManifest(strings.NewReader(tc.manifest), tc.format)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, shared.ErrInvalidInput)
+			assert.Contains(t, err.Error(), tc.contains)
+		})
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	f, err := FormatOf("export/Manifest.CSV")
+	require.NoError(t, err)
+	assert.Equal(t, ManifestCSV, f)
+
+	_, err = FormatOf("manifest.xlsx")
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+}
+
+func TestBatch_Lifecycle(t *testing.T) {
+	now := time.Now()
+	workID := uuid.New()
+	b := New(uuid.New(), uuid.New(), []Item{
+		{FileName: "a.txt", StudentID: uuid.New(), WorkID: &workID},
+		{FileName: "b.pdf", StudentID: uuid.New(), Error: "unsupported format"},
+	}, now)
+	assert.Equal(t, StatusImporting, b.Status)
+	assert.Equal(t, []uuid.UUID{workID}, b.WorkIDs())
+	assert.Equal(t, 1, b.Rejected())
+
+	assert.ErrorIs(t, b.Start(now), ErrInvalidState, "проверка начинается после импорта")
+	require.NoError(t, b.Queue())
+	assert.Equal(t, StatusQueued, b.Status)
+	assert.ErrorIs(t, b.Queue(), ErrInvalidState)
+
+	assert.ErrorIs(t, b.Complete(now), ErrInvalidState, "завершить можно только начатый проход")
+	require.NoError(t, b.Start(now))
+	b.Checked = 1
+	require.NoError(t, b.Start(now), "брошенный проход начинается заново")
+	assert.Zero(t, b.Checked)
+
+	require.NoError(t, b.Complete(now))
+	assert.True(t, b.Done())
+	assert.ErrorIs(t, b.Start(now), ErrInvalidState)
+	assert.ErrorIs(t, b.Fail("late", now), ErrInvalidState)
+}
+
+func TestBatch_FailImport(t *testing.T) {
+	now := time.Now()
+	b := New(uuid.New(), uuid.New(), []Item{{FileName: "a.txt", StudentID: uuid.New()}}, now)
+	assert.Zero(t, b.Rejected(), "еще не обработанный файл не отклонен")
+
+	require.NoError(t, b.Fail("storage unavailable", now))
+	assert.True(t, b.Done())
+	assert.ErrorIs(t, b.Queue(), ErrInvalidState)
+}
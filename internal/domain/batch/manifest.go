@@ -0,0 +1,160 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+type ManifestFormat string
+
+const (
+	ManifestCSV  ManifestFormat = "csv"
+	ManifestJSON ManifestFormat = "json"
+)
+
+// ManifestEntry связывает файл архива со студентом.
+type ManifestEntry struct {
+	File      string    `json:"file"`
+	StudentID uuid.UUID `json:"student_id"`
+}
+
+// FormatOf определяет формат манифеста по имени файла.
+func FormatOf(filename string) (ManifestFormat, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return ManifestCSV, nil
+	case ".json":
+		return ManifestJSON, nil
+	}
+	return "", fmt.Errorf("%w: manifest must be .csv or .json", shared.ErrInvalidInput)
+}
+
+// ParseManifest читает манифест. CSV — с заголовком, колонки file и student_id в любом
+// порядке (остальные колонки выгрузки LMS игнорируются); JSON — массив объектов
+// {"file": ..., "student_id": ...}. Имя файла повторяться не может.
+func ParseManifest(r io.Reader, format ManifestFormat) ([]ManifestEntry, error) {
+	var entries []ManifestEntry
+	var err error
+	switch format {
+	case ManifestCSV:
+		entries, err = parseCSV(r)
+	case ManifestJSON:
+		entries, err = parseJSON(r)
+	default:
+		return nil, fmt.Errorf("%w: unknown manifest format %q", shared.ErrInvalidInput, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: manifest is empty", shared.ErrInvalidInput)
+	}
+	if len(entries) > MaxItems {
+		return nil, fmt.Errorf("%w: manifest lists %d files, at most %d allowed", shared.ErrInvalidInput, len(entries), MaxItems)
+	}
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if seen[e.File] {
+			return nil, fmt.Errorf("%w: file %q is listed twice", shared.ErrInvalidInput, e.File)
+		}
+		seen[e.File] = true
+	}
+	return entries, nil
+}
+
+func parseCSV(r io.Reader) ([]ManifestEntry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: manifest: %v", shared.ErrInvalidInput, err)
+	}
+	fileCol, studentCol := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+		case "file", "filename":
+			fileCol = i
+		case "student_id":
+			studentCol = i
+		}
+	}
+	if fileCol < 0 || studentCol < 0 {
+		return nil, fmt.Errorf("%w: manifest header must have file and student_id columns", shared.ErrInvalidInput)
+	}
+
+	var entries []ManifestEntry
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: manifest: %v", shared.ErrInvalidInput, err)
+		}
+		if fileCol >= len(record) || studentCol >= len(record) {
+			return nil, fmt.Errorf("%w: manifest line %d: missing columns", shared.ErrInvalidInput, line)
+		}
+		entry, err := newEntry(record[fileCol], record[studentCol])
+		if err != nil {
+			return nil, fmt.Errorf("%w: manifest line %d: %v", shared.ErrInvalidInput, line, err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func parseJSON(r io.Reader) ([]ManifestEntry, error) {
+	var raw []struct {
+		File      string `json:"file"`
+		StudentID string `json:"student_id"`
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%w: manifest: %v", shared.ErrInvalidInput, err)
+	}
+
+	entries := make([]ManifestEntry, 0, len(raw))
+	for i, item := range raw {
+		entry, err := newEntry(item.File, item.StudentID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: manifest item %d: %v", shared.ErrInvalidInput, i, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func newEntry(file, studentID string) (ManifestEntry, error) {
+	file = CleanName(file)
+	if file == "" {
+		return ManifestEntry{}, errors.New("file is empty")
+	}
+	id, err := uuid.Parse(strings.TrimSpace(studentID))
+	if err != nil {
+		return ManifestEntry{}, fmt.Errorf("invalid student_id %q", studentID)
+	}
+	return ManifestEntry{File: file, StudentID: id}, nil
+}
+
+// CleanName приводит путь файла к виду, в котором его хранит zip: прямые слэши, без
+// ведущих "./" и "/".
+func CleanName(name string) string {
+	name = strings.ReplaceAll(strings.TrimSpace(name), "\\", "/")
+	if name == "" {
+		return ""
+	}
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
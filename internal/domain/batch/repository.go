@@ -0,0 +1,22 @@
+package batch
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Create сохраняет пакет вместе с файлами манифеста.
+	Create(ctx context.Context, b *Batch) error
+	// SaveItem сохраняет итог импорта файла с номером position в манифесте.
+	SaveItem(ctx context.Context, batchID uuid.UUID, position int, it Item) error
+	// Update сохраняет статус и ход проверки; состав пакета не меняется.
+	Update(ctx context.Context, b *Batch) error
+	// GetByID возвращает пакет с файлами или shared.ErrNotFound.
+	GetByID(ctx context.Context, id uuid.UUID) (*Batch, error)
+	// Claim берет самый старый пакет в очереди (или брошенный обработчиком: в processing
+	// с истекшей арендой) и продлевает аренду до now+lease. Если брать нечего — shared.ErrNotFound.
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*Batch, error)
+}
@@ -11,6 +11,10 @@ type Repository interface {
 	Save(ctx context.Context, file *File) error
 	GetByID(ctx context.Context, id uuid.UUID) (*File, error)
 	GetByHash(ctx context.Context, hash string) (*File, error) // Для проверки дубликатов
+	List(ctx context.Context) ([]*File, error)
+	UpdateHash(ctx context.Context, id uuid.UUID, hash string) error
+	// StoragePaths — все пути хранилища, на которые есть ссылки в базе.
+	StoragePaths(ctx context.Context) (map[string]struct{}, error)
 }
 
 type Storage interface {
@@ -5,52 +5,149 @@ import (
 )
 
 type ShingleDetector struct {
-	ShingleLen int
+	ShingleLen    int
+	Normalization NormalizationPolicy
+	// MinTokens — сколько слов должно быть в тексте, чтобы его сравнивать; более короткие
+	// тексты ("TODO", пустой шаблон) дают бессмысленные 0 или 1.
+	MinTokens int
+	// MinPassageWords — общие фрагменты короче этого числа слов не учитываются ни в оценке,
+	// ни во фрагментах: это устойчивые обороты, а не заимствования.
+	MinPassageWords int
 }
 
 func NewShingleDetector() *ShingleDetector {
-	return &ShingleDetector{ShingleLen: 3}
+	return &ShingleDetector{
+		ShingleLen: 3,
+		Normalization: NormalizationPolicy{
+			ExcludeQuotes:       true,
+			ExcludeBibliography: true,
+		},
+	}
+}
+
+// NewShingleDetectorFor воспроизводит детектор, которым получена оценка с параметрами p.
+// Нормализация берется текущей: прежние версии алгоритма не хранятся.
+func NewShingleDetectorFor(p Parameters) *ShingleDetector {
+	return &ShingleDetector{
+		ShingleLen: p.ShingleLen,
+		Normalization: NormalizationPolicy{
+			ExcludeQuotes:       p.ExcludeQuotes,
+			ExcludeBibliography: p.ExcludeBibliography,
+		},
+		MinTokens:       p.MinTokens,
+		MinPassageWords: p.MinPassageWords,
+	}
 }
 
 func (d *ShingleDetector) Compare(text1, text2 string) (float64, error) {
+	overlap, err := d.Overlap(text1, text2)
+	return overlap.Similarity, err
+}
+
+// Overlap считает сходство пары по одному набору шинглов на каждый текст. Пара, где
+// хотя бы один текст короче MinTokens, не сравнивается: сходство нулевое.
+func (d *ShingleDetector) Overlap(text1, text2 string) (Overlap, error) {
 	if text1 == "" || text2 == "" {
-		return 0.0, nil
+		return Overlap{}, nil
 	}
 
-	set1 := d.getShingles(text1)
-	set2 := d.getShingles(text2)
+	a := tokenize(text1, d.Exclusions(text1))
+	b := tokenize(text2, d.Exclusions(text2))
+	if !d.Sufficient(len(a)) || !d.Sufficient(len(b)) {
+		return Overlap{}, nil
+	}
+
+	set1 := d.shingles(a)
+	set2 := d.shingles(b)
 
-	intersection := 0
-	for shingle := range set1 {
-		if _, exists := set2[shingle]; exists {
-			intersection++
+	var intersection int
+	if d.filtersPassages() {
+		kept2 := d.passageShingles(b, a)
+		for shingle := range d.passageShingles(a, b) {
+			if _, exists := kept2[shingle]; exists {
+				intersection++
+			}
+		}
+	} else {
+		for shingle := range set1 {
+			if _, exists := set2[shingle]; exists {
+				intersection++
+			}
 		}
 	}
 
 	union := len(set1) + len(set2) - intersection
 	if union == 0 {
-		return 0.0, nil
+		return Overlap{}, nil
+	}
+
+	return Overlap{
+		Similarity:         float64(intersection) / float64(union),
+		Containment:        share(intersection, len(set1)),
+		MatchedContainment: share(intersection, len(set2)),
+	}, nil
+}
+
+func (d *ShingleDetector) Exclusions(text string) []ExcludedRange {
+	return d.Normalization.Exclusions(text)
+}
+
+func (d *ShingleDetector) Parameters() Parameters {
+	return Parameters{
+		Algorithm:           AlgorithmShingle,
+		Version:             ShingleVersion,
+		ShingleLen:          d.ShingleLen,
+		ExcludeQuotes:       d.Normalization.ExcludeQuotes,
+		ExcludeBibliography: d.Normalization.ExcludeBibliography,
+		MinTokens:           d.MinTokens,
+		MinPassageWords:     d.MinPassageWords,
 	}
+}
+
+// Sufficient: в тексте из tokens слов достаточно содержания для сравнения.
+func (d *ShingleDetector) Sufficient(tokens int) bool {
+	return tokens >= d.MinTokens
+}
 
-	return float64(intersection) / float64(union), nil
+// filtersPassages: MinPassageWords отсекает что-то сверх того, что и так короче шингла.
+func (d *ShingleDetector) filtersPassages() bool {
+	return d.MinPassageWords > d.ShingleLen
 }
 
-func (d *ShingleDetector) getShingles(text string) map[string]struct{} {
-	text = strings.ToLower(text)
-	text = strings.ReplaceAll(text, ".", "")
-	text = strings.ReplaceAll(text, ",", "")
-	text = strings.ReplaceAll(text, "\n", " ")
+// passageShingles — шинглы первого текста, лежащие внутри общих со вторым фрагментов
+// не короче MinPassageWords.
+func (d *ShingleDetector) passageShingles(a, b []token) map[string]struct{} {
+	kept := make(map[string]struct{})
+	for _, r := range d.matchRuns(a, b) {
+		if r.words < d.MinPassageWords {
+			continue
+		}
+		for i := r.source; i+d.ShingleLen <= r.source+r.words; i++ {
+			if key, ok := shingleAt(a, i, d.ShingleLen); ok {
+				kept[key] = struct{}{}
+			}
+		}
+	}
+	return kept
+}
 
-	words := strings.Fields(text)
+func (d *ShingleDetector) shingles(tokens []token) map[string]struct{} {
 	shingles := make(map[string]struct{})
 
-	if len(words) < d.ShingleLen {
+	if len(tokens) < d.ShingleLen {
 		return shingles
 	}
 
-	for i := 0; i <= len(words)-d.ShingleLen; i++ {
-		shingle := strings.Join(words[i:i+d.ShingleLen], " ")
-		shingles[shingle] = struct{}{}
+	words := make([]string, d.ShingleLen)
+	for i := 0; i <= len(tokens)-d.ShingleLen; i++ {
+		window := tokens[i : i+d.ShingleLen]
+		if window[0].seg != window[len(window)-1].seg {
+			continue
+		}
+		for j, t := range window {
+			words[j] = t.word
+		}
+		shingles[strings.Join(words, " ")] = struct{}{}
 	}
 	return shingles
 }
@@ -56,3 +56,70 @@ func TestShingleDetector_Compare(t *testing.T) {
 		})
 	}
 }
+
+func TestShingleDetector_Overlap(t *testing.T) {
+	detector := NewShingleDetector()
+	detector.ShingleLen = 2
+
+	overlap, err := detector.Overlap("alpha beta gamma", "alpha beta gamma delta epsilon zeta")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, overlap.Containment, "короткий текст целиком вставлен в длинный")
+	assert.InDelta(t, 0.4, overlap.MatchedContainment, 0.0001)
+	assert.InDelta(t, 0.4, overlap.Similarity, 0.0001)
+}
+
+func TestShingleDetector_MinTokens(t *testing.T) {
+	detector := NewShingleDetector()
+	detector.MinTokens = 5
+
+	score, err := detector.Compare("TODO", "TODO")
+	assert.NoError(t, err)
+	assert.Zero(t, score, "короткие тексты не дают ложной единицы")
+
+	score, err = detector.Compare("one two three four", "one two three four five six")
+	assert.NoError(t, err)
+	assert.Zero(t, score, "достаточно слов должно быть в обоих текстах")
+	assert.Empty(t, detector.Passages("one two three four", "one two three four five six"))
+
+	score, err = detector.Compare("one two three four five", "one two three four five")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, score)
+
+	assert.False(t, detector.Sufficient(4))
+	assert.True(t, detector.Sufficient(5))
+	assert.Equal(t, 5, detector.Parameters().MinTokens)
+}
+
+func TestShingleDetector_MinPassageWords(t *testing.T) {
+	detector := NewShingleDetector()
+	text1 := "it is well known that the mitochondria is the powerhouse of the cell and nothing else"
+	text2 := "as is well known, we measured the mitochondria is the powerhouse of the cell today"
+
+	passages := detector.Passages(text1, text2)
+	assert.Equal(t, []int{3, 8}, passageWords(passages))
+	before, err := detector.Overlap(text1, text2)
+	assert.NoError(t, err)
+
+	detector.MinPassageWords = 5
+	passages = detector.Passages(text1, text2)
+	assert.Equal(t, []int{8}, passageWords(passages), "оборот из трех слов не считается")
+	after, err := detector.Overlap(text1, text2)
+	assert.NoError(t, err)
+	assert.Less(t, after.Similarity, before.Similarity)
+	assert.Less(t, after.Containment, before.Containment)
+	assert.Greater(t, after.Similarity, 0.0)
+
+	detector.MinPassageWords = 20
+	after, err = detector.Overlap(text1, text2)
+	assert.NoError(t, err)
+	assert.Zero(t, after.Similarity, "общих фрагментов такой длины нет")
+	assert.Empty(t, detector.Passages(text1, text2))
+}
+
+func passageWords(passages []Passage) []int {
+	words := make([]int, len(passages))
+	for i, p := range passages {
+		words[i] = p.Words
+	}
+	return words
+}
@@ -0,0 +1,146 @@
+package plagiarism
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Роль работы в паре совпавших работ.
+const (
+	RoleLikelySource = "likely_source"
+	RoleLikelyCopier = "likely_copier"
+	// RoleUndetermined — признаки противоречат друг другу или слишком слабы.
+	RoleUndetermined = "undetermined"
+)
+
+// Веса признаков атрибуции; в сумме 1, поэтому итог лежит в [-1, 1].
+const (
+	weightOrder   = 0.3
+	weightContext = 0.4
+	weightHistory = 0.3
+
+	// attributionMargin — насколько вероятность должна отойти от 0.5, чтобы назвать роль.
+	attributionMargin = 0.1
+)
+
+// VersionedText — работа пары с текстом и более ранними версиями того же автора в задании.
+type VersionedText struct {
+	WorkID      uuid.UUID
+	SubmittedAt time.Time
+	Text        string
+	Versions    []Version
+}
+
+// Version — другая работа того же автора по тому же заданию.
+type Version struct {
+	WorkID      uuid.UUID
+	SubmittedAt time.Time
+	Text        string
+}
+
+// AttributionSignal — вклад одного признака: положительный говорит за то, что работа —
+// источник, отрицательный — что копия.
+type AttributionSignal struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Detail string  `json:"detail"`
+}
+
+// Attribution — кто у кого вероятнее списал, с точки зрения работы WorkID.
+type Attribution struct {
+	WorkID        uuid.UUID `json:"work_id"`
+	MatchedWorkID uuid.UUID `json:"matched_work_id"`
+	// SourceProbability — оценка вероятности, что WorkID — источник, а не копия.
+	SourceProbability float64             `json:"source_probability"`
+	Role              string              `json:"role"`
+	MatchedRole       string              `json:"matched_role"`
+	SharedWords       int                 `json:"shared_words"`
+	Signals           []AttributionSignal `json:"signals"`
+}
+
+// BuildAttribution оценивает направление списывания по трем признакам:
+//   - порядок сдачи: источник обычно сдан раньше;
+//   - контекст общих фрагментов: у копии они составляют большую долю текста, у источника
+//     окружены собственным текстом автора;
+//   - история версий: общий текст уже был в версии автора, сданной до второй работы.
+func BuildAttribution(d Detector, work, matched VersionedText) Attribution {
+	attr := Attribution{WorkID: work.WorkID, MatchedWorkID: matched.WorkID}
+
+	shared := d.Passages(work.Text, matched.Text)
+	attr.SharedWords = MatchedWords(shared)
+	if attr.SharedWords == 0 {
+		attr.SourceProbability = 0.5
+		attr.Role, attr.MatchedRole = RoleUndetermined, RoleUndetermined
+		attr.Signals = []AttributionSignal{}
+		return attr
+	}
+	matchedShared := MatchedWords(d.Passages(matched.Text, work.Text))
+
+	var order float64
+	switch {
+	case work.SubmittedAt.Before(matched.SubmittedAt):
+		order = weightOrder
+	case work.SubmittedAt.After(matched.SubmittedAt):
+		order = -weightOrder
+	}
+
+	coverage := share(attr.SharedWords, d.CountTokens(work.Text))
+	matchedCoverage := share(matchedShared, d.CountTokens(matched.Text))
+	context := weightContext * (matchedCoverage - coverage)
+
+	prior := priorOverlap(d, work, matched, matchedShared)
+	matchedPrior := priorOverlap(d, matched, work, attr.SharedWords)
+	history := weightHistory * (prior - matchedPrior)
+
+	attr.Signals = []AttributionSignal{
+		{Name: "submission_order", Value: order, Detail: orderDetail(order)},
+		{Name: "passage_context", Value: context, Detail: fmt.Sprintf(
+			"shared passages cover %.0f%% of this work and %.0f%% of the matched work", coverage*100, matchedCoverage*100)},
+		{Name: "version_history", Value: history, Detail: fmt.Sprintf(
+			"earlier versions already contained %.0f%% of the shared text for this author and %.0f%% for the matched author", prior*100, matchedPrior*100)},
+	}
+
+	attr.SourceProbability = math.Round((0.5+(order+context+history)/2)*1000) / 1000
+	switch {
+	case attr.SourceProbability >= 0.5+attributionMargin:
+		attr.Role, attr.MatchedRole = RoleLikelySource, RoleLikelyCopier
+	case attr.SourceProbability <= 0.5-attributionMargin:
+		attr.Role, attr.MatchedRole = RoleLikelyCopier, RoleLikelySource
+	default:
+		attr.Role, attr.MatchedRole = RoleUndetermined, RoleUndetermined
+	}
+	return attr
+}
+
+// priorOverlap — какая доля общего с other текста (sharedWords слов на стороне other) уже
+// была в версиях автора, сданных раньше other. Берется лучшая версия.
+func priorOverlap(d Detector, author, other VersionedText, sharedWords int) float64 {
+	best := 0.0
+	for _, v := range author.Versions {
+		if v.WorkID == author.WorkID || !v.SubmittedAt.Before(other.SubmittedAt) {
+			continue
+		}
+		best = math.Max(best, math.Min(1, share(MatchedWords(d.Passages(other.Text, v.Text)), sharedWords)))
+	}
+	return best
+}
+
+func share(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+func orderDetail(order float64) string {
+	switch {
+	case order > 0:
+		return "this work was submitted first"
+	case order < 0:
+		return "the matched work was submitted first"
+	}
+	return "both works were submitted at the same time"
+}
@@ -0,0 +1,73 @@
+package plagiarism
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sharedPassage = "The migration of birds depends on daylight length and the magnetic field of the earth. "
+	ownIntro      = "In this essay I describe my own observations made near the lake during the autumn. "
+	ownOutro      = "My notes show that the first flocks left two weeks earlier than in the previous year."
+)
+
+func versioned(text string, at time.Time, versions ...Version) VersionedText {
+	return VersionedText{WorkID: uuid.New(), SubmittedAt: at, Text: text, Versions: versions}
+}
+
+func TestBuildAttribution_EarlierWorkWithContextIsSource(t *testing.T) {
+	now := time.Now()
+	source := versioned(ownIntro+sharedPassage+ownOutro, now)
+	copier := versioned(sharedPassage, now.Add(time.Hour))
+	d := NewShingleDetector()
+
+	attr := BuildAttribution(d, source, copier)
+	assert.Equal(t, RoleLikelySource, attr.Role)
+	assert.Equal(t, RoleLikelyCopier, attr.MatchedRole)
+	assert.Greater(t, attr.SourceProbability, 0.6)
+	require.Len(t, attr.Signals, 3)
+	assert.Positive(t, attr.Signals[0].Value, "сдана раньше")
+	assert.Positive(t, attr.Signals[1].Value, "у копии общий текст занимает большую долю")
+
+	reverse := BuildAttribution(d, copier, source)
+	assert.Equal(t, RoleLikelyCopier, reverse.Role)
+	assert.InDelta(t, 1-attr.SourceProbability, reverse.SourceProbability, 1e-9)
+}
+
+func TestBuildAttribution_VersionHistoryOutweighsOrder(t *testing.T) {
+	now := time.Now()
+	draft := Version{WorkID: uuid.New(), SubmittedAt: now, Text: ownIntro + sharedPassage}
+	copier := versioned(sharedPassage, now.Add(time.Hour))
+	// Автор переслал работу после копии, но общий текст был уже в черновике.
+	resubmitted := versioned(ownIntro+sharedPassage+ownOutro, now.Add(2*time.Hour), draft)
+	d := NewShingleDetector()
+
+	attr := BuildAttribution(d, resubmitted, copier)
+	assert.Negative(t, attr.Signals[0].Value, "сдана позже копии")
+	assert.InDelta(t, weightHistory, attr.Signals[2].Value, 1e-9)
+	assert.Equal(t, RoleLikelySource, attr.Role)
+}
+
+func TestBuildAttribution_VersionAfterMatchIgnored(t *testing.T) {
+	now := time.Now()
+	a := versioned(sharedPassage, now)
+	late := Version{WorkID: uuid.New(), SubmittedAt: now.Add(2 * time.Hour), Text: sharedPassage}
+	b := versioned(sharedPassage, now, late)
+
+	attr := BuildAttribution(NewShingleDetector(), b, a)
+	assert.Zero(t, attr.Signals[2].Value, "версия сдана после второй работы")
+	assert.Equal(t, RoleUndetermined, attr.Role)
+}
+
+func TestBuildAttribution_NothingShared(t *testing.T) {
+	now := time.Now()
+	attr := BuildAttribution(NewShingleDetector(), versioned(ownIntro, now), versioned(ownOutro, now.Add(time.Hour)))
+	assert.Zero(t, attr.SharedWords)
+	assert.Equal(t, 0.5, attr.SourceProbability)
+	assert.Equal(t, RoleUndetermined, attr.Role)
+	assert.Empty(t, attr.Signals)
+}
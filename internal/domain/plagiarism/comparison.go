@@ -0,0 +1,49 @@
+package plagiarism
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ComparedText struct {
+	WorkID      uuid.UUID       `json:"work_id"`
+	StudentID   uuid.UUID       `json:"student_id"`
+	SubmittedAt time.Time       `json:"submitted_at"`
+	FileHash    string          `json:"file_sha256,omitempty"`
+	Text        string          `json:"text"`
+	Excluded    []ExcludedRange `json:"excluded_ranges,omitempty"`
+}
+
+// Comparison — подробное сравнение двух работ: оценка, параметры и совпавшие фрагменты.
+type Comparison struct {
+	Source        ComparedText `json:"source"`
+	Match         ComparedText `json:"match"`
+	Score         float64      `json:"score"`
+	Threshold     float64      `json:"threshold"`
+	IsPlagiarized bool         `json:"is_plagiarized"`
+	Parameters    Parameters   `json:"parameters"`
+	Passages      []Passage    `json:"passages"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+func NewComparison(d Detector, source, match ComparedText, threshold float64) (*Comparison, error) {
+	score, err := d.Compare(source.Text, match.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	source.Excluded = d.Exclusions(source.Text)
+	match.Excluded = d.Exclusions(match.Text)
+
+	return &Comparison{
+		Source:        source,
+		Match:         match,
+		Score:         score,
+		Threshold:     threshold,
+		IsPlagiarized: score > threshold,
+		Parameters:    d.Parameters(),
+		Passages:      d.Passages(source.Text, match.Text),
+		CreatedAt:     time.Now(),
+	}, nil
+}
@@ -6,6 +6,16 @@ import (
 	"github.com/google/uuid"
 )
 
+// Причина, по которой построена ревизия отчета.
+const (
+	ReasonSubmission = "submission"
+	ReasonReanalysis = "reanalysis"
+	// ReasonCounterpart — в отчет добавлено совпадение, найденное при проверке другой работы.
+	ReasonCounterpart = "counterpart"
+)
+
+// Report — одна ревизия отчета по работе. Ревизии не перезаписываются: повторный анализ
+// добавляет новую, старые остаются для аудита.
 type Report struct {
 	ID            uuid.UUID
 	WorkID        uuid.UUID
@@ -13,29 +23,118 @@ type Report struct {
 	Score         float64
 
 	MatchedWorkID *uuid.UUID
+	// Matches — работы с наибольшими оценками, включая MatchedWorkID.
+	Matches []Match
 
 	Details AnalysisDetails
+	// Decision — итог правил политики; nil у отчетов, сохраненных до появления правил.
+	Decision *Decision
+
+	// Revision — номер ревизии по работе, начиная с 1; назначается при сохранении.
+	Revision int
+	// Policy — с какими параметрами построен отчет; nil у отчетов, сохраненных до того,
+	// как параметры стали записываться.
+	Policy *Policy
+	Reason string
 
 	CreatedAt time.Time
 }
 
 type AnalysisDetails struct {
-	AlgorithmUsed string `json:"algorithm"`
-	MatchedTokens int    `json:"matched_tokens"`
-	TotalTokens   int    `json:"total_tokens"`
+	AlgorithmUsed  string          `json:"algorithm"`
+	MatchedTokens  int             `json:"matched_tokens"`
+	TotalTokens    int             `json:"total_tokens"`
+	Coverage       float64         `json:"coverage"`
+	ExcludedRanges []ExcludedRange `json:"excluded_ranges,omitempty"`
+	// InsufficientContent: в работе меньше слов, чем нужно для сравнения, и она не оценивалась.
+	InsufficientContent bool `json:"insufficient_content,omitempty"`
+}
+
+// AssignmentEntry — работа задания и последний отчет по ней (nil, если проверки еще не было).
+type AssignmentEntry struct {
+	WorkID      uuid.UUID
+	StudentID   uuid.UUID
+	SubmittedAt time.Time
+	Report      *Report
+}
+
+// NewAnalysisDetails считает покрытие: долю слов работы, попавших в совпавшие фрагменты.
+func NewAnalysisDetails(totalTokens int, passages []Passage, excluded []ExcludedRange) AnalysisDetails {
+	details := AnalysisDetails{
+		AlgorithmUsed:  AlgorithmShingle,
+		MatchedTokens:  MatchedWords(passages),
+		TotalTokens:    totalTokens,
+		ExcludedRanges: excluded,
+	}
+	if totalTokens > 0 {
+		details.Coverage = float64(details.MatchedTokens) / float64(totalTokens)
+	}
+	return details
+}
+
+// NewReport создает отчет и сразу применяет правила политики. SetMatch и SetMatches
+// меняют показатели отчета и принимают решение заново.
+func NewReport(workID uuid.UUID, score float64, policy Policy) *Report {
+	r := &Report{
+		ID:        uuid.New(),
+		WorkID:    workID,
+		Score:     score,
+		Policy:    &policy,
+		Reason:    ReasonSubmission,
+		CreatedAt: time.Now(),
+	}
+	r.decide()
+	return r
 }
 
-func NewReport(workID uuid.UUID, score float64, threshold float64) *Report {
-	return &Report{
-		ID:            uuid.New(),
-		WorkID:        workID,
-		Score:         score,
-		IsPlagiarized: score > threshold,
-		CreatedAt:     time.Now(),
+// NewInsufficientReport — отчет по работе, в которой слишком мало слов для сравнения
+// (см. ShingleDetector.MinTokens): оценки и совпадений нет, плагиатом она не признается.
+func NewInsufficientReport(workID uuid.UUID, totalTokens int, excluded []ExcludedRange, policy Policy) *Report {
+	r := NewReport(workID, 0, policy)
+	r.Details = AnalysisDetails{
+		AlgorithmUsed:       AlgorithmShingle,
+		TotalTokens:         totalTokens,
+		ExcludedRanges:      excluded,
+		InsufficientContent: true,
 	}
+	r.decide()
+	return r
+}
+
+// IsStale: отчет построен не с текущей политикой (или неизвестно, с какой).
+func (r *Report) IsStale(current Policy) bool {
+	return r.Policy == nil || !r.Policy.Equal(current)
 }
 
 func (r *Report) SetMatch(matchedWorkID uuid.UUID, details AnalysisDetails) {
 	r.MatchedWorkID = &matchedWorkID
 	r.Details = details
+	r.decide()
+}
+
+// Metrics — показатели отчета, по которым работают правила.
+func (r *Report) Metrics() Metrics {
+	m := Metrics{
+		Score:        r.Score,
+		Coverage:     r.Details.Coverage,
+		MatchedWords: r.Details.MatchedTokens,
+	}
+	for _, match := range r.Matches {
+		m.Containment = max(m.Containment, match.Containment)
+	}
+	return m
+}
+
+// decide принимает решение по правилам политики. Без политики правила неизвестны,
+// и вывод отчета остается прежним; неоцененная работа правилам не подлежит.
+func (r *Report) decide() {
+	if r.Policy == nil {
+		return
+	}
+	decision := Decision{Severity: SeverityNone}
+	if !r.Details.InsufficientContent {
+		decision = Decide(r.Policy.DecisionRules(), r.Metrics())
+	}
+	r.Decision = &decision
+	r.IsPlagiarized = decision.Severity == SeverityFlag
 }
@@ -0,0 +1,51 @@
+package plagiarism
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport_IsStale(t *testing.T) {
+	policy := Policy{Parameters: NewShingleDetector().Parameters(), Threshold: 0.85}
+	report := NewReport(uuid.New(), 0.9, policy)
+	assert.True(t, report.IsPlagiarized)
+	assert.Equal(t, ReasonSubmission, report.Reason)
+	assert.False(t, report.IsStale(policy))
+
+	lower := policy
+	lower.Threshold = 0.5
+	assert.True(t, report.IsStale(lower), "изменился порог")
+
+	longer := policy
+	longer.Parameters.ShingleLen = 5
+	assert.True(t, report.IsStale(longer), "изменилась длина шингла")
+
+	newer := policy
+	newer.Parameters.Version = "2.0"
+	assert.True(t, report.IsStale(newer), "новая версия алгоритма")
+
+	limited := policy
+	limited.Parameters.MinTokens = 50
+	assert.True(t, report.IsStale(limited), "изменился минимум слов")
+
+	report.Policy = nil
+	assert.True(t, report.IsStale(policy), "параметры старого отчета неизвестны")
+}
+
+func TestNewInsufficientReport(t *testing.T) {
+	policy := Policy{Threshold: 0.5, Rules: []Rule{{
+		Name:     "low",
+		Severity: SeverityInfo,
+		When:     []Condition{{Metric: MetricScore, Op: "<=", Value: 0.1}},
+	}}}
+	report := NewInsufficientReport(uuid.New(), 3, nil, policy)
+
+	assert.True(t, report.Details.InsufficientContent)
+	assert.Equal(t, 3, report.Details.TotalTokens)
+	assert.Zero(t, report.Score)
+	assert.False(t, report.IsPlagiarized)
+	assert.Equal(t, SeverityNone, report.Decision.Severity, "правила к неоцененной работе не применяются")
+	assert.False(t, report.GainsMatch(Match{WorkID: uuid.New(), Score: 0.9}))
+}
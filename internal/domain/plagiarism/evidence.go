@@ -0,0 +1,69 @@
+package plagiarism
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Evidence собирает все, что нужно для печатного отчета по делу о плагиате:
+// сохраненный отчет, проверяемую работу и лучшие совпадения с фрагментами.
+type Evidence struct {
+	Report     *Report
+	Work       ComparedText
+	Matches    []*Comparison
+	Threshold  float64
+	Parameters Parameters
+}
+
+// CanonicalInputs — детерминированное текстовое представление входных данных отчета.
+// Тексты представлены SHA-256 исходных файлов, как они хранятся в files.content_hash,
+// поэтому отчет можно перепроверить по сохраненным данным.
+func (e *Evidence) CanonicalInputs() string {
+	var b strings.Builder
+
+	line := func(key string, value interface{}) {
+		fmt.Fprintf(&b, "%s=%v\n", key, value)
+	}
+
+	line("algorithm", e.Parameters.Algorithm)
+	line("version", e.Parameters.Version)
+	line("shingle_len", e.Parameters.ShingleLen)
+	line("exclude_quotes", e.Parameters.ExcludeQuotes)
+	line("exclude_bibliography", e.Parameters.ExcludeBibliography)
+	// Лимиты появились позже: без них строки не пишутся, и дайджесты старых отчетов сходятся.
+	if e.Parameters.MinTokens > 0 {
+		line("min_tokens", e.Parameters.MinTokens)
+	}
+	if e.Parameters.MinPassageWords > 0 {
+		line("min_passage_words", e.Parameters.MinPassageWords)
+	}
+	line("threshold", fmt.Sprintf("%.6f", e.Threshold))
+	if e.Report != nil {
+		line("report_id", e.Report.ID)
+		line("report_score", fmt.Sprintf("%.6f", e.Report.Score))
+	}
+	line("work_id", e.Work.WorkID)
+	line("student_id", e.Work.StudentID)
+	line("submitted_at", e.Work.SubmittedAt.UTC().Format(time.RFC3339Nano))
+	line("file_sha256", e.Work.FileHash)
+
+	for i, m := range e.Matches {
+		prefix := fmt.Sprintf("match[%d].", i)
+		line(prefix+"work_id", m.Match.WorkID)
+		line(prefix+"student_id", m.Match.StudentID)
+		line(prefix+"submitted_at", m.Match.SubmittedAt.UTC().Format(time.RFC3339Nano))
+		line(prefix+"file_sha256", m.Match.FileHash)
+		line(prefix+"score", fmt.Sprintf("%.6f", m.Score))
+	}
+
+	return b.String()
+}
+
+// Digest — SHA-256 от CanonicalInputs в hex.
+func (e *Evidence) Digest() string {
+	sum := sha256.Sum256([]byte(e.CanonicalInputs()))
+	return hex.EncodeToString(sum[:])
+}
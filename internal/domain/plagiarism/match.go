@@ -0,0 +1,221 @@
+package plagiarism
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxMatches — сколько совпавших работ с наибольшей оценкой сохраняется в отчете.
+const MaxMatches = 10
+
+// Relation — когда сдана совпавшая работа относительно проверяемой.
+const (
+	// RelationEarlier — совпавшая работа сдана раньше: проверяемая могла списать с нее.
+	RelationEarlier = "earlier"
+	// RelationLater — совпавшая работа сдана позже: проверяемая скорее источник.
+	RelationLater = "later"
+)
+
+// Match — работа, с которой у проверяемой нашлось совпадение. Containment — доля шинглов
+// проверяемой работы, найденных в совпавшей; у совпадений, сохраненных до появления поля, 0.
+type Match struct {
+	WorkID      uuid.UUID `json:"work_id"`
+	Score       float64   `json:"score"`
+	Containment float64   `json:"containment,omitempty"`
+	Relation    string    `json:"relation,omitempty"`
+}
+
+func NewMatch(workID uuid.UUID, score, containment float64, submittedAt, matchedSubmittedAt time.Time) Match {
+	relation := RelationEarlier
+	if matchedSubmittedAt.After(submittedAt) {
+		relation = RelationLater
+	}
+	return Match{WorkID: workID, Score: score, Containment: containment, Relation: relation}
+}
+
+// SetMatches сохраняет в отчете до MaxMatches совпадений с ненулевой оценкой, от большей
+// к меньшей, и принимает решение заново. Совпадение с наибольшим Containment остается,
+// даже если по оценке не входит в число лучших: по нему работают правила.
+func (r *Report) SetMatches(matches []Match) {
+	kept := make([]Match, 0, len(matches))
+	for _, m := range matches {
+		if m.Score > 0 {
+			kept = append(kept, m)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Score > kept[j].Score })
+	if len(kept) > MaxMatches {
+		contained := 0
+		for i, m := range kept {
+			if m.Containment > kept[contained].Containment {
+				contained = i
+			}
+		}
+		if contained >= MaxMatches {
+			kept[MaxMatches-1] = kept[contained]
+		}
+		kept = kept[:MaxMatches]
+	}
+	r.Matches = kept
+	r.decide()
+}
+
+// AllMatches — совпадения отчета. У отчетов, сохраненных до появления списка, известна
+// только лучшая совпавшая работа.
+func (r *Report) AllMatches() []Match {
+	if len(r.Matches) > 0 || r.MatchedWorkID == nil {
+		return r.Matches
+	}
+	return []Match{{WorkID: *r.MatchedWorkID, Score: r.Score}}
+}
+
+// MatchRelation — когда сдана лучшая совпавшая работа; пусто, если неизвестно.
+func (r *Report) MatchRelation() string {
+	if r.MatchedWorkID == nil {
+		return ""
+	}
+	for _, m := range r.Matches {
+		if m.WorkID == *r.MatchedWorkID {
+			return m.Relation
+		}
+	}
+	return ""
+}
+
+// FlaggedByLaterOnly: работа признана плагиатом только из-за работ, сданных позже нее, —
+// с нее списали, а не она. Правила политики применяются к каждому совпадению с более
+// ранней работой отдельно; если хоть одно из них само дает flag, ответ false. Без политики
+// или списка совпадений ответ тоже false.
+func (r *Report) FlaggedByLaterOnly() bool {
+	if !r.IsPlagiarized || r.Policy == nil || len(r.Matches) == 0 {
+		return false
+	}
+	rules := r.Policy.DecisionRules()
+	for _, m := range r.Matches {
+		if m.Relation != RelationLater && Decide(rules, r.matchMetrics(m)).Severity == SeverityFlag {
+			return false
+		}
+	}
+	return true
+}
+
+// matchMetrics — показатели отчета, как если бы m было единственным совпадением. Покрытие
+// и число общих слов посчитаны только для лучшего совпадения, у остальных они нулевые.
+func (r *Report) matchMetrics(m Match) Metrics {
+	metrics := Metrics{Score: m.Score, Containment: m.Containment}
+	if r.MatchedWorkID != nil && *r.MatchedWorkID == m.WorkID {
+		metrics.Coverage = r.Details.Coverage
+		metrics.MatchedWords = r.Details.MatchedTokens
+	}
+	return metrics
+}
+
+// GainsMatch: совпадение, найденное при проверке другой работы, стоит добавить в отчет —
+// оно выше порога отчета, сильнее его лучшего совпадения или содержит большую долю работы.
+// Проверка идет в одну сторону, и без этого работа, с которой списали, не узнала бы о копии.
+// Совпадение, уже записанное в отчет с теми же значениями, ничего не меняет.
+func (r *Report) GainsMatch(m Match) bool {
+	if r.Policy == nil || r.Details.InsufficientContent {
+		return false
+	}
+	for _, known := range r.AllMatches() {
+		if known.WorkID == m.WorkID && known.Score == m.Score && known.Containment == m.Containment {
+			return false
+		}
+	}
+	return m.Score > r.Policy.Threshold || m.Score > r.Score || m.Containment > r.Metrics().Containment
+}
+
+// WithMatches — новая ревизия отчета с совпадениями, найденными при проверке других работ.
+// Если сильнейшее из них превосходит лучшее совпадение отчета, оно становится лучшим:
+// оценка и вывод пересчитываются, а details должны описывать фрагменты именно с ним.
+func (r *Report) WithMatches(matches []Match, details AnalysisDetails) *Report {
+	next := *r
+	next.ID = uuid.New()
+	next.Revision = 0
+	next.Reason = ReasonCounterpart
+	next.CreatedAt = time.Now()
+	if r.Policy != nil {
+		policy := *r.Policy
+		next.Policy = &policy
+	}
+
+	added := make(map[uuid.UUID]bool, len(matches))
+	for _, m := range matches {
+		added[m.WorkID] = true
+	}
+	merged := append([]Match{}, matches...)
+	for _, m := range r.AllMatches() {
+		if !added[m.WorkID] {
+			merged = append(merged, m)
+		}
+	}
+	for _, m := range matches {
+		if m.Score > next.Score {
+			id := m.WorkID
+			next.Score = m.Score
+			next.MatchedWorkID = &id
+			next.Details = details
+		}
+	}
+	next.SetMatches(merged)
+	return &next
+}
+
+// MatchChange — совпадение, оценка которого изменилась между ревизиями.
+type MatchChange struct {
+	WorkID    uuid.UUID `json:"work_id"`
+	Relation  string    `json:"relation,omitempty"`
+	FromScore float64   `json:"from_score"`
+	ToScore   float64   `json:"to_score"`
+}
+
+// ReportDiff — чем ревизия To отличается от ревизии From той же работы.
+type ReportDiff struct {
+	FromRevision   int           `json:"from_revision"`
+	ToRevision     int           `json:"to_revision"`
+	ScoreDelta     float64       `json:"score_delta"`
+	VerdictChanged bool          `json:"verdict_changed"`
+	PolicyChanged  bool          `json:"policy_changed"`
+	Appeared       []Match       `json:"appeared"`
+	Disappeared    []Match       `json:"disappeared"`
+	Changed        []MatchChange `json:"changed"`
+}
+
+// DiffReports сравнивает совпадения двух ревизий отчета.
+func DiffReports(from, to *Report) ReportDiff {
+	diff := ReportDiff{
+		FromRevision:   from.Revision,
+		ToRevision:     to.Revision,
+		ScoreDelta:     to.Score - from.Score,
+		VerdictChanged: from.IsPlagiarized != to.IsPlagiarized,
+		PolicyChanged:  from.Policy == nil || to.Policy == nil || !from.Policy.Equal(*to.Policy),
+		Appeared:       []Match{},
+		Disappeared:    []Match{},
+		Changed:        []MatchChange{},
+	}
+
+	before := make(map[uuid.UUID]Match)
+	for _, m := range from.AllMatches() {
+		before[m.WorkID] = m
+	}
+	after := make(map[uuid.UUID]bool)
+	for _, m := range to.AllMatches() {
+		after[m.WorkID] = true
+		prev, ok := before[m.WorkID]
+		switch {
+		case !ok:
+			diff.Appeared = append(diff.Appeared, m)
+		case prev.Score != m.Score:
+			diff.Changed = append(diff.Changed, MatchChange{WorkID: m.WorkID, Relation: m.Relation, FromScore: prev.Score, ToScore: m.Score})
+		}
+	}
+	for _, m := range from.AllMatches() {
+		if !after[m.WorkID] {
+			diff.Disappeared = append(diff.Disappeared, m)
+		}
+	}
+	return diff
+}
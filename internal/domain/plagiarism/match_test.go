@@ -0,0 +1,143 @@
+package plagiarism
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMatch_Relation(t *testing.T) {
+	now := time.Now()
+	id := uuid.New()
+	assert.Equal(t, RelationEarlier, NewMatch(id, 0.5, 0.6, now, now.Add(-time.Hour)).Relation)
+	assert.Equal(t, RelationLater, NewMatch(id, 0.5, 0.6, now, now.Add(time.Hour)).Relation)
+}
+
+func TestReport_SetMatches(t *testing.T) {
+	report := NewReport(uuid.New(), 0, Policy{Threshold: 0.8})
+	matches := []Match{{WorkID: uuid.New(), Score: 0}}
+	for i := 0; i < MaxMatches+2; i++ {
+		matches = append(matches, Match{WorkID: uuid.New(), Score: float64(i+1) / 100})
+	}
+
+	report.SetMatches(matches)
+	require.Len(t, report.Matches, MaxMatches)
+	assert.Equal(t, 0.12, report.Matches[0].Score)
+	assert.Equal(t, 0.03, report.Matches[MaxMatches-1].Score, "нулевые и слабейшие отброшены")
+}
+
+func TestReport_FlaggedByLaterOnly(t *testing.T) {
+	policy := Policy{Threshold: 0.8}
+	later := Match{WorkID: uuid.New(), Score: 0.95, Relation: RelationLater}
+	earlier := Match{WorkID: uuid.New(), Score: 0.9, Relation: RelationEarlier}
+
+	source := NewReport(uuid.New(), later.Score, policy)
+	source.SetMatch(later.WorkID, AnalysisDetails{})
+	source.SetMatches([]Match{later, {WorkID: uuid.New(), Score: 0.3, Relation: RelationEarlier}})
+	assert.True(t, source.FlaggedByLaterOnly(), "слабое раннее совпадение не в счет")
+	assert.Equal(t, RelationLater, source.MatchRelation())
+
+	copier := NewReport(uuid.New(), later.Score, policy)
+	copier.SetMatches([]Match{later, earlier})
+	assert.False(t, copier.FlaggedByLaterOnly())
+
+	clean := NewReport(uuid.New(), 0.3, policy)
+	clean.SetMatches([]Match{{WorkID: uuid.New(), Score: 0.3, Relation: RelationLater}})
+	assert.False(t, clean.FlaggedByLaterOnly())
+}
+
+func TestReport_FlaggedByLaterOnlyFollowsRules(t *testing.T) {
+	policy := Policy{Threshold: 0.85, Rules: []Rule{{
+		Name:     "contained",
+		Severity: SeverityFlag,
+		When:     []Condition{{Metric: MetricContainment, Op: ">", Value: 0.7}},
+	}}}
+	later := Match{WorkID: uuid.New(), Score: 0.4, Containment: 0.9, Relation: RelationLater}
+
+	source := NewReport(uuid.New(), later.Score, policy)
+	source.SetMatch(later.WorkID, AnalysisDetails{})
+	source.SetMatches([]Match{later, {WorkID: uuid.New(), Score: 0.3, Containment: 0.2, Relation: RelationEarlier}})
+	require.True(t, source.IsPlagiarized, "оценка ниже порога, но правило по containment сработало")
+	assert.True(t, source.FlaggedByLaterOnly())
+
+	inserted := NewReport(uuid.New(), later.Score, policy)
+	inserted.SetMatch(later.WorkID, AnalysisDetails{})
+	inserted.SetMatches([]Match{later, {WorkID: uuid.New(), Score: 0.1, Containment: 0.8, Relation: RelationEarlier}})
+	assert.False(t, inserted.FlaggedByLaterOnly(), "ранняя работа с низкой оценкой сама дает flag по правилам")
+}
+
+func TestDiffReports(t *testing.T) {
+	kept, gone, added := uuid.New(), uuid.New(), uuid.New()
+	from := NewReport(uuid.New(), 0.9, Policy{Threshold: 0.85})
+	from.Revision = 1
+	from.SetMatches([]Match{
+		{WorkID: kept, Score: 0.9, Relation: RelationEarlier},
+		{WorkID: gone, Score: 0.4, Relation: RelationEarlier},
+	})
+	to := NewReport(from.WorkID, 0.6, Policy{Threshold: 0.5})
+	to.Revision = 2
+	to.SetMatches([]Match{
+		{WorkID: kept, Score: 0.6, Relation: RelationEarlier},
+		{WorkID: added, Score: 0.55, Relation: RelationLater},
+	})
+
+	diff := DiffReports(from, to)
+	assert.Equal(t, 1, diff.FromRevision)
+	assert.Equal(t, 2, diff.ToRevision)
+	assert.InDelta(t, -0.3, diff.ScoreDelta, 1e-9)
+	assert.False(t, diff.VerdictChanged)
+	assert.True(t, diff.PolicyChanged)
+	assert.Equal(t, []Match{{WorkID: added, Score: 0.55, Relation: RelationLater}}, diff.Appeared)
+	assert.Equal(t, []Match{{WorkID: gone, Score: 0.4, Relation: RelationEarlier}}, diff.Disappeared)
+	assert.Equal(t, []MatchChange{{WorkID: kept, Relation: RelationEarlier, FromScore: 0.9, ToScore: 0.6}}, diff.Changed)
+}
+
+func TestDiffReports_LegacyRevision(t *testing.T) {
+	matched := uuid.New()
+	from := &Report{Score: 0.7, MatchedWorkID: &matched, Revision: 1}
+	to := NewReport(uuid.New(), 0.7, Policy{Threshold: 0.85})
+	to.Revision = 2
+	to.SetMatches([]Match{{WorkID: matched, Score: 0.7, Relation: RelationEarlier}})
+
+	diff := DiffReports(from, to)
+	assert.Empty(t, diff.Appeared)
+	assert.Empty(t, diff.Disappeared)
+	assert.Empty(t, diff.Changed, "лучшее совпадение старой ревизии сопоставляется по работе")
+	assert.True(t, diff.PolicyChanged, "политика старой ревизии неизвестна")
+}
+
+func TestReport_WithMatches(t *testing.T) {
+	policy := Policy{Threshold: 0.8}
+	oldMatch := Match{WorkID: uuid.New(), Score: 0.3, Relation: RelationEarlier}
+	prev := NewReport(uuid.New(), oldMatch.Score, policy)
+	prev.Revision = 1
+	prev.SetMatch(oldMatch.WorkID, AnalysisDetails{MatchedTokens: 5})
+	prev.SetMatches([]Match{oldMatch})
+
+	copier := Match{WorkID: uuid.New(), Score: 0.95, Relation: RelationLater}
+	require.True(t, prev.GainsMatch(copier))
+	assert.False(t, prev.GainsMatch(Match{WorkID: uuid.New(), Score: 0.2}), "слабее лучшего и ниже порога")
+
+	next := prev.WithMatches([]Match{copier}, AnalysisDetails{MatchedTokens: 40})
+	assert.NotEqual(t, prev.ID, next.ID)
+	assert.Zero(t, next.Revision, "номер назначит хранилище")
+	assert.Equal(t, ReasonCounterpart, next.Reason)
+	assert.Equal(t, 0.95, next.Score)
+	assert.True(t, next.IsPlagiarized)
+	assert.Equal(t, copier.WorkID, *next.MatchedWorkID)
+	assert.Equal(t, 40, next.Details.MatchedTokens)
+	assert.Equal(t, []Match{copier, oldMatch}, next.Matches)
+	assert.True(t, next.FlaggedByLaterOnly())
+	assert.False(t, next.GainsMatch(copier), "совпадение уже в отчете")
+
+	assert.Equal(t, 0.3, prev.Score, "прежняя ревизия не меняется")
+	assert.Len(t, prev.Matches, 1)
+
+	weaker := next.WithMatches([]Match{{WorkID: oldMatch.WorkID, Score: 0.5, Relation: RelationEarlier}}, AnalysisDetails{MatchedTokens: 1})
+	assert.Equal(t, 0.95, weaker.Score, "лучшее совпадение остается")
+	assert.Equal(t, 40, weaker.Details.MatchedTokens)
+	assert.Len(t, weaker.Matches, 2, "совпадение с той же работой заменяется")
+}
@@ -0,0 +1,131 @@
+package plagiarism
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// SimilarityMatrix — симметричная матрица попарных оценок работ одного задания.
+type SimilarityMatrix struct {
+	WorkIDs []uuid.UUID
+	Scores  [][]float64
+}
+
+type Cluster struct {
+	WorkIDs  []uuid.UUID `json:"work_ids"`
+	MaxScore float64     `json:"max_score"`
+}
+
+type Pair struct {
+	WorkID        uuid.UUID `json:"work_id"`
+	MatchedWorkID uuid.UUID `json:"matched_work_id"`
+	Score         float64   `json:"score"`
+}
+
+func NewSimilarityMatrix(workIDs []uuid.UUID) *SimilarityMatrix {
+	scores := make([][]float64, len(workIDs))
+	for i := range scores {
+		scores[i] = make([]float64, len(workIDs))
+		scores[i][i] = 1.0
+	}
+	return &SimilarityMatrix{WorkIDs: workIDs, Scores: scores}
+}
+
+// BuildSimilarityMatrix сравнивает каждую пару текстов ровно один раз.
+func BuildSimilarityMatrix(detector Detector, workIDs []uuid.UUID, texts []string) (*SimilarityMatrix, error) {
+	m := NewSimilarityMatrix(workIDs)
+	for i := range workIDs {
+		for j := i + 1; j < len(workIDs); j++ {
+			score, err := detector.Compare(texts[i], texts[j])
+			if err != nil {
+				return nil, err
+			}
+			m.Set(i, j, score)
+		}
+	}
+	return m, nil
+}
+
+func (m *SimilarityMatrix) Set(i, j int, score float64) {
+	m.Scores[i][j] = score
+	m.Scores[j][i] = score
+}
+
+// Pairs возвращает пары с оценкой не ниже threshold, по убыванию оценки.
+func (m *SimilarityMatrix) Pairs(threshold float64) []Pair {
+	var pairs []Pair
+	for i := range m.WorkIDs {
+		for j := i + 1; j < len(m.WorkIDs); j++ {
+			if m.Scores[i][j] >= threshold {
+				pairs = append(pairs, Pair{WorkID: m.WorkIDs[i], MatchedWorkID: m.WorkIDs[j], Score: m.Scores[i][j]})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].Score > pairs[b].Score })
+	return pairs
+}
+
+// Clusters находит компоненты связности графа, где ребро — пара с оценкой не ниже threshold.
+// Это эквивалентно single-linkage кластеризации, срезанной на уровне threshold.
+// Одиночные работы в результат не попадают.
+func (m *SimilarityMatrix) Clusters(threshold float64) []Cluster {
+	parent := make([]int, len(m.WorkIDs))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range m.WorkIDs {
+		for j := i + 1; j < len(m.WorkIDs); j++ {
+			if m.Scores[i][j] >= threshold {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	// Компоненты собираются в порядке наименьшего индекса участника,
+	// чтобы равные по размеру и оценке кластеры не зависели от обхода map.
+	members := make(map[int][]int)
+	var roots []int
+	for i := range m.WorkIDs {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+
+	clusters := make([]Cluster, 0)
+	for _, root := range roots {
+		idx := members[root]
+		if len(idx) < 2 {
+			continue
+		}
+		c := Cluster{}
+		for a, i := range idx {
+			c.WorkIDs = append(c.WorkIDs, m.WorkIDs[i])
+			for _, j := range idx[a+1:] {
+				if m.Scores[i][j] > c.MaxScore {
+					c.MaxScore = m.Scores[i][j]
+				}
+			}
+		}
+		clusters = append(clusters, c)
+	}
+
+	sort.SliceStable(clusters, func(a, b int) bool {
+		if len(clusters[a].WorkIDs) != len(clusters[b].WorkIDs) {
+			return len(clusters[a].WorkIDs) > len(clusters[b].WorkIDs)
+		}
+		return clusters[a].MaxScore > clusters[b].MaxScore
+	})
+	return clusters
+}
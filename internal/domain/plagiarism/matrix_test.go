@@ -0,0 +1,64 @@
+package plagiarism
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimilarityMatrix_Clusters(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	m := NewSimilarityMatrix(ids)
+	// 0-1-2 связаны цепочкой, 3-4 — отдельная пара
+	m.Set(0, 1, 0.9)
+	m.Set(1, 2, 0.7)
+	m.Set(0, 2, 0.3)
+	m.Set(3, 4, 0.8)
+	m.Set(2, 3, 0.1)
+
+	clusters := m.Clusters(0.6)
+	assert.Len(t, clusters, 2)
+	assert.ElementsMatch(t, []uuid.UUID{ids[0], ids[1], ids[2]}, clusters[0].WorkIDs)
+	assert.Equal(t, 0.9, clusters[0].MaxScore)
+	assert.ElementsMatch(t, []uuid.UUID{ids[3], ids[4]}, clusters[1].WorkIDs)
+
+	assert.Empty(t, m.Clusters(0.95))
+
+	pairs := m.Pairs(0.6)
+	assert.Len(t, pairs, 3)
+	assert.Equal(t, 0.9, pairs[0].Score)
+}
+
+func TestSimilarityMatrix_ClustersEqualOrder(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	m := NewSimilarityMatrix(ids)
+	// две равные пары: 4-5 и 1-2, порядок задаёт наименьший индекс участника
+	m.Set(4, 5, 0.8)
+	m.Set(1, 2, 0.8)
+
+	for range 20 {
+		clusters := m.Clusters(0.6)
+		assert.Len(t, clusters, 2)
+		assert.Equal(t, []uuid.UUID{ids[1], ids[2]}, clusters[0].WorkIDs)
+		assert.Equal(t, []uuid.UUID{ids[4], ids[5]}, clusters[1].WorkIDs)
+	}
+}
+
+func TestBuildSimilarityMatrix(t *testing.T) {
+	detector := NewShingleDetector()
+	detector.ShingleLen = 2
+
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	texts := []string{
+		"the quick brown fox jumps",
+		"the quick brown fox jumps",
+		"something else entirely here",
+	}
+
+	m, err := BuildSimilarityMatrix(detector, ids, texts)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, m.Scores[0][1])
+	assert.Equal(t, m.Scores[0][1], m.Scores[1][0])
+	assert.Equal(t, 0.0, m.Scores[0][2])
+}
@@ -0,0 +1,320 @@
+package plagiarism
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	ExclusionQuote        = "quote"
+	ExclusionBibliography = "bibliography"
+)
+
+// NormalizationPolicy управляет тем, какие фрагменты текста не участвуют в подсчете.
+type NormalizationPolicy struct {
+	ExcludeQuotes       bool
+	ExcludeBibliography bool
+}
+
+// ExcludedRange — исключенный из сравнения фрагмент, Start/End — байтовые смещения в исходном тексте.
+type ExcludedRange struct {
+	Kind  string `json:"kind"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+var bibliographyHeadings = map[string]struct{}{
+	"references":        {},
+	"bibliography":      {},
+	"works cited":       {},
+	"список литературы": {},
+	"список использованных источников": {},
+	"список использованной литературы": {},
+	"список источников":                {},
+	"библиография":                     {},
+}
+
+// genericBibliographyHeadings встречаются и как заголовки обычных разделов.
+var genericBibliographyHeadings = map[string]struct{}{
+	"literature": {},
+	"литература": {},
+	"источники":  {},
+}
+
+var quotePairs = []struct{ open, close string }{
+	{"«", "»"},
+	{"“", "”"},
+	{"\"", "\""},
+}
+
+type token struct {
+	word       string
+	start, end int
+	seg        int
+}
+
+// Exclusions находит цитаты и списки литературы согласно политике.
+func (p NormalizationPolicy) Exclusions(text string) []ExcludedRange {
+	var ranges []ExcludedRange
+
+	if p.ExcludeBibliography {
+		ranges = append(ranges, findBibliography(text)...)
+	}
+	if p.ExcludeQuotes {
+		ranges = append(ranges, findBlockquotes(text)...)
+		ranges = append(ranges, findInlineQuotes(text)...)
+	}
+
+	return mergeRanges(text, ranges)
+}
+
+// bibliographyTail — доля текста с конца, в которой ищется список литературы. Заголовок
+// выше (в оглавлении или нарочно вставленный в начало) не исключает основной текст.
+const bibliographyTail = 0.5
+
+func findBibliography(text string) []ExcludedRange {
+	var ranges []ExcludedRange
+
+	// Поиск начинается со строки, в которую попадает граница хвоста.
+	tail := len(text) - int(float64(len(text))*bibliographyTail)
+	for offset := strings.LastIndexByte(text[:tail], '\n') + 1; offset < len(text); {
+		line, next := lineAt(text, offset)
+		if isBibliographyHeading(text, line, next) {
+			end := len(text)
+			for pos := next; pos < len(text); {
+				l, n := lineAt(text, pos)
+				if strings.HasPrefix(strings.TrimSpace(l), "#") {
+					end = pos
+					break
+				}
+				pos = n
+			}
+			ranges = append(ranges, ExcludedRange{Kind: ExclusionBibliography, Start: offset, End: end})
+			offset = end
+			continue
+		}
+		offset = next
+	}
+
+	return ranges
+}
+
+// isBibliographyHeading проверяет, что line — заголовок списка литературы и за ним
+// (текст продолжается с next) действительно идут ссылки.
+func isBibliographyHeading(text, line string, next int) bool {
+	line = strings.TrimSpace(line)
+	line = strings.TrimLeft(line, "#")
+	line = strings.TrimLeftFunc(line, func(r rune) bool {
+		return unicode.IsDigit(r) || r == '.' || unicode.IsSpace(r)
+	})
+	line = strings.TrimRight(line, ":. \t")
+	line = strings.Trim(line, "*_")
+	line = strings.ToLower(line)
+
+	_, specific := bibliographyHeadings[line]
+	_, generic := genericBibliographyHeadings[line]
+	return (specific || generic) && looksLikeReferences(text, next)
+}
+
+// referenceSample — сколько непустых строк после заголовка проверяет looksLikeReferences.
+const referenceSample = 5
+
+// looksLikeReferences проверяет, что большинство первых строк после offset похожи
+// на библиографические записи: нумерованный пункт или год издания.
+func looksLikeReferences(text string, offset int) bool {
+	seen, refs := 0, 0
+	for pos := offset; pos < len(text) && seen < referenceSample; {
+		line, next := lineAt(text, pos)
+		pos = next
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			break
+		}
+		seen++
+		if isReferenceLine(line) {
+			refs++
+		}
+	}
+	return seen > 0 && refs*2 > seen
+}
+
+func isReferenceLine(line string) bool {
+	if strings.HasPrefix(line, "[") || strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "• ") {
+		return true
+	}
+	digits := strings.IndexFunc(line, func(r rune) bool { return !unicode.IsDigit(r) })
+	if digits > 0 && (line[digits] == '.' || line[digits] == ')') {
+		return true
+	}
+	return containsYear(line)
+}
+
+// containsYear ищет четырехзначный год 1800–2099 отдельным числом.
+func containsYear(line string) bool {
+	run := 0
+	for i := 0; i <= len(line); i++ {
+		if i < len(line) && line[i] >= '0' && line[i] <= '9' {
+			run++
+			continue
+		}
+		if run == 4 {
+			prefix := line[i-4 : i-2]
+			if prefix == "18" || prefix == "19" || prefix == "20" {
+				return true
+			}
+		}
+		run = 0
+	}
+	return false
+}
+
+func findBlockquotes(text string) []ExcludedRange {
+	var ranges []ExcludedRange
+
+	for offset := 0; offset < len(text); {
+		line, next := lineAt(text, offset)
+		if strings.HasPrefix(strings.TrimLeft(line, " \t"), ">") {
+			ranges = append(ranges, ExcludedRange{Kind: ExclusionQuote, Start: offset, End: offset + len(line)})
+		}
+		offset = next
+	}
+
+	return ranges
+}
+
+// maxInlineQuoteRunes — предел длины цитаты в строке. Кавычка без пары в пределах абзаца
+// и этой длины считается случайной, иначе она исключила бы из сравнения большую часть текста.
+const maxInlineQuoteRunes = 1000
+
+func findInlineQuotes(text string) []ExcludedRange {
+	var ranges []ExcludedRange
+
+	for _, q := range quotePairs {
+		for offset := 0; offset < len(text); {
+			open := strings.Index(text[offset:], q.open)
+			if open < 0 {
+				break
+			}
+			start := offset + open
+			body := start + len(q.open)
+			closing := strings.Index(text[body:quoteLimit(text, body)], q.close)
+			if closing < 0 {
+				offset = body
+				continue
+			}
+			end := body + closing + len(q.close)
+			ranges = append(ranges, ExcludedRange{Kind: ExclusionQuote, Start: start, End: end})
+			offset = end
+		}
+	}
+
+	return ranges
+}
+
+// quoteLimit — до какого смещения ищется закрывающая кавычка цитаты, начатой в from:
+// до пустой строки, но не дальше maxInlineQuoteRunes символов.
+func quoteLimit(text string, from int) int {
+	limit := len(text)
+	for offset := from; offset < len(text); {
+		line, next := lineAt(text, offset)
+		if offset > from && strings.TrimSpace(line) == "" {
+			limit = offset
+			break
+		}
+		offset = next
+	}
+
+	runes := 0
+	for i := range text[from:limit] {
+		if runes == maxInlineQuoteRunes {
+			return from + i
+		}
+		runes++
+	}
+	return limit
+}
+
+// mergeRanges сортирует диапазоны и склеивает пересекающиеся, сохраняя вид первого.
+func mergeRanges(text string, ranges []ExcludedRange) []ExcludedRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+
+	merged := []ExcludedRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start < last.End {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	for i := range merged {
+		merged[i].Text = text[merged[i].Start:merged[i].End]
+	}
+	return merged
+}
+
+func lineAt(text string, offset int) (string, int) {
+	nl := strings.IndexByte(text[offset:], '\n')
+	if nl < 0 {
+		return text[offset:], len(text)
+	}
+	return text[offset : offset+nl], offset + nl + 1
+}
+
+// tokenize разбивает текст на слова в нижнем регистре, пропуская исключенные диапазоны.
+// Слова по разные стороны исключенного фрагмента попадают в разные сегменты.
+func tokenize(text string, excluded []ExcludedRange) []token {
+	var tokens []token
+
+	seg := 0
+	ex := 0
+	start := -1
+
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, token{
+				word:  strings.ToLower(text[start:end]),
+				start: start,
+				end:   end,
+				seg:   seg,
+			})
+			start = -1
+		}
+	}
+
+	for i, r := range text {
+		for ex < len(excluded) && excluded[ex].End <= i {
+			ex++
+		}
+		if ex < len(excluded) && excluded[ex].Start <= i {
+			if start >= 0 || len(tokens) > 0 && tokens[len(tokens)-1].seg == seg {
+				flush(i)
+				seg++
+			}
+			continue
+		}
+
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+
+	return tokens
+}
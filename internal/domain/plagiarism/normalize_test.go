@@ -0,0 +1,135 @@
+package plagiarism
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// essayBody — основной текст длиннее списка литературы в примерах: список ищется
+// только во второй половине работы.
+const essayBody = "The essay argues that shared passages must be reviewed by a teacher before any verdict is made.\n" +
+	"It compares several approaches and explains why none of them is enough on its own.\n"
+
+func TestNormalizationPolicy_Exclusions(t *testing.T) {
+	policy := NormalizationPolicy{ExcludeQuotes: true, ExcludeBibliography: true}
+
+	tests := []struct {
+		name  string
+		text  string
+		kinds []string
+		texts []string
+	}{
+		{
+			name:  "Straight quotes",
+			text:  `He wrote "to be or not to be" in the play`,
+			kinds: []string{ExclusionQuote},
+			texts: []string{`"to be or not to be"`},
+		},
+		{
+			name:  "Guillemets",
+			text:  "Автор пишет «цитата из книги» и продолжает",
+			kinds: []string{ExclusionQuote},
+			texts: []string{"«цитата из книги»"},
+		},
+		{
+			name:  "Quote does not cross a paragraph break",
+			text:  "He said \"it was late.\n\nNext paragraph \"short quote\" ends",
+			kinds: []string{ExclusionQuote},
+			texts: []string{`"short quote"`},
+		},
+		{
+			name:  "Quote spans a line break within a paragraph",
+			text:  "Автор пишет «первая строка\nвторая строка» и продолжает",
+			kinds: []string{ExclusionQuote},
+			texts: []string{"«первая строка\nвторая строка»"},
+		},
+		{
+			name:  "Markdown blockquote",
+			text:  "Intro line\n> quoted line one\n> quoted line two\nOutro line",
+			kinds: []string{ExclusionQuote, ExclusionQuote},
+			texts: []string{"> quoted line one", "> quoted line two"},
+		},
+		{
+			name:  "Bibliography section",
+			text:  essayBody + "\n## Список литературы\n1. Иванов И.И. Книга\n2. Петров П.П. Статья",
+			kinds: []string{ExclusionBibliography},
+			texts: []string{"## Список литературы\n1. Иванов И.И. Книга\n2. Петров П.П. Статья"},
+		},
+		{
+			name:  "Bibliography ends at next heading",
+			text:  essayBody + "References:\nSmith 2020\n# Appendix\nMore",
+			kinds: []string{ExclusionBibliography},
+			texts: []string{"References:\nSmith 2020\n"},
+		},
+		{
+			name:  "Generic heading followed by references",
+			text:  essayBody + "\nЛитература\n\n1. Иванов И.И. Книга. — М., 2015.\n2. Петров П.П. Статья",
+			kinds: []string{ExclusionBibliography},
+			texts: []string{"Литература\n\n1. Иванов И.И. Книга. — М., 2015.\n2. Петров П.П. Статья"},
+		},
+		{
+			name:  "Heading at the start of the document",
+			text:  "References\n1. Smith J. Title, 2020.\n" + essayBody + essayBody,
+			kinds: []string{},
+			texts: []string{},
+		},
+		{
+			name:  "Specific heading without references",
+			text:  essayBody + "\nReferences\nThe text goes on after this heading as an ordinary paragraph.\nAnd so does this line.",
+			kinds: []string{},
+			texts: []string{},
+		},
+		{
+			name:  "Generic heading of an ordinary section",
+			text:  "Введение\nЛитература\nРусская литература XIX века отличается глубиной.\nОна повлияла на весь мир.",
+			kinds: []string{},
+			texts: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := policy.Exclusions(tt.text)
+			assert.Len(t, ranges, len(tt.kinds))
+			for i, r := range ranges {
+				assert.Equal(t, tt.kinds[i], r.Kind)
+				assert.Equal(t, tt.texts[i], r.Text)
+				assert.Equal(t, tt.text[r.Start:r.End], r.Text)
+			}
+		})
+	}
+}
+
+func TestNormalizationPolicy_LongQuoteIsNotExcluded(t *testing.T) {
+	policy := NormalizationPolicy{ExcludeQuotes: true}
+	long := strings.Repeat("word ", maxInlineQuoteRunes/5+1)
+	assert.Empty(t, policy.Exclusions("«"+long+"»"), "эссе целиком в кавычках остается в сравнении")
+
+	short := strings.Repeat("word ", maxInlineQuoteRunes/10)
+	assert.Len(t, policy.Exclusions("«"+short+"»"), 1)
+}
+
+func TestNormalizationPolicy_Disabled(t *testing.T) {
+	policy := NormalizationPolicy{}
+	assert.Empty(t, policy.Exclusions("He said \"hi\"\n> quote\nReferences\nSmith"))
+}
+
+func TestShingleDetector_IgnoresQuotedText(t *testing.T) {
+	detector := NewShingleDetector()
+	detector.ShingleLen = 2
+
+	quote := `"the quick brown fox jumps over the lazy dog"`
+	text1 := "My own essay starts here. " + quote
+	text2 := "Completely different introduction. " + quote
+
+	score, err := detector.Compare(text1, text2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, score)
+
+	detector.Normalization = NormalizationPolicy{}
+	score, err = detector.Compare(text1, text2)
+	assert.NoError(t, err)
+	assert.Greater(t, score, 0.0)
+}
@@ -0,0 +1,63 @@
+package plagiarism
+
+import "slices"
+
+const (
+	AlgorithmShingle = "shingle"
+
+	// ShingleVersion меняется при любом изменении нормализации или подсчета,
+	// влияющем на оценки.
+	ShingleVersion = "1.3"
+)
+
+// Parameters описывает настройки, с которыми была получена оценка.
+type Parameters struct {
+	Algorithm           string `json:"algorithm"`
+	Version             string `json:"version"`
+	ShingleLen          int    `json:"shingle_len"`
+	ExcludeQuotes       bool   `json:"exclude_quotes"`
+	ExcludeBibliography bool   `json:"exclude_bibliography"`
+	// MinTokens и MinPassageWords — см. ShingleDetector; 0 у отчетов до появления лимитов.
+	MinTokens       int `json:"min_tokens,omitempty"`
+	MinPassageWords int `json:"min_passage_words,omitempty"`
+}
+
+// Policy — параметры алгоритма, порог и правила решения, с которыми строится отчет.
+// Отчет, построенный с другой политикой, устарел: его оценку и вывод нужно пересчитать.
+type Policy struct {
+	Parameters Parameters `json:"parameters"`
+	Threshold  float64    `json:"threshold"`
+	// Rules — правила решения; без них работает DefaultRules(Threshold).
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// NewPolicy собирает политику из параметров детектора, порога и правил в JSON (DECISION_RULES);
+// пустая строка правил оставляет решение по порогу.
+func NewPolicy(params Parameters, threshold float64, rules string) (Policy, error) {
+	policy := Policy{Parameters: params, Threshold: threshold}
+	if rules == "" {
+		return policy, nil
+	}
+	parsed, err := ParseRules([]byte(rules))
+	if err != nil {
+		return Policy{}, err
+	}
+	policy.Rules = parsed
+	return policy, nil
+}
+
+// DecisionRules — правила, по которым принимается решение.
+func (p Policy) DecisionRules() []Rule {
+	if len(p.Rules) == 0 {
+		return DefaultRules(p.Threshold)
+	}
+	return p.Rules
+}
+
+func (p Policy) Equal(other Policy) bool {
+	return p.Parameters == other.Parameters && p.Threshold == other.Threshold &&
+		slices.EqualFunc(p.Rules, other.Rules, func(a, b Rule) bool {
+			return a.Name == b.Name && a.Severity == b.Severity &&
+				slices.Equal(a.When, b.When) && slices.Equal(a.Unless, b.Unless)
+		})
+}
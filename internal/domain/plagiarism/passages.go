@@ -0,0 +1,110 @@
+package plagiarism
+
+import "strings"
+
+// Passage — совпавший фрагмент: байтовые смещения в первом (Source) и втором (Match) текстах.
+type Passage struct {
+	SourceStart int `json:"source_start"`
+	SourceEnd   int `json:"source_end"`
+	MatchStart  int `json:"match_start"`
+	MatchEnd    int `json:"match_end"`
+	Words       int `json:"words"`
+}
+
+// Passages находит максимальные общие фрагменты двух текстов длиной не меньше одного шингла
+// и не короче MinPassageWords. Первый текст проходится жадно слева направо, поэтому
+// фрагменты в нем не пересекаются. Тексты короче MinTokens не сравниваются.
+func (d *ShingleDetector) Passages(text1, text2 string) []Passage {
+	a := tokenize(text1, d.Exclusions(text1))
+	b := tokenize(text2, d.Exclusions(text2))
+	if !d.Sufficient(len(a)) || !d.Sufficient(len(b)) {
+		return nil
+	}
+
+	var passages []Passage
+	for _, r := range d.matchRuns(a, b) {
+		if r.words < d.MinPassageWords {
+			continue
+		}
+		passages = append(passages, Passage{
+			SourceStart: a[r.source].start,
+			SourceEnd:   a[r.source+r.words-1].end,
+			MatchStart:  b[r.match].start,
+			MatchEnd:    b[r.match+r.words-1].end,
+			Words:       r.words,
+		})
+	}
+	return passages
+}
+
+// matchRun — общий фрагмент в индексах слов: начало в первом и втором текстах и длина.
+type matchRun struct {
+	source, match, words int
+}
+
+func (d *ShingleDetector) matchRuns(a, b []token) []matchRun {
+	n := d.ShingleLen
+	if n < 1 || len(a) < n || len(b) < n {
+		return nil
+	}
+
+	index := make(map[string][]int)
+	for j := 0; j <= len(b)-n; j++ {
+		if key, ok := shingleAt(b, j, n); ok {
+			index[key] = append(index[key], j)
+		}
+	}
+
+	var runs []matchRun
+	for i := 0; i <= len(a)-n; {
+		key, ok := shingleAt(a, i, n)
+		starts := index[key]
+		if !ok || len(starts) == 0 {
+			i++
+			continue
+		}
+
+		bestJ, bestLen := starts[0], 0
+		for _, j := range starts {
+			l := n
+			for i+l < len(a) && j+l < len(b) &&
+				a[i+l].word == b[j+l].word &&
+				a[i+l].seg == a[i].seg && b[j+l].seg == b[j].seg {
+				l++
+			}
+			if l > bestLen {
+				bestJ, bestLen = j, l
+			}
+		}
+
+		runs = append(runs, matchRun{source: i, match: bestJ, words: bestLen})
+		i += bestLen
+	}
+
+	return runs
+}
+
+func shingleAt(tokens []token, i, n int) (string, bool) {
+	if tokens[i].seg != tokens[i+n-1].seg {
+		return "", false
+	}
+	words := make([]string, n)
+	for k := 0; k < n; k++ {
+		words[k] = tokens[i+k].word
+	}
+	return strings.Join(words, " "), true
+}
+
+// MatchedWords суммирует длину фрагментов в словах.
+func MatchedWords(passages []Passage) int {
+	total := 0
+	for _, p := range passages {
+		total += p.Words
+	}
+	return total
+}
+
+// CountTokens — число слов текста, участвующих в сравнении (без исключенных фрагментов).
+func (d *ShingleDetector) CountTokens(text string) int {
+	return len(tokenize(text, d.Exclusions(text)))
+}
@@ -8,9 +8,38 @@ import (
 
 type Repository interface {
 	Save(ctx context.Context, report *Report) error
+	// Amend строит новую ревизию из последней ревизии отчета по работе и сохраняет ее под
+	// той же блокировкой, что нумерует ревизии: параллельные дополнения одного отчета не
+	// теряют друг друга. change получает nil, если отчета нет, и возвращает nil, если
+	// менять нечего; тогда и Amend возвращает nil.
+	Amend(ctx context.Context, workID uuid.UUID, change func(latest *Report) (*Report, error)) (*Report, error)
+	// GetByWorkID возвращает последнюю ревизию отчета по работе.
 	GetByWorkID(ctx context.Context, workID uuid.UUID) (*Report, error)
+	// ListByWorkID возвращает все ревизии отчета по работе, от первой к последней.
+	ListByWorkID(ctx context.Context, workID uuid.UUID) ([]*Report, error)
+	// GetByID возвращает конкретный отчет, даже если по работе есть более новый.
+	GetByID(ctx context.Context, id uuid.UUID) (*Report, error)
+	// ListByAssignmentID возвращает все работы задания с последним отчетом по каждой одним запросом.
+	ListByAssignmentID(ctx context.Context, assignmentID uuid.UUID) ([]AssignmentEntry, error)
+}
+
+// Overlap — сходство пары текстов. Similarity — мера Жаккара по шинглам; Containment —
+// доля шинглов первого текста, найденных во втором, MatchedContainment — наоборот.
+// Containment близок к 1, когда короткий текст целиком вставлен в длинный, хотя
+// Similarity у такой пары низкая.
+type Overlap struct {
+	Similarity         float64
+	Containment        float64
+	MatchedContainment float64
 }
 
 type Detector interface {
 	Compare(text1, text2 string) (float64, error)
+	Overlap(text1, text2 string) (Overlap, error)
+	Exclusions(text string) []ExcludedRange
+	Passages(text1, text2 string) []Passage
+	CountTokens(text string) int
+	// Sufficient: в тексте из tokens слов достаточно содержания для сравнения.
+	Sufficient(tokens int) bool
+	Parameters() Parameters
 }
@@ -0,0 +1,242 @@
+package plagiarism
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+// Severity — уровень решения по отчету. Плагиатом работа признается только на SeverityFlag;
+// info и warn подсказывают преподавателю, куда посмотреть.
+type Severity string
+
+const (
+	SeverityNone Severity = "none"
+	SeverityInfo Severity = "info"
+	SeverityWarn Severity = "warn"
+	SeverityFlag Severity = "flag"
+)
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarn:
+		return 2
+	case SeverityFlag:
+		return 3
+	}
+	return 0
+}
+
+// Metric — показатель отчета, на который ссылается условие правила.
+type Metric string
+
+const (
+	// MetricScore — оценка лучшей совпавшей пары (мера Жаккара).
+	MetricScore Metric = "score"
+	// MetricCoverage — доля слов работы во фрагментах, общих с лучшим совпадением.
+	MetricCoverage Metric = "coverage"
+	// MetricContainment — наибольшая по всем совпадениям доля шинглов работы, найденных
+	// в одной совпавшей работе.
+	MetricContainment Metric = "containment"
+	// MetricMatchedWords — число слов во фрагментах, общих с лучшим совпадением.
+	MetricMatchedWords Metric = "matched_words"
+)
+
+// Condition — сравнение показателя с порогом: op — один из >, >=, <, <=.
+type Condition struct {
+	Metric Metric  `json:"metric"`
+	Op     string  `json:"op"`
+	Value  float64 `json:"value"`
+}
+
+// Rule срабатывает, когда выполнено хотя бы одно условие When и ни одно из Unless.
+// Например, {"when": [coverage > 0.4, containment > 0.7], "unless": [matched_words < 200]}:
+// отметить, если покрытие больше 40% или любая пара содержит больше 70% работы, но не при
+// совпадении короче 200 слов.
+type Rule struct {
+	Name     string      `json:"name"`
+	Severity Severity    `json:"severity"`
+	When     []Condition `json:"when"`
+	Unless   []Condition `json:"unless,omitempty"`
+}
+
+// Metrics — значения показателей отчета.
+type Metrics struct {
+	Score        float64
+	Coverage     float64
+	Containment  float64
+	MatchedWords int
+}
+
+// RuleResult — правило, условия которого выполнены, и почему.
+type RuleResult struct {
+	Rule        string   `json:"rule"`
+	Severity    Severity `json:"severity"`
+	Explanation string   `json:"explanation"`
+}
+
+// Decision — итог правил политики. Fired — сработавшие правила, от самого строгого;
+// Suppressed — правила, условия которых выполнены, но отменены исключением Unless.
+type Decision struct {
+	Severity   Severity     `json:"severity"`
+	Fired      []RuleResult `json:"fired,omitempty"`
+	Suppressed []RuleResult `json:"suppressed,omitempty"`
+}
+
+// DefaultRules — правило политики без настроенных правил: плагиат, если оценка выше порога.
+func DefaultRules(threshold float64) []Rule {
+	return []Rule{{
+		Name:     "threshold",
+		Severity: SeverityFlag,
+		When:     []Condition{{Metric: MetricScore, Op: ">", Value: threshold}},
+	}}
+}
+
+// Decide применяет правила к показателям; решение — уровень самого строгого сработавшего правила.
+func Decide(rules []Rule, m Metrics) Decision {
+	decision := Decision{Severity: SeverityNone}
+	for _, rule := range rules {
+		when, ok := firstMet(rule.When, m)
+		if !ok {
+			continue
+		}
+		if unless, ok := firstMet(rule.Unless, m); ok {
+			decision.Suppressed = append(decision.Suppressed, RuleResult{
+				Rule:        rule.Name,
+				Severity:    rule.Severity,
+				Explanation: fmt.Sprintf("%s, ignored: %s", when.describe(m), unless.describe(m)),
+			})
+			continue
+		}
+		decision.Fired = append(decision.Fired, RuleResult{
+			Rule:        rule.Name,
+			Severity:    rule.Severity,
+			Explanation: when.describe(m),
+		})
+		if rule.Severity.rank() > decision.Severity.rank() {
+			decision.Severity = rule.Severity
+		}
+	}
+	slices.SortStableFunc(decision.Fired, func(a, b RuleResult) int {
+		return b.Severity.rank() - a.Severity.rank()
+	})
+	return decision
+}
+
+// Explanation — решение одной строкой для людей: какие правила сработали и на каких значениях.
+func (d Decision) Explanation() string {
+	if len(d.Fired) == 0 {
+		return "no rule fired"
+	}
+	parts := make([]string, len(d.Fired))
+	for i, f := range d.Fired {
+		parts[i] = fmt.Sprintf("%s %q: %s", f.Severity, f.Rule, f.Explanation)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func firstMet(conditions []Condition, m Metrics) (Condition, bool) {
+	for _, c := range conditions {
+		if c.met(m) {
+			return c, true
+		}
+	}
+	return Condition{}, false
+}
+
+func (c Condition) met(m Metrics) bool {
+	v := c.Metric.value(m)
+	switch c.Op {
+	case ">":
+		return v > c.Value
+	case ">=":
+		return v >= c.Value
+	case "<":
+		return v < c.Value
+	case "<=":
+		return v <= c.Value
+	}
+	return false
+}
+
+func (c Condition) describe(m Metrics) string {
+	if c.Metric == MetricMatchedWords {
+		return fmt.Sprintf("%s %d %s %g", c.Metric, m.MatchedWords, c.Op, c.Value)
+	}
+	return fmt.Sprintf("%s %.2f %s %.2f", c.Metric, c.Metric.value(m), c.Op, c.Value)
+}
+
+func (metric Metric) value(m Metrics) float64 {
+	switch metric {
+	case MetricScore:
+		return m.Score
+	case MetricCoverage:
+		return m.Coverage
+	case MetricContainment:
+		return m.Containment
+	case MetricMatchedWords:
+		return float64(m.MatchedWords)
+	}
+	return 0
+}
+
+// ParseRules читает правила из JSON-массива и проверяет их.
+func ParseRules(data []byte) ([]Rule, error) {
+	var rules []Rule
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("%w: rules: %v", shared.ErrInvalidInput, err)
+	}
+	if err := ValidateRules(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ValidateRules проверяет, что у каждого правила есть уникальное имя, известный уровень
+// и хотя бы одно условие с известными показателем и сравнением.
+func ValidateRules(rules []Rule) error {
+	seen := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("%w: rule %d: name is empty", shared.ErrInvalidInput, i)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("%w: rule %q is defined twice", shared.ErrInvalidInput, rule.Name)
+		}
+		seen[rule.Name] = true
+		if rule.Severity.rank() == 0 {
+			return fmt.Errorf("%w: rule %q: severity must be info, warn or flag", shared.ErrInvalidInput, rule.Name)
+		}
+		if len(rule.When) == 0 {
+			return fmt.Errorf("%w: rule %q: at least one condition is required", shared.ErrInvalidInput, rule.Name)
+		}
+		for _, c := range slices.Concat(rule.When, rule.Unless) {
+			if err := c.validate(); err != nil {
+				return fmt.Errorf("%w: rule %q: %v", shared.ErrInvalidInput, rule.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c Condition) validate() error {
+	switch c.Metric {
+	case MetricScore, MetricCoverage, MetricContainment, MetricMatchedWords:
+	default:
+		return fmt.Errorf("unknown metric %q", c.Metric)
+	}
+	switch c.Op {
+	case ">", ">=", "<", "<=":
+	default:
+		return fmt.Errorf("unknown comparison %q", c.Op)
+	}
+	return nil
+}
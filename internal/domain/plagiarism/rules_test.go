@@ -0,0 +1,139 @@
+package plagiarism
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+func exampleRules() []Rule {
+	return []Rule{
+		{
+			Name:     "copied",
+			Severity: SeverityFlag,
+			When: []Condition{
+				{Metric: MetricCoverage, Op: ">", Value: 0.4},
+				{Metric: MetricContainment, Op: ">", Value: 0.7},
+			},
+			Unless: []Condition{{Metric: MetricMatchedWords, Op: "<", Value: 200}},
+		},
+		{
+			Name:     "similar",
+			Severity: SeverityWarn,
+			When:     []Condition{{Metric: MetricScore, Op: ">=", Value: 0.3}},
+		},
+		{
+			Name:     "any_overlap",
+			Severity: SeverityInfo,
+			When:     []Condition{{Metric: MetricMatchedWords, Op: ">", Value: 0}},
+		},
+	}
+}
+
+func TestDecide(t *testing.T) {
+	rules := exampleRules()
+
+	d := Decide(rules, Metrics{Score: 0.35, Coverage: 0.1, Containment: 0.8, MatchedWords: 250})
+	assert.Equal(t, SeverityFlag, d.Severity, "любая пара с containment выше 0.7")
+	require.Len(t, d.Fired, 3)
+	assert.Equal(t, "copied", d.Fired[0].Rule, "самое строгое правило первым")
+	assert.Equal(t, "containment 0.80 > 0.70", d.Fired[0].Explanation)
+	assert.Contains(t, d.Explanation(), `flag "copied": containment 0.80 > 0.70`)
+
+	d = Decide(rules, Metrics{Score: 0.2, Coverage: 0.9, MatchedWords: 120})
+	assert.Equal(t, SeverityInfo, d.Severity, "короткое совпадение не отмечается")
+	require.Len(t, d.Suppressed, 1)
+	assert.Equal(t, "coverage 0.90 > 0.40, ignored: matched_words 120 < 200", d.Suppressed[0].Explanation)
+
+	d = Decide(rules, Metrics{})
+	assert.Equal(t, SeverityNone, d.Severity)
+	assert.Equal(t, "no rule fired", d.Explanation())
+}
+
+func TestReport_DecisionFollowsRules(t *testing.T) {
+	policy := Policy{Threshold: 0.85, Rules: exampleRules()}
+	report := NewReport(uuid.New(), 0.2, policy)
+	assert.False(t, report.IsPlagiarized)
+	require.NotNil(t, report.Decision)
+	assert.Equal(t, SeverityNone, report.Decision.Severity)
+
+	report.SetMatch(uuid.New(), AnalysisDetails{MatchedTokens: 300, TotalTokens: 600, Coverage: 0.5})
+	assert.True(t, report.IsPlagiarized, "оценка ниже порога, но покрытие выше 0.4")
+	assert.Equal(t, SeverityFlag, report.Decision.Severity)
+
+	defaults := NewReport(uuid.New(), 0.9, Policy{Threshold: 0.85})
+	assert.True(t, defaults.IsPlagiarized, "без правил действует порог оценки")
+	assert.Equal(t, "threshold", defaults.Decision.Fired[0].Rule)
+}
+
+func TestReport_SetMatchesKeepsMostContained(t *testing.T) {
+	report := NewReport(uuid.New(), 0, Policy{Threshold: 0.85})
+	var matches []Match
+	for i := 0; i < MaxMatches+2; i++ {
+		matches = append(matches, Match{WorkID: uuid.New(), Score: 0.5 - float64(i)*0.01, Containment: 0.1})
+	}
+	contained := Match{WorkID: uuid.New(), Score: 0.05, Containment: 0.9}
+	report.SetMatches(append(matches, contained))
+
+	require.Len(t, report.Matches, MaxMatches)
+	assert.Equal(t, contained, report.Matches[MaxMatches-1])
+	assert.Equal(t, 0.9, report.Metrics().Containment)
+}
+
+func TestPolicy_Equal(t *testing.T) {
+	a := Policy{Threshold: 0.85, Rules: exampleRules()}
+	b := Policy{Threshold: 0.85, Rules: exampleRules()}
+	assert.True(t, a.Equal(b))
+
+	b.Rules[0].Unless[0].Value = 100
+	assert.False(t, a.Equal(b))
+	assert.False(t, a.Equal(Policy{Threshold: 0.85}))
+	assert.True(t, Policy{Threshold: 0.85}.Equal(Policy{Threshold: 0.85, Rules: []Rule{}}))
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`[{"name":"copied","severity":"flag","when":[{"metric":"coverage","op":">","value":0.4}]}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{{Name: "copied", Severity: SeverityFlag, When: []Condition{{Metric: MetricCoverage, Op: ">", Value: 0.4}}}}, rules)
+
+	cases := map[string]struct {
+		rules    string
+		contains string
+	}{
+		"не JSON":           {`{`, "rules"},
+		"лишнее поле":       {`[{"name":"a","severity":"flag","when":[],"if":1}]`, "unknown field"},
+		"без имени":         {`[{"severity":"flag","when":[{"metric":"score","op":">","value":1}]}]`, "name is empty"},
+		"повтор имени":      {`[{"name":"a","severity":"info","when":[{"metric":"score","op":">","value":1}]},{"name":"a","severity":"info","when":[{"metric":"score","op":">","value":1}]}]`, "defined twice"},
+		"неизвестный level": {`[{"name":"a","severity":"error","when":[{"metric":"score","op":">","value":1}]}]`, "severity"},
+		"без условий":       {`[{"name":"a","severity":"warn"}]`, "at least one condition"},
+		"метрика":           {`[{"name":"a","severity":"warn","when":[{"metric":"words","op":">","value":1}]}]`, "unknown metric"},
+		"сравнение":         {`[{"name":"a","severity":"warn","when":[{"metric":"score","op":"=","value":1}]}]`, "unknown comparison"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tc.rules))
+			require.Error(t, err)
+			assert.ErrorIs(t, err, shared.ErrInvalidInput)
+			assert.Contains(t, err.Error(), tc.contains)
+		})
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	params := NewShingleDetector().Parameters()
+	policy, err := NewPolicy(params, 0.7, "")
+	require.NoError(t, err)
+	assert.Equal(t, Policy{Parameters: params, Threshold: 0.7}, policy)
+
+	policy, err = NewPolicy(params, 0.7, `[{"name":"copied","severity":"flag","when":[{"metric":"coverage","op":">","value":0.4}]}]`)
+	require.NoError(t, err)
+	require.Len(t, policy.Rules, 1)
+	assert.Equal(t, "copied", policy.Rules[0].Name)
+
+	_, err = NewPolicy(params, 0.7, `[{"name":"a"}]`)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+}
@@ -0,0 +1,148 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+// Verdict — решение преподавателя по отчету, которое может расходиться с автоматическим.
+type Verdict string
+
+const (
+	VerdictConfirmed       Verdict = "confirmed"
+	VerdictDismissed       Verdict = "dismissed"
+	VerdictNeedsDiscussion Verdict = "needs_discussion"
+)
+
+// State — состояние проверки отчета преподавателем; после вердикта совпадает с ним.
+type State string
+
+const (
+	StateUnreviewed      State = "unreviewed"
+	StateAssigned        State = "assigned"
+	StateConfirmed       State = State(VerdictConfirmed)
+	StateDismissed       State = State(VerdictDismissed)
+	StateNeedsDiscussion State = State(VerdictNeedsDiscussion)
+)
+
+type EventKind string
+
+const (
+	EventAssigned  EventKind = "assigned"
+	EventCommented EventKind = "commented"
+	EventVerdict   EventKind = "verdict"
+)
+
+var (
+	ErrUnknownVerdict = errors.New("unknown verdict")
+	ErrUnknownState   = errors.New("unknown review state")
+)
+
+func ParseVerdict(s string) (Verdict, error) {
+	switch v := Verdict(s); v {
+	case VerdictConfirmed, VerdictDismissed, VerdictNeedsDiscussion:
+		return v, nil
+	}
+	return "", ErrUnknownVerdict
+}
+
+func ParseState(s string) (State, error) {
+	switch st := State(s); st {
+	case StateUnreviewed, StateAssigned, StateConfirmed, StateDismissed, StateNeedsDiscussion:
+		return st, nil
+	}
+	return "", ErrUnknownState
+}
+
+// Event — запись истории проверки: назначение, комментарий или вердикт.
+type Event struct {
+	ID         uuid.UUID
+	ReportID   uuid.UUID
+	ActorID    uuid.UUID
+	Kind       EventKind
+	AssigneeID *uuid.UUID
+	Verdict    Verdict
+	Comment    string
+	CreatedAt  time.Time
+}
+
+// Review — проверка работы преподавателем. Автоматический результат отчета не меняется,
+// вердикт хранится рядом с ним. Проверка одна на работу и переживает новые ревизии отчета;
+// ReportID — ревизия, по которой ее начали.
+type Review struct {
+	ReportID       uuid.UUID
+	WorkID         uuid.UUID
+	AssignmentID   uuid.UUID
+	AssigneeID     *uuid.UUID
+	Verdict        Verdict
+	VerdictBy      *uuid.UUID
+	VerdictComment string
+	DecidedAt      *time.Time
+	UpdatedAt      time.Time
+	// History заполняется только при загрузке одной проверки, старые события первыми.
+	History []Event
+}
+
+func New(reportID, workID, assignmentID uuid.UUID) *Review {
+	return &Review{ReportID: reportID, WorkID: workID, AssignmentID: assignmentID}
+}
+
+func (r *Review) State() State {
+	switch {
+	case r == nil:
+		return StateUnreviewed
+	case r.Verdict != "":
+		return State(r.Verdict)
+	case r.AssigneeID != nil:
+		return StateAssigned
+	}
+	return StateUnreviewed
+}
+
+// Overrides: вердикт противоречит автоматическому результату отчета.
+func (r *Review) Overrides(isPlagiarized bool) bool {
+	if r == nil {
+		return false
+	}
+	return (r.Verdict == VerdictDismissed && isPlagiarized) || (r.Verdict == VerdictConfirmed && !isPlagiarized)
+}
+
+func (r *Review) Assign(actorID, assigneeID uuid.UUID, now time.Time) Event {
+	r.AssigneeID = &assigneeID
+	return r.record(Event{ActorID: actorID, Kind: EventAssigned, AssigneeID: &assigneeID}, now)
+}
+
+func (r *Review) Comment(actorID uuid.UUID, text string, now time.Time) (Event, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Event{}, fmt.Errorf("%w: comment is empty", shared.ErrInvalidInput)
+	}
+	return r.record(Event{ActorID: actorID, Kind: EventCommented, Comment: text}, now), nil
+}
+
+// SetVerdict выносит или меняет вердикт; прежние решения остаются в истории.
+func (r *Review) SetVerdict(actorID uuid.UUID, verdict Verdict, comment string, now time.Time) (Event, error) {
+	if _, err := ParseVerdict(string(verdict)); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", shared.ErrInvalidInput, err)
+	}
+	comment = strings.TrimSpace(comment)
+
+	decided := now
+	r.Verdict, r.VerdictBy, r.VerdictComment, r.DecidedAt = verdict, &actorID, comment, &decided
+	return r.record(Event{ActorID: actorID, Kind: EventVerdict, Verdict: verdict, Comment: comment}, now), nil
+}
+
+func (r *Review) record(e Event, now time.Time) Event {
+	e.ID = uuid.New()
+	e.ReportID = r.ReportID
+	e.CreatedAt = now
+	r.UpdatedAt = now
+	r.History = append(r.History, e)
+	return e
+}
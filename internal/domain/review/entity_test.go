@@ -0,0 +1,75 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+func TestReview_Workflow(t *testing.T) {
+	teacher, assistant := uuid.New(), uuid.New()
+	now := time.Now()
+
+	r := New(uuid.New(), uuid.New(), uuid.New())
+	assert.Equal(t, StateUnreviewed, r.State())
+
+	r.Assign(teacher, assistant, now)
+	assert.Equal(t, StateAssigned, r.State())
+
+	_, err := r.Comment(assistant, "  ", now)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+	_, err = r.Comment(assistant, "Общий шаблон титульного листа", now)
+	require.NoError(t, err)
+
+	_, err = r.SetVerdict(assistant, VerdictNeedsDiscussion, "", now)
+	require.NoError(t, err)
+	assert.Equal(t, StateNeedsDiscussion, r.State())
+
+	e, err := r.SetVerdict(teacher, VerdictDismissed, "false positive, shared template", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, StateDismissed, r.State())
+	assert.Equal(t, teacher, *r.VerdictBy)
+	assert.Equal(t, r.ReportID, e.ReportID)
+
+	kinds := make([]EventKind, len(r.History))
+	for i, h := range r.History {
+		kinds[i] = h.Kind
+	}
+	assert.Equal(t, []EventKind{EventAssigned, EventCommented, EventVerdict, EventVerdict}, kinds, "прежний вердикт остается в истории")
+
+	_, err = r.SetVerdict(teacher, Verdict("guilty"), "", now)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+	assert.Equal(t, StateDismissed, r.State())
+}
+
+func TestReview_Overrides(t *testing.T) {
+	var none *Review
+	assert.Equal(t, StateUnreviewed, none.State())
+	assert.False(t, none.Overrides(true))
+
+	r := New(uuid.New(), uuid.New(), uuid.New())
+	_, _ = r.SetVerdict(uuid.New(), VerdictDismissed, "", time.Now())
+	assert.True(t, r.Overrides(true))
+	assert.False(t, r.Overrides(false))
+
+	_, _ = r.SetVerdict(uuid.New(), VerdictConfirmed, "", time.Now())
+	assert.True(t, r.Overrides(false))
+	assert.False(t, r.Overrides(true))
+
+	_, _ = r.SetVerdict(uuid.New(), VerdictNeedsDiscussion, "", time.Now())
+	assert.False(t, r.Overrides(true))
+}
+
+func TestParseState(t *testing.T) {
+	st, err := ParseState("needs_discussion")
+	require.NoError(t, err)
+	assert.Equal(t, StateNeedsDiscussion, st)
+
+	_, err = ParseState("closed")
+	assert.ErrorIs(t, err, ErrUnknownState)
+}
@@ -0,0 +1,16 @@
+package review
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Get возвращает проверку работы вместе с историей или shared.ErrNotFound.
+	Get(ctx context.Context, workID uuid.UUID) (*Review, error)
+	// Save сохраняет текущее состояние проверки и добавляет событие в историю одной транзакцией.
+	Save(ctx context.Context, r *Review, e Event) error
+	// ListByAssignment возвращает проверки работ задания без истории, по WorkID.
+	ListByAssignment(ctx context.Context, assignmentID uuid.UUID) (map[uuid.UUID]*Review, error)
+}
@@ -0,0 +1,262 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+// EventType — событие, на которое можно подписаться.
+type EventType string
+
+const (
+	// EventReportCreated — проверка работы завершена, отчет сохранен.
+	EventReportCreated EventType = "report.created"
+	// EventReportFlagged — сохраненный отчет признал работу плагиатом.
+	EventReportFlagged EventType = "report.flagged"
+	// EventReviewUpdated — преподаватель назначил проверяющего, прокомментировал или вынес вердикт.
+	EventReviewUpdated EventType = "review.updated"
+)
+
+func ParseEventType(s string) (EventType, error) {
+	switch t := EventType(s); t {
+	case EventReportCreated, EventReportFlagged, EventReviewUpdated:
+		return t, nil
+	}
+	return "", fmt.Errorf("%w: unknown event %q", shared.ErrInvalidInput, s)
+}
+
+const (
+	// SecretPrefix отличает секреты вебхуков от API-ключей.
+	SecretPrefix = "whsec_"
+	secretBytes  = 32
+	minSecretLen = 16
+)
+
+// Subscription — адрес, на который отправляются выбранные события.
+// Секрет хранится открыто: без него нельзя подписать доставку.
+type Subscription struct {
+	ID     uuid.UUID
+	Name   string
+	URL    string
+	Events []EventType
+	Secret string
+	// AssignmentID ограничивает подписку одним заданием; nil — все задания.
+	AssignmentID *uuid.UUID
+	CreatedBy    uuid.UUID
+	CreatedAt    time.Time
+}
+
+// NewSubscription проверяет адрес и события; пустой secret заменяется сгенерированным.
+func NewSubscription(name, rawURL string, events []EventType, secret string, assignmentID *uuid.UUID, createdBy uuid.UUID, now time.Time) (*Subscription, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", shared.ErrInvalidInput)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http or https URL", shared.ErrInvalidInput)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: at least one event is required", shared.ErrInvalidInput)
+	}
+	if secret == "" {
+		if secret, err = generateSecret(); err != nil {
+			return nil, err
+		}
+	} else if len(secret) < minSecretLen {
+		return nil, fmt.Errorf("%w: secret must be at least %d characters", shared.ErrInvalidInput, minSecretLen)
+	}
+
+	return &Subscription{
+		ID:           uuid.New(),
+		Name:         name,
+		URL:          u.String(),
+		Events:       events,
+		Secret:       secret,
+		AssignmentID: assignmentID,
+		CreatedBy:    createdBy,
+		CreatedAt:    now,
+	}, nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return SecretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Matches: подписка получает событие, если выбрала его тип и задание совпадает.
+func (s *Subscription) Matches(e Event) bool {
+	if s.AssignmentID != nil && *s.AssignmentID != e.AssignmentID {
+		return false
+	}
+	for _, t := range s.Events {
+		if t == e.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// Event — произошедшее в системе событие. Payload — тело, которое получит подписчик.
+type Event struct {
+	ID           uuid.UUID
+	Type         EventType
+	AssignmentID uuid.UUID
+	OccurredAt   time.Time
+	Payload      []byte
+}
+
+type envelope struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       EventType       `json:"type"`
+	OccurredAt string          `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewEvent сериализует data в стандартную обертку {id, type, occurred_at, data}.
+func NewEvent(t EventType, assignmentID uuid.UUID, data interface{}, now time.Time) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s event: %w", t, err)
+	}
+	e := Event{ID: uuid.New(), Type: t, AssignmentID: assignmentID, OccurredAt: now.UTC()}
+	e.Payload, err = json.Marshal(envelope{
+		ID:         e.ID,
+		Type:       t,
+		OccurredAt: e.OccurredAt.Format(time.RFC3339Nano),
+		Data:       raw,
+	})
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s event: %w", t, err)
+	}
+	return e, nil
+}
+
+// DeliveryStatus — состояние доставки события одному подписчику.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// RetryPolicy — повторы с экспоненциальной задержкой: BaseDelay, 2×BaseDelay, ... до MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy: 8 попыток в течение примерно двух часов.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 8, BaseDelay: 30 * time.Second, MaxDelay: time.Hour}
+}
+
+// Backoff — задержка перед следующей попыткой после attempt неудачных.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// Delivery — запись журнала доставок: одно событие одному подписчику.
+type Delivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      EventType
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	// ReplayOf — исходная доставка, если эта создана повторной отправкой.
+	ReplayOf    *uuid.UUID
+	CreatedAt   time.Time
+	DeliveredAt *time.Time
+	UpdatedAt   time.Time
+}
+
+func NewDelivery(s *Subscription, e Event, now time.Time) *Delivery {
+	return &Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: s.ID,
+		EventID:        e.ID,
+		EventType:      e.Type,
+		Payload:        e.Payload,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// Replay создает новую доставку того же события; исходная запись журнала не меняется.
+func (d *Delivery) Replay(now time.Time) *Delivery {
+	id := d.ID
+	return &Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		ReplayOf:       &id,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// RecordAttempt учитывает результат попытки: ответ 2xx завершает доставку, иначе
+// назначается следующая попытка, а после MaxAttempts доставка считается неудавшейся.
+func (d *Delivery) RecordAttempt(statusCode int, sendErr error, p RetryPolicy, now time.Time) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.UpdatedAt = now
+
+	if sendErr == nil && statusCode >= 200 && statusCode < 300 {
+		d.Status = DeliverySucceeded
+		d.LastError = ""
+		delivered := now
+		d.DeliveredAt = &delivered
+		return
+	}
+
+	if sendErr != nil {
+		d.LastError = sendErr.Error()
+	} else {
+		d.LastError = fmt.Sprintf("unexpected status %d", statusCode)
+	}
+	if d.Attempts >= p.MaxAttempts {
+		d.Status = DeliveryFailed
+		return
+	}
+	d.NextAttemptAt = now.Add(p.Backoff(d.Attempts))
+}
+
+// Abandon завершает доставку без отправки, например если подписка удалена.
+func (d *Delivery) Abandon(reason string, now time.Time) {
+	d.Status = DeliveryFailed
+	d.LastError = reason
+	d.UpdatedAt = now
+}
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+func TestNewSubscription(t *testing.T) {
+	now := time.Now()
+	s, err := NewSubscription("LMS", "https://lms.example.com/hooks", []EventType{EventReportCreated}, "", nil, uuid.New(), now)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(s.Secret, SecretPrefix), "секрет генерируется, если не задан")
+
+	_, err = NewSubscription("LMS", "ftp://lms.example.com", []EventType{EventReportCreated}, "", nil, uuid.New(), now)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+	_, err = NewSubscription("LMS", "/relative", []EventType{EventReportCreated}, "", nil, uuid.New(), now)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+	_, err = NewSubscription("LMS", "https://lms.example.com", nil, "", nil, uuid.New(), now)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+	_, err = NewSubscription("LMS", "https://lms.example.com", []EventType{EventReportCreated}, "short", nil, uuid.New(), now)
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+
+	_, err = ParseEventType("report.deleted")
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+}
+
+func TestSubscription_Matches(t *testing.T) {
+	assignment := uuid.New()
+	all, _ := NewSubscription("all", "https://a.example.com", []EventType{EventReportFlagged}, "", nil, uuid.New(), time.Now())
+	one, _ := NewSubscription("one", "https://b.example.com", []EventType{EventReportFlagged, EventReviewUpdated}, "", &assignment, uuid.New(), time.Now())
+
+	flagged, err := NewEvent(EventReportFlagged, assignment, map[string]string{"work_id": "w"}, time.Now())
+	require.NoError(t, err)
+	other, _ := NewEvent(EventReportFlagged, uuid.New(), nil, time.Now())
+	created, _ := NewEvent(EventReportCreated, assignment, nil, time.Now())
+
+	assert.True(t, all.Matches(flagged))
+	assert.True(t, all.Matches(other))
+	assert.False(t, all.Matches(created))
+	assert.True(t, one.Matches(flagged))
+	assert.False(t, one.Matches(other), "фильтр по заданию")
+
+	var body struct {
+		ID   uuid.UUID         `json:"id"`
+		Type string            `json:"type"`
+		Data map[string]string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(flagged.Payload, &body))
+	assert.Equal(t, flagged.ID, body.ID)
+	assert.Equal(t, "report.flagged", body.Type)
+	assert.Equal(t, "w", body.Data["work_id"])
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	assert.Equal(t, time.Second, p.Backoff(1))
+	assert.Equal(t, 2*time.Second, p.Backoff(2))
+	assert.Equal(t, 8*time.Second, p.Backoff(4))
+	assert.Equal(t, 10*time.Second, p.Backoff(5))
+	assert.Equal(t, 10*time.Second, p.Backoff(50))
+}
+
+func TestDelivery_RecordAttempt(t *testing.T) {
+	now := time.Now()
+	p := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+	s, _ := NewSubscription("LMS", "https://lms.example.com", []EventType{EventReportCreated}, "", nil, uuid.New(), now)
+	e, _ := NewEvent(EventReportCreated, uuid.New(), nil, now)
+
+	d := NewDelivery(s, e, now)
+	d.RecordAttempt(http.StatusInternalServerError, nil, p, now)
+	assert.Equal(t, DeliveryPending, d.Status)
+	assert.Equal(t, now.Add(time.Second), d.NextAttemptAt)
+
+	d.RecordAttempt(0, errors.New("connection refused"), p, now)
+	assert.Equal(t, DeliveryPending, d.Status)
+	assert.Equal(t, now.Add(2*time.Second), d.NextAttemptAt)
+	assert.Equal(t, "connection refused", d.LastError)
+
+	d.RecordAttempt(http.StatusBadGateway, nil, p, now)
+	assert.Equal(t, DeliveryFailed, d.Status)
+	assert.Equal(t, 3, d.Attempts)
+
+	replay := d.Replay(now)
+	assert.Equal(t, DeliveryPending, replay.Status)
+	assert.Equal(t, d.ID, *replay.ReplayOf)
+	assert.Equal(t, d.Payload, replay.Payload)
+	assert.Equal(t, DeliveryFailed, d.Status, "исходная запись не меняется")
+
+	replay.RecordAttempt(http.StatusNoContent, nil, p, now)
+	assert.Equal(t, DeliverySucceeded, replay.Status)
+	assert.NotNil(t, replay.DeliveredAt)
+}
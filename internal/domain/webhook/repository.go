@@ -0,0 +1,29 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	SaveSubscription(ctx context.Context, s *Subscription) error
+	// GetSubscription возвращает подписку или shared.ErrNotFound.
+	GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]*Subscription, error)
+	// DeleteSubscription удаляет подписку; журнал ее доставок сохраняется.
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	// SubscriptionsFor возвращает подписки на тип события, фильтр по заданию проверяет Matches.
+	SubscriptionsFor(ctx context.Context, t EventType) ([]*Subscription, error)
+
+	SaveDeliveries(ctx context.Context, ds []*Delivery) error
+	UpdateDelivery(ctx context.Context, d *Delivery) error
+	// GetDelivery возвращает доставку или shared.ErrNotFound.
+	GetDelivery(ctx context.Context, id uuid.UUID) (*Delivery, error)
+	// ListDeliveries возвращает журнал доставок подписки, новые первыми.
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*Delivery, error)
+	// ClaimDue выбирает ожидающие доставки, срок которых наступил, и откладывает их на lease,
+	// чтобы параллельный отправитель не взял их повторно.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Delivery, error)
+}
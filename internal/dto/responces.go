@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+)
 
 type SubmitWorkResponse struct {
 	Success   bool             `json:"success" example:"true"`
@@ -12,6 +16,7 @@ type WorkResponseData struct {
 	WorkID          string         `json:"work_id" example:"550e8400-e29b-41d4-a716-446655440000"`
 	SubmittedAt     time.Time      `json:"submitted_at"`
 	PlagiarismCheck PlagiarismInfo `json:"plagiarism_check"`
+	EventsURL       string         `json:"events_url" example:"/api/v1/works/550e8400-e29b-41d4-a716-446655440000/events"`
 }
 
 type PlagiarismInfo struct {
@@ -20,12 +25,69 @@ type PlagiarismInfo struct {
 	Status        string  `json:"status" example:"checked"`
 }
 
+// WorkReportResponse — тот же конверт, что у монолита: data — service.ReportResponse
+// преподавателю или service.ReportSummary автору работы.
+type WorkReportResponse struct {
+	Success   bool        `json:"success" example:"true"`
+	Data      interface{} `json:"data"`
+	Timestamp time.Time   `json:"timestamp" example:"2025-12-12T19:00:00Z"`
+}
+
 type ErrorResponse struct {
 	Success bool   `json:"success" example:"false"`
 	Error   string `json:"error" example:"Invalid input"`
 }
 
-type WordCloudResponse struct {
-	WorkID       string `json:"work_id" example:"550e..."`
-	WordCloudURL string `json:"word_cloud_url" example:"https://quickchart.io/..."`
+type CreatedAPIKeyResponse struct {
+	Success   bool                  `json:"success" example:"true"`
+	Data      service.CreatedAPIKey `json:"data"`
+	Timestamp time.Time             `json:"timestamp" example:"2025-12-12T19:00:00Z"`
+}
+
+type APIKeyResponse struct {
+	Success   bool               `json:"success" example:"true"`
+	Data      service.APIKeyInfo `json:"data"`
+	Timestamp time.Time          `json:"timestamp" example:"2025-12-12T19:00:00Z"`
+}
+
+type APIKeyListResponse struct {
+	Success   bool                 `json:"success" example:"true"`
+	Data      []service.APIKeyInfo `json:"data"`
+	Timestamp time.Time            `json:"timestamp" example:"2025-12-12T19:00:00Z"`
+}
+
+type AuditLogResponse struct {
+	Success   bool                         `json:"success" example:"true"`
+	Data      []service.AuditEntryResponse `json:"data"`
+	Timestamp time.Time                    `json:"timestamp" example:"2025-12-12T19:00:00Z"`
+}
+
+type AuditVerificationResponse struct {
+	Success   bool                      `json:"success" example:"true"`
+	Data      service.AuditVerification `json:"data"`
+	Timestamp time.Time                 `json:"timestamp" example:"2025-12-12T19:00:00Z"`
+}
+
+type CreatedWebhookResponse struct {
+	Success   bool                   `json:"success" example:"true"`
+	Data      service.CreatedWebhook `json:"data"`
+	Timestamp time.Time              `json:"timestamp" example:"2025-12-12T19:00:00Z"`
+}
+
+type WebhookListResponse struct {
+	Success   bool                  `json:"success" example:"true"`
+	Data      []service.WebhookInfo `json:"data"`
+	Timestamp time.Time             `json:"timestamp" example:"2025-12-12T19:00:00Z"`
+}
+
+type WebhookDeliveryResponse struct {
+	Success   bool                        `json:"success" example:"true"`
+	Data      service.WebhookDeliveryInfo `json:"data"`
+	Timestamp time.Time                   `json:"timestamp" example:"2025-12-12T19:00:00Z"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Success   bool                          `json:"success" example:"true"`
+	Data      []service.WebhookDeliveryInfo `json:"data"`
+	Timestamp time.Time                     `json:"timestamp" example:"2025-12-12T19:00:00Z"`
 }
@@ -0,0 +1,110 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/webhook"
+)
+
+const (
+	// lease — на сколько откладывается взятая доставка; должен превышать таймаут запроса.
+	lease        = time.Minute
+	batchSize    = 50
+	sendTimeout  = 10 * time.Second
+	maxReadReply = 64 << 10
+)
+
+// Dispatcher отправляет ожидающие доставки из журнала и назначает повторы.
+// Несколько экземпляров могут работать с одной базой: доставки разбираются через ClaimDue.
+type Dispatcher struct {
+	repo   webhook.Repository
+	client *http.Client
+	policy webhook.RetryPolicy
+	now    func() time.Time
+}
+
+func NewDispatcher(repo webhook.Repository, policy webhook.RetryPolicy) *Dispatcher {
+	client := &http.Client{
+		Timeout: sendTimeout,
+		// Перенаправление считается неудачной доставкой: адрес подписки должен быть точным.
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	return &Dispatcher{repo: repo, client: client, policy: policy, now: time.Now}
+}
+
+// Run разбирает очередь каждые interval до отмены ctx.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Webhook dispatch failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue отправляет доставки, срок которых наступил, и возвращает их число.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	due, err := d.repo.ClaimDue(ctx, d.now(), lease, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, del := range due {
+		if err := d.deliver(ctx, del); err != nil {
+			return 0, err
+		}
+	}
+	return len(due), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, del *webhook.Delivery) error {
+	sub, err := d.repo.GetSubscription(ctx, del.SubscriptionID)
+	if errors.Is(err, shared.ErrNotFound) {
+		del.Abandon("subscription deleted", d.now())
+		return d.repo.UpdateDelivery(ctx, del)
+	}
+	if err != nil {
+		return err
+	}
+
+	status, sendErr := d.send(ctx, sub, del)
+	del.RecordAttempt(status, sendErr, d.policy, d.now())
+	if del.Status == webhook.DeliveryFailed {
+		log.Printf("Webhook delivery %s to %s failed after %d attempts: %s", del.ID, sub.URL, del.Attempts, del.LastError)
+	}
+	return d.repo.UpdateDelivery(ctx, del)
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub *webhook.Subscription, del *webhook.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(del.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Antiplague-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, string(del.EventType))
+	req.Header.Set(HeaderEventID, del.EventID.String())
+	req.Header.Set(HeaderDelivery, del.ID.String())
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, d.now(), del.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("send: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxReadReply))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,240 @@
+package dispatch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/webhook"
+)
+
+// memoryRepo — журнал доставок в памяти; подписки и доставки хранятся по ID.
+type memoryRepo struct {
+	mu         sync.Mutex
+	subs       map[uuid.UUID]*webhook.Subscription
+	deliveries map[uuid.UUID]*webhook.Delivery
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{subs: map[uuid.UUID]*webhook.Subscription{}, deliveries: map[uuid.UUID]*webhook.Delivery{}}
+}
+
+func (r *memoryRepo) SaveSubscription(_ context.Context, s *webhook.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs[s.ID] = s
+	return nil
+}
+
+func (r *memoryRepo) GetSubscription(_ context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.subs[id]; ok {
+		return s, nil
+	}
+	return nil, shared.ErrNotFound
+}
+
+func (r *memoryRepo) ListSubscriptions(context.Context) ([]*webhook.Subscription, error) {
+	return nil, nil
+}
+
+func (r *memoryRepo) DeleteSubscription(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subs, id)
+	return nil
+}
+
+func (r *memoryRepo) SubscriptionsFor(context.Context, webhook.EventType) ([]*webhook.Subscription, error) {
+	return nil, nil
+}
+
+func (r *memoryRepo) SaveDeliveries(_ context.Context, ds []*webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range ds {
+		cp := *d
+		r.deliveries[d.ID] = &cp
+	}
+	return nil
+}
+
+func (r *memoryRepo) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	return r.SaveDeliveries(ctx, []*webhook.Delivery{d})
+}
+
+func (r *memoryRepo) GetDelivery(_ context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d, ok := r.deliveries[id]; ok {
+		cp := *d
+		return &cp, nil
+	}
+	return nil, shared.ErrNotFound
+}
+
+func (r *memoryRepo) ListDeliveries(context.Context, uuid.UUID, int) ([]*webhook.Delivery, error) {
+	return nil, nil
+}
+
+func (r *memoryRepo) ClaimDue(_ context.Context, now time.Time, lease time.Duration, limit int) ([]*webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*webhook.Delivery
+	for _, d := range r.deliveries {
+		if d.Status == webhook.DeliveryPending && !d.NextAttemptAt.After(now) && len(due) < limit {
+			d.NextAttemptAt = now.Add(lease)
+			cp := *d
+			due = append(due, &cp)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].CreatedAt.Before(due[j].CreatedAt) })
+	return due, nil
+}
+
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	failures int
+	bodies   [][]byte
+	errs     []error
+	headers  []http.Header
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	rc.bodies = append(rc.bodies, body)
+	rc.headers = append(rc.headers, r.Header.Clone())
+	rc.errs = append(rc.errs, Verify(rc.secret, r.Header.Get(HeaderSignature), body, time.Now(), 5*time.Minute))
+	if rc.failures > 0 {
+		rc.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setup(t *testing.T, rc *receiver, policy webhook.RetryPolicy) (*memoryRepo, *Dispatcher, *webhook.Delivery, *time.Time) {
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+
+	now := time.Now()
+	repo := newMemoryRepo()
+	sub, err := webhook.NewSubscription("LMS", srv.URL+"/hooks", []webhook.EventType{webhook.EventReportCreated}, "", nil, uuid.New(), now)
+	require.NoError(t, err)
+	rc.secret = sub.Secret
+	require.NoError(t, repo.SaveSubscription(context.Background(), sub))
+
+	ev, err := webhook.NewEvent(webhook.EventReportCreated, uuid.New(), map[string]float64{"similarity_score": 0.42}, now)
+	require.NoError(t, err)
+	del := webhook.NewDelivery(sub, ev, now)
+	require.NoError(t, repo.SaveDeliveries(context.Background(), []*webhook.Delivery{del}))
+
+	d := NewDispatcher(repo, policy)
+	d.now = func() time.Time { return now }
+	return repo, d, del, &now
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	rc := &receiver{failures: 2}
+	policy := webhook.RetryPolicy{MaxAttempts: 5, BaseDelay: 10 * time.Second, MaxDelay: time.Hour}
+	repo, d, del, now := setup(t, rc, policy)
+
+	n, err := d.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	got, _ := repo.GetDelivery(ctx, del.ID)
+	assert.Equal(t, webhook.DeliveryPending, got.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, got.LastStatusCode)
+	assert.Equal(t, now.Add(10*time.Second), got.NextAttemptAt)
+
+	n, _ = d.DeliverDue(ctx)
+	assert.Zero(t, n, "до истечения задержки повтора нет")
+
+	*now = now.Add(10 * time.Second)
+	_, err = d.DeliverDue(ctx)
+	require.NoError(t, err)
+	got, _ = repo.GetDelivery(ctx, del.ID)
+	assert.Equal(t, now.Add(20*time.Second), got.NextAttemptAt, "задержка удваивается")
+
+	*now = now.Add(20 * time.Second)
+	_, err = d.DeliverDue(ctx)
+	require.NoError(t, err)
+	got, _ = repo.GetDelivery(ctx, del.ID)
+	assert.Equal(t, webhook.DeliverySucceeded, got.Status)
+	assert.Equal(t, 3, got.Attempts)
+	assert.Equal(t, http.StatusNoContent, got.LastStatusCode)
+
+	require.Len(t, rc.bodies, 3)
+	for i := range rc.bodies {
+		assert.NoError(t, rc.errs[i], "каждая попытка подписана")
+		assert.Equal(t, del.Payload, rc.bodies[i])
+		assert.Equal(t, "report.created", rc.headers[i].Get(HeaderEvent))
+		assert.Equal(t, del.ID.String(), rc.headers[i].Get(HeaderDelivery))
+	}
+}
+
+func TestDispatcher_GivesUpAndReplays(t *testing.T) {
+	ctx := context.Background()
+	rc := &receiver{failures: 2}
+	policy := webhook.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Second, MaxDelay: time.Second}
+	repo, d, del, now := setup(t, rc, policy)
+
+	_, _ = d.DeliverDue(ctx)
+	*now = now.Add(time.Second)
+	_, _ = d.DeliverDue(ctx)
+	failed, _ := repo.GetDelivery(ctx, del.ID)
+	assert.Equal(t, webhook.DeliveryFailed, failed.Status)
+
+	*now = now.Add(time.Hour)
+	n, _ := d.DeliverDue(ctx)
+	assert.Zero(t, n, "неудавшаяся доставка больше не отправляется")
+
+	replay := failed.Replay(*now)
+	require.NoError(t, repo.SaveDeliveries(ctx, []*webhook.Delivery{replay}))
+	_, err := d.DeliverDue(ctx)
+	require.NoError(t, err)
+	got, _ := repo.GetDelivery(ctx, replay.ID)
+	assert.Equal(t, webhook.DeliverySucceeded, got.Status)
+	require.Len(t, rc.bodies, 3)
+	assert.Equal(t, rc.bodies[0], rc.bodies[2], "повтор отправляет то же событие")
+}
+
+func TestDispatcher_AbandonsDeletedSubscription(t *testing.T) {
+	ctx := context.Background()
+	rc := &receiver{}
+	repo, d, del, _ := setup(t, rc, webhook.DefaultRetryPolicy())
+	require.NoError(t, repo.DeleteSubscription(ctx, del.SubscriptionID))
+
+	_, err := d.DeliverDue(ctx)
+	require.NoError(t, err)
+	got, _ := repo.GetDelivery(ctx, del.ID)
+	assert.Equal(t, webhook.DeliveryFailed, got.Status)
+	assert.Empty(t, rc.bodies)
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"report.created"}`)
+	now := time.Now()
+	header := Sign("whsec_test_secret_value", now, body)
+
+	assert.NoError(t, Verify("whsec_test_secret_value", header, body, now, time.Minute))
+	assert.ErrorIs(t, Verify("whsec_other_secret_value", header, body, now, time.Minute), ErrSignatureMismatch)
+	assert.ErrorIs(t, Verify("whsec_test_secret_value", header, []byte(`{}`), now, time.Minute), ErrSignatureMismatch)
+	assert.ErrorIs(t, Verify("whsec_test_secret_value", header, body, now.Add(time.Hour), time.Minute), ErrSignatureExpired)
+	assert.ErrorIs(t, Verify("whsec_test_secret_value", "garbage", body, now, time.Minute), ErrMalformedSignature)
+}
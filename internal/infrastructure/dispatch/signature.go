@@ -0,0 +1,69 @@
+package dispatch
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Подпись вебхуков: HMAC-SHA256 секретом подписки над "<timestamp>.<тело>".
+// Получатель проверяет подпись и отбрасывает доставки старше допустимого окна,
+// чтобы перехваченный запрос нельзя было повторить.
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Event-Id"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+var (
+	ErrMalformedSignature = errors.New("malformed webhook signature header")
+	ErrSignatureMismatch  = errors.New("webhook signature mismatch")
+	ErrSignatureExpired   = errors.New("webhook signature timestamp outside the allowed window")
+)
+
+// Sign возвращает значение заголовка X-Webhook-Signature: "t=<unix>,v1=<hex>".
+func Sign(secret string, ts time.Time, body []byte) string {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	return "t=" + t + ",v1=" + mac(secret, t, body)
+}
+
+// Verify проверяет заголовок подписи; пригодится получателям, написанным на Go.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var t, sig string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrMalformedSignature
+		}
+		switch k {
+		case "t":
+			t = v
+		case "v1":
+			sig = v
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || sig == "" {
+		return ErrMalformedSignature
+	}
+	if skew := now.Sub(time.Unix(unix, 0)); skew > tolerance || skew < -tolerance {
+		return ErrSignatureExpired
+	}
+	if !hmac.Equal([]byte(sig), []byte(mac(secret, t, body))) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+func mac(secret, ts string, body []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(ts + "."))
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"sort"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+)
+
+// Segment — кусок текста для вывода: обычный, исключенный или часть совпавшего фрагмента.
+type Segment struct {
+	Text     string
+	Passage  int // номер фрагмента начиная с 1, 0 — не совпадение
+	Excluded bool
+	// Anchors — фрагменты, целиком покрытые предыдущими отметками: их якоря ставятся
+	// перед сегментом, чтобы ссылки с другой стороны сравнения не вели в никуда.
+	Anchors []int
+}
+
+type mark struct {
+	start, end int
+	passage    int
+	excluded   bool
+}
+
+// Highlight режет текст на сегменты по совпавшим фрагментам и исключенным диапазонам.
+// source выбирает сторону фрагмента: true — Source*, false — Match*.
+// Пересекающиеся отметки обрезаются по концу предыдущей; якорь есть у каждого фрагмента.
+func Highlight(text string, passages []plagiarism.Passage, source bool, excluded []plagiarism.ExcludedRange) []Segment {
+	marks := make([]mark, 0, len(passages)+len(excluded))
+	for i, p := range passages {
+		if source {
+			marks = append(marks, mark{start: p.SourceStart, end: p.SourceEnd, passage: i + 1})
+		} else {
+			marks = append(marks, mark{start: p.MatchStart, end: p.MatchEnd, passage: i + 1})
+		}
+	}
+	for _, r := range excluded {
+		marks = append(marks, mark{start: r.Start, end: r.End, excluded: true})
+	}
+	sort.SliceStable(marks, func(i, j int) bool { return marks[i].start < marks[j].start })
+
+	var segments []Segment
+	pos := 0
+	for _, m := range marks {
+		covered := m.start < pos
+		if covered {
+			m.start = pos
+		}
+		if m.end > len(text) {
+			m.end = len(text)
+		}
+		if m.start >= m.end {
+			if m.passage == 0 {
+				continue
+			}
+			if covered {
+				// Фрагмент внутри последней отметки: якорь на ее начале.
+				last := &segments[len(segments)-1]
+				last.Anchors = append(last.Anchors, m.passage)
+			} else {
+				segments = append(segments, Segment{Anchors: []int{m.passage}})
+			}
+			continue
+		}
+		if m.start > pos {
+			segments = append(segments, Segment{Text: text[pos:m.start]})
+		}
+		segments = append(segments, Segment{Text: text[m.start:m.end], Passage: m.passage, Excluded: m.excluded})
+		pos = m.end
+	}
+	if pos < len(text) {
+		segments = append(segments, Segment{Text: text[pos:]})
+	}
+
+	return segments
+}
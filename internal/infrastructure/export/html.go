@@ -0,0 +1,54 @@
+package export
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+)
+
+//go:embed templates/compare.html
+var compareTemplateSource string
+
+// paletteSize должен совпадать с количеством классов .c0…cN в шаблоне.
+const paletteSize = 8
+
+var compareTemplate = template.Must(template.New("compare").Funcs(template.FuncMap{
+	"percent": func(v float64) string { return fmt.Sprintf("%.1f%%", v*100) },
+	"color":   func(passage int) string { return fmt.Sprintf("c%d", (passage-1)%paletteSize) },
+	"excerpt": excerpt,
+	"inc":     func(i int) int { return i + 1 },
+}).Parse(compareTemplateSource))
+
+type compareView struct {
+	*plagiarism.Comparison
+	SourceSegments []Segment
+	MatchSegments  []Segment
+	MatchedWords   int
+}
+
+// WriteComparisonHTML рендерит самодостаточную HTML-страницу сравнения двух работ.
+// Страница не ссылается на внешние ресурсы: стили встроены, навигация — на якорях.
+func WriteComparisonHTML(w io.Writer, c *plagiarism.Comparison) error {
+	view := compareView{
+		Comparison:     c,
+		SourceSegments: Highlight(c.Source.Text, c.Passages, true, c.Source.Excluded),
+		MatchSegments:  Highlight(c.Match.Text, c.Passages, false, c.Match.Excluded),
+	}
+	for _, p := range c.Passages {
+		view.MatchedWords += p.Words
+	}
+
+	return compareTemplate.Execute(w, view)
+}
+
+// excerpt возвращает начало фрагмента не длиннее limit рун.
+func excerpt(text string, start, end, limit int) string {
+	runes := []rune(text[start:end])
+	if len(runes) <= limit {
+		return string(runes)
+	}
+	return string(runes[:limit]) + "…"
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+)
+
+func TestWriteComparisonHTML(t *testing.T) {
+	detector := plagiarism.NewShingleDetector()
+
+	source := plagiarism.ComparedText{
+		WorkID: uuid.New(),
+		Text:   "My intro. The quick brown fox jumps over the lazy dog. <script>alert(1)</script>",
+	}
+	match := plagiarism.ComparedText{
+		WorkID: uuid.New(),
+		Text:   "Another intro. The quick brown fox jumps over the lazy dog. \"quoted words here\"",
+	}
+
+	c, err := plagiarism.NewComparison(detector, source, match, 0.85)
+	assert.NoError(t, err)
+	assert.Len(t, c.Passages, 1)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteComparisonHTML(&buf, c))
+
+	html := buf.String()
+	assert.Contains(t, html, `id="s-1"`)
+	assert.Contains(t, html, `href="#m-1"`)
+	assert.Contains(t, html, `class="excluded"`)
+	assert.NotContains(t, html, "<script>")
+	assert.NotContains(t, html, "http://")
+	assert.NotContains(t, html, "https://")
+}
+
+func TestHighlight_ClipsOverlaps(t *testing.T) {
+	text := "aaaa bbbb cccc"
+	passages := []plagiarism.Passage{
+		{MatchStart: 0, MatchEnd: 9},
+		{MatchStart: 5, MatchEnd: 14},
+	}
+
+	segments := Highlight(text, passages, false, nil)
+	assert.Len(t, segments, 2)
+	assert.Equal(t, "aaaa bbbb", segments[0].Text)
+	assert.Equal(t, " cccc", segments[1].Text)
+	assert.Equal(t, 2, segments[1].Passage)
+}
+
+func TestHighlight_KeepsAnchorsOfCoveredPassages(t *testing.T) {
+	text := "aaaa bbbb cccc"
+	passages := []plagiarism.Passage{
+		{MatchStart: 0, MatchEnd: 14},
+		{MatchStart: 5, MatchEnd: 9},
+		{MatchStart: 20, MatchEnd: 25},
+	}
+
+	segments := Highlight(text, passages, false, nil)
+	assert.Len(t, segments, 2)
+	assert.Equal(t, text, segments[0].Text)
+	assert.Equal(t, 1, segments[0].Passage)
+	assert.Equal(t, []int{2}, segments[0].Anchors, "покрытый фрагмент получает якорь на начале покрывшего")
+	assert.Empty(t, segments[1].Text)
+	assert.Equal(t, []int{3}, segments[1].Anchors, "фрагмент за концом текста получает якорь в конце")
+}
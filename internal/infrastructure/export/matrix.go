@@ -0,0 +1,87 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+)
+
+// WriteMatrixCSV пишет матрицу в виде таблицы: первая строка и первый столбец — ID работ.
+func WriteMatrixCSV(w io.Writer, m *plagiarism.SimilarityMatrix) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, 0, len(m.WorkIDs)+1)
+	header = append(header, "work_id")
+	for _, id := range m.WorkIDs {
+		header = append(header, id.String())
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i, id := range m.WorkIDs {
+		row := make([]string, 0, len(m.WorkIDs)+1)
+		row = append(row, id.String())
+		for _, score := range m.Scores[i] {
+			row = append(row, strconv.FormatFloat(score, 'f', 4, 64))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMatrixDOT пишет неориентированный граф Graphviz: вершины — работы, ребра — пары
+// с оценкой не ниже threshold. Работы одного кластера объединяются в subgraph.
+func WriteMatrixDOT(w io.Writer, m *plagiarism.SimilarityMatrix, threshold float64, labels map[uuid.UUID]string) error {
+	ew := &errWriter{w: w}
+
+	ew.printf("graph similarity {\n")
+	ew.printf("  node [shape=box, fontname=\"Helvetica\"];\n")
+
+	for i, c := range m.Clusters(threshold) {
+		ew.printf("  subgraph cluster_%d {\n", i)
+		ew.printf("    label=%q;\n", fmt.Sprintf("group %d (max %.2f)", i+1, c.MaxScore))
+		for _, id := range c.WorkIDs {
+			ew.printf("    %q;\n", id.String())
+		}
+		ew.printf("  }\n")
+	}
+
+	for _, id := range m.WorkIDs {
+		label := id.String()[:8]
+		if l, ok := labels[id]; ok && l != "" {
+			label = l
+		}
+		ew.printf("  %q [label=%q];\n", id.String(), label)
+	}
+
+	for _, p := range m.Pairs(threshold) {
+		ew.printf("  %q -- %q [label=%q, penwidth=%.1f];\n",
+			p.WorkID.String(), p.MatchedWorkID.String(),
+			strconv.FormatFloat(p.Score, 'f', 2, 64), 1+4*p.Score)
+	}
+
+	ew.printf("}\n")
+	return ew.err
+}
+
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
@@ -0,0 +1,310 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Минимальный генератор PDF 1.4 без внешних зависимостей. Используются стандартные
+// шрифты Helvetica/Courier с однобайтовой кодировкой: WinAnsi плюс кириллица на
+// местах cp1251 (через /Differences). Символы вне этой кодировки заменяются на '?'.
+
+const (
+	pdfPageWidth  = 595.0 // A4, пункты
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+
+	pdfFontRegular = "F1"
+	pdfFontBold    = "F2"
+	pdfFontMono    = "F3"
+)
+
+type rgb struct{ r, g, b float64 }
+
+var (
+	pdfBlack     = rgb{0, 0, 0}
+	pdfGrey      = rgb{0.45, 0.45, 0.45}
+	pdfRed       = rgb{0.69, 0, 0.13}
+	pdfGreen     = rgb{0.1, 0.5, 0.23}
+	pdfHighlight = rgb{1, 0.93, 0.6}
+	pdfQuoteBar  = rgb{0.85, 0.6, 0.1}
+)
+
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+
+	info   map[string]string
+	footer string
+}
+
+func newPDFWriter() *pdfWriter {
+	p := &pdfWriter{info: make(map[string]string)}
+	p.newPage()
+	return p
+}
+
+func (p *pdfWriter) newPage() {
+	p.page = &bytes.Buffer{}
+	p.pages = append(p.pages, p.page)
+	p.y = pdfPageHeight - pdfMargin
+}
+
+// ensure переносит вывод на новую страницу, если до нижнего поля меньше h пунктов.
+func (p *pdfWriter) ensure(h float64) {
+	if p.y-h < pdfMargin+20 {
+		p.newPage()
+	}
+}
+
+func (p *pdfWriter) space(h float64) {
+	p.y -= h
+}
+
+func (p *pdfWriter) textAt(x, y float64, font string, size float64, c rgb, s string) {
+	fmt.Fprintf(p.page, "BT %.3f %.3f %.3f rg /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		c.r, c.g, c.b, font, size, x, y, pdfEscape(s))
+}
+
+func (p *pdfWriter) rect(x, y, w, h float64, c rgb) {
+	fmt.Fprintf(p.page, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n", c.r, c.g, c.b, x, y, w, h)
+}
+
+// paragraph выводит текст с переносом по словам, начиная с отступа indent.
+func (p *pdfWriter) paragraph(font string, size float64, c rgb, indent float64, s string) {
+	lineHeight := size * 1.35
+	for _, line := range wrapText(s, maxChars(font, size, pdfPageWidth-2*pdfMargin-indent)) {
+		p.ensure(lineHeight)
+		p.y -= lineHeight
+		p.textAt(pdfMargin+indent, p.y, font, size, c, line)
+	}
+}
+
+func (p *pdfWriter) heading(s string) {
+	p.space(8)
+	p.paragraph(pdfFontBold, 13, pdfBlack, 0, s)
+	p.space(4)
+}
+
+// field выводит строку «подпись: значение» в две колонки.
+func (p *pdfWriter) field(label, value string, valueColor rgb) {
+	const size, labelWidth = 10.0, 150.0
+	lines := wrapText(value, maxChars(pdfFontRegular, size, pdfPageWidth-2*pdfMargin-labelWidth))
+	for i, line := range lines {
+		p.ensure(size * 1.35)
+		p.y -= size * 1.35
+		if i == 0 {
+			p.textAt(pdfMargin, p.y, pdfFontBold, size, pdfGrey, label)
+		}
+		p.textAt(pdfMargin+labelWidth, p.y, pdfFontRegular, size, valueColor, line)
+	}
+}
+
+// highlighted выводит выдержку на цветной подложке с полосой слева.
+func (p *pdfWriter) highlighted(label, s string) {
+	const size, indent = 9.5, 12.0
+	lineHeight := size * 1.35
+	lines := wrapText(s, maxChars(pdfFontRegular, size, pdfPageWidth-2*pdfMargin-2*indent))
+
+	p.ensure(lineHeight * 2)
+	p.y -= lineHeight
+	p.textAt(pdfMargin+indent, p.y, pdfFontBold, size-1, pdfGrey, label)
+
+	for _, line := range lines {
+		p.ensure(lineHeight)
+		p.y -= lineHeight
+		p.rect(pdfMargin+indent-2, p.y-3, pdfPageWidth-2*pdfMargin-indent, lineHeight, pdfHighlight)
+		p.rect(pdfMargin, p.y-3, 3, lineHeight, pdfQuoteBar)
+		p.textAt(pdfMargin+indent, p.y, pdfFontRegular, size, pdfBlack, line)
+	}
+	p.space(4)
+}
+
+func (p *pdfWriter) rule() {
+	p.ensure(10)
+	p.space(6)
+	p.rect(pdfMargin, p.y, pdfPageWidth-2*pdfMargin, 0.7, pdfGrey)
+	p.space(4)
+}
+
+// WriteTo собирает объекты PDF и таблицу xref.
+func (p *pdfWriter) WriteTo(w io.Writer) (int64, error) {
+	var out bytes.Buffer
+	var offsets []int
+
+	obj := func(body string) int {
+		offsets = append(offsets, out.Len())
+		n := len(offsets)
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", n, body)
+		return n
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Номера объектов фиксированы: 1 — каталог, 2 — дерево страниц, 3 — кодировка, 4–6 — шрифты, 7 — info.
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	pagesIdx := len(offsets)
+	offsets = append(offsets, 0) // зарезервировано под /Pages, пишется после страниц
+	obj(pdfEncoding())
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding 3 0 R >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding 3 0 R >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding 3 0 R >>")
+	obj(p.infoDict())
+
+	var kids []string
+	for i, content := range p.pages {
+		stream := content.String()
+		if p.footer != "" {
+			stream += fmt.Sprintf("BT %.3f %.3f %.3f rg /%s 7.5 Tf %.2f %.2f Td (%s) Tj ET\n",
+				pdfGrey.r, pdfGrey.g, pdfGrey.b, pdfFontMono, pdfMargin, pdfMargin-20.0,
+				pdfEscape(fmt.Sprintf("%s  |  page %d/%d", p.footer, i+1, len(p.pages))))
+		}
+		contentID := obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(stream), stream))
+		pageID := obj(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Contents %d 0 R "+
+				"/Resources << /Font << /%s 4 0 R /%s 5 0 R /%s 6 0 R >> >> >>",
+			pdfPageWidth, pdfPageHeight, contentID, pdfFontRegular, pdfFontBold, pdfFontMono))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+	}
+
+	offsets[pagesIdx] = out.Len()
+	fmt.Fprintf(&out, "2 0 obj\n<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(kids))
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 7 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+func (p *pdfWriter) infoDict() string {
+	var b strings.Builder
+	b.WriteString("<< /Producer (antiplague) ")
+	fmt.Fprintf(&b, "/CreationDate (D:%s) ", time.Now().UTC().Format("20060102150405Z"))
+	for k, v := range p.info {
+		fmt.Fprintf(&b, "/%s (%s) ", k, pdfEscape(v))
+	}
+	b.WriteString(">>")
+	return b.String()
+}
+
+func pdfEncoding() string {
+	var b strings.Builder
+	b.WriteString("<< /Type /Encoding /BaseEncoding /WinAnsiEncoding /Differences [")
+	b.WriteString(" 168 /afii10023 184 /afii10071 185 /afii61352 192")
+	for k := 0; k < 32; k++ { // А..Я без Ё
+		b.WriteString(fmt.Sprintf(" /afii%d", cyrillicUpperGlyph(k)))
+	}
+	for k := 0; k < 32; k++ { // а..я без ё
+		b.WriteString(fmt.Sprintf(" /afii%d", cyrillicUpperGlyph(k)+48))
+	}
+	b.WriteString(" ] >>")
+	return b.String()
+}
+
+// cyrillicUpperGlyph — номер глифа Adobe afii для А+k; Ё (afii10023) стоит между Е и Ж.
+func cyrillicUpperGlyph(k int) int {
+	if k < 6 {
+		return 10017 + k
+	}
+	return 10018 + k
+}
+
+var winAnsiExtra = map[rune]byte{
+	'Ё': 0xA8, 'ё': 0xB8, '№': 0xB9,
+	'«': 0xAB, '»': 0xBB, '…': 0x85, '–': 0x96, '—': 0x97,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '×': 0xD7,
+}
+
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		var c byte
+		switch {
+		case r == '\t':
+			c = ' '
+		case r >= 32 && r < 127:
+			c = byte(r)
+		case r >= 'А' && r <= 'я':
+			c = byte(0xC0 + (r - 'А'))
+		default:
+			if mapped, ok := winAnsiExtra[r]; ok {
+				c = mapped
+			} else {
+				c = '?'
+			}
+		}
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// maxChars грубо оценивает число символов в строке: средняя ширина глифа
+// Helvetica ~0.52 em, моноширинного Courier — 0.6 em.
+func maxChars(font string, size, width float64) int {
+	em := 0.52
+	if font == pdfFontMono {
+		em = 0.6
+	}
+	n := int(width / (size * em))
+	if n < 10 {
+		n = 10
+	}
+	return n
+}
+
+func wrapText(s string, limit int) []string {
+	var lines []string
+	for _, para := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		var line []rune
+		for _, word := range strings.Fields(para) {
+			w := []rune(word)
+			for len(w) > limit {
+				if len(line) > 0 {
+					lines = append(lines, string(line))
+					line = nil
+				}
+				lines = append(lines, string(w[:limit]))
+				w = w[limit:]
+			}
+			switch {
+			case len(line) == 0:
+				line = w
+			case len(line)+1+len(w) <= limit:
+				line = append(append(line, ' '), w...)
+			default:
+				lines = append(lines, string(line))
+				line = w
+			}
+		}
+		lines = append(lines, string(line))
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// truncate обрезает строку до limit рун по границе слова.
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)[:limit]
+	if i := strings.LastIndexByte(string(runes), ' '); i > limit/2 {
+		return string(runes)[:i] + " …"
+	}
+	return string(runes) + "…"
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+)
+
+func TestWriteReportPDF(t *testing.T) {
+	detector := plagiarism.NewShingleDetector()
+
+	source := plagiarism.ComparedText{
+		WorkID:      uuid.New(),
+		StudentID:   uuid.New(),
+		SubmittedAt: time.Date(2025, 12, 10, 21, 58, 55, 0, time.UTC),
+		FileHash:    "aa",
+		Text:        "Введение. Быстрая коричневая лиса прыгает через ленивую собаку (и не только).",
+	}
+	match := plagiarism.ComparedText{
+		WorkID:    uuid.New(),
+		StudentID: uuid.New(),
+		FileHash:  "bb",
+		Text:      "Другое начало. Быстрая коричневая лиса прыгает через ленивую собаку.",
+	}
+	comparison, err := plagiarism.NewComparison(detector, source, match, 0.85)
+	assert.NoError(t, err)
+
+	report := plagiarism.NewReport(source.WorkID, comparison.Score, plagiarism.Policy{Parameters: detector.Parameters(), Threshold: 0.85})
+	evidence := &plagiarism.Evidence{
+		Report:     report,
+		Work:       source,
+		Matches:    []*plagiarism.Comparison{comparison},
+		Threshold:  0.85,
+		Parameters: detector.Parameters(),
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteReportPDF(&buf, evidence))
+	pdf := buf.Bytes()
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "/InputSHA256 ("+evidence.Digest()+")")
+	// Скобки в тексте экранированы, кириллица закодирована в cp1251
+	assert.Contains(t, string(pdf), `\(this work\)`)
+	assert.Contains(t, string(pdf), "(\xc1\xfb\xf1\xf2\xf0\xe0\xff ")
+
+	// startxref указывает на таблицу xref, а каждая запись — на начало своего объекта
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	assert.NotNil(t, start)
+	xref, _ := strconv.Atoi(string(start[1]))
+	assert.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		assert.True(t, bytes.HasPrefix(pdf[off:], []byte(strconv.Itoa(i+1)+" 0 obj")), "object %d", i+1)
+	}
+}
+
+func TestEvidenceDigestIsStable(t *testing.T) {
+	e := &plagiarism.Evidence{
+		Work:       plagiarism.ComparedText{WorkID: uuid.New(), FileHash: "aa"},
+		Threshold:  0.85,
+		Parameters: plagiarism.NewShingleDetector().Parameters(),
+	}
+	digest := e.Digest()
+	assert.Len(t, digest, 64)
+	assert.Equal(t, digest, e.Digest())
+
+	e.Work.FileHash = "ab"
+	assert.NotEqual(t, digest, e.Digest())
+}
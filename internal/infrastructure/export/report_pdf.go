@@ -0,0 +1,153 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/plagiarism"
+)
+
+const (
+	pdfExcerptsPerMatch = 3
+	pdfExcerptRunes     = 600
+)
+
+// WriteReportPDF рендерит печатный отчет по работе для комиссии по академической этике.
+// SHA-256 входных данных (plagiarism.Evidence.Digest) выводится в тексте, в колонтитуле
+// каждой страницы и в метаданных документа (/Keywords, /InputSHA256).
+func WriteReportPDF(w io.Writer, e *plagiarism.Evidence) error {
+	digest := e.Digest()
+
+	p := newPDFWriter()
+	p.info["Title"] = fmt.Sprintf("Plagiarism report for work %s", e.Work.WorkID)
+	p.info["Subject"] = "Academic integrity case file"
+	p.info["Keywords"] = "sha256:" + digest
+	p.info["InputSHA256"] = digest
+	p.footer = "Input SHA-256 " + digest
+
+	p.paragraph(pdfFontBold, 18, pdfBlack, 0, "Plagiarism report")
+	p.paragraph(pdfFontRegular, 9, pdfGrey, 0, "Generated "+time.Now().UTC().Format("2006-01-02 15:04:05 UTC"))
+	p.rule()
+
+	p.heading("Submission")
+	p.field("Work ID", e.Work.WorkID.String(), pdfBlack)
+	p.field("Student ID", e.Work.StudentID.String(), pdfBlack)
+	p.field("Submitted at", formatPDFTime(e.Work.SubmittedAt), pdfBlack)
+	p.field("File SHA-256", e.Work.FileHash, pdfBlack)
+
+	if r := e.Report; r != nil {
+		p.heading("Result")
+		verdict, color := "Not flagged", pdfGreen
+		if r.IsPlagiarized {
+			verdict, color = "Flagged as plagiarism", pdfRed
+		}
+		p.field("Verdict", verdict, color)
+		p.field("Similarity score", formatPercent(r.Score), pdfBlack)
+		if r.MatchedWorkID != nil {
+			p.field("Closest work", r.MatchedWorkID.String(), pdfBlack)
+		}
+		p.field("Checked at", formatPDFTime(r.CreatedAt), pdfBlack)
+		p.field("Report ID", r.ID.String(), pdfBlack)
+		if n := len(e.Work.Excluded); n > 0 {
+			p.field("Excluded fragments", fmt.Sprintf("%d (quotations / bibliography)", n), pdfBlack)
+		}
+	}
+
+	p.heading("Algorithm")
+	p.field("Algorithm", fmt.Sprintf("%s v%s", e.Parameters.Algorithm, e.Parameters.Version), pdfBlack)
+	p.field("Shingle length", fmt.Sprintf("%d words", e.Parameters.ShingleLen), pdfBlack)
+	p.field("Threshold", formatPercent(e.Threshold), pdfBlack)
+	p.field("Quotations", excludedLabel(e.Parameters.ExcludeQuotes), pdfBlack)
+	p.field("Bibliography", excludedLabel(e.Parameters.ExcludeBibliography), pdfBlack)
+
+	p.heading("Top matches")
+	if len(e.Matches) == 0 {
+		p.paragraph(pdfFontRegular, 10, pdfGrey, 0, "No other work of this assignment shares content with this submission.")
+	}
+	for i, m := range e.Matches {
+		p.space(4)
+		color := pdfBlack
+		if m.IsPlagiarized {
+			color = pdfRed
+		}
+		p.paragraph(pdfFontBold, 11, color, 0, fmt.Sprintf("%d. %s similar to work %s", i+1, formatPercent(m.Score), m.Match.WorkID))
+		p.field("Student ID", m.Match.StudentID.String(), pdfBlack)
+		p.field("Submitted at", formatPDFTime(m.Match.SubmittedAt), pdfBlack)
+		p.field("File SHA-256", m.Match.FileHash, pdfBlack)
+		p.field("Matched passages", fmt.Sprintf("%d", len(m.Passages)), pdfBlack)
+	}
+
+	if len(e.Matches) > 0 {
+		p.newPage()
+		p.heading("Highlighted excerpts")
+	}
+	for i, m := range e.Matches {
+		p.paragraph(pdfFontBold, 11, pdfBlack, 0, fmt.Sprintf("Match %d: work %s", i+1, m.Match.WorkID))
+		for j, passage := range longestPassages(m.Passages, pdfExcerptsPerMatch) {
+			label := fmt.Sprintf("Passage %d, %d words", j+1, passage.Words)
+			p.highlighted(label+" (this work)", truncate(e.Work.Text[passage.SourceStart:passage.SourceEnd], pdfExcerptRunes))
+			p.highlighted(label+" (matched work)", truncate(m.Match.Text[passage.MatchStart:passage.MatchEnd], pdfExcerptRunes))
+		}
+		p.space(6)
+	}
+
+	p.heading("Verification")
+	p.paragraph(pdfFontRegular, 9.5, pdfBlack, 0,
+		"The digest below is SHA-256 over the inputs listed here. File hashes match files.content_hash "+
+			"in the system database, so the report can be re-derived and checked against stored data.")
+	p.space(4)
+	p.paragraph(pdfFontMono, 9, pdfBlack, 0, "SHA-256: "+digest)
+	p.space(4)
+	for _, line := range strings.Split(strings.TrimRight(e.CanonicalInputs(), "\n"), "\n") {
+		p.paragraph(pdfFontMono, 7.5, pdfGrey, 0, line)
+	}
+
+	_, err := p.WriteTo(w)
+	return err
+}
+
+// longestPassages возвращает до n самых длинных фрагментов в порядке их следования в тексте.
+func longestPassages(passages []plagiarism.Passage, n int) []plagiarism.Passage {
+	if len(passages) <= n {
+		return passages
+	}
+
+	chosen := make([]bool, len(passages))
+	for k := 0; k < n; k++ {
+		best := -1
+		for i, p := range passages {
+			if !chosen[i] && (best < 0 || p.Words > passages[best].Words) {
+				best = i
+			}
+		}
+		chosen[best] = true
+	}
+
+	result := make([]plagiarism.Passage, 0, n)
+	for i, p := range passages {
+		if chosen[i] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func formatPDFTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
+
+func formatPercent(v float64) string {
+	return fmt.Sprintf("%.1f%%", v*100)
+}
+
+func excludedLabel(excluded bool) string {
+	if excluded {
+		return "excluded from scoring"
+	}
+	return "counted"
+}
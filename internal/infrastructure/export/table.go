@@ -0,0 +1,213 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableWriter построчно пишет таблицу; строки уходят в writer сразу, без буферизации всего файла.
+// Ячейки: string, float64, int, time.Time или nil (пустая ячейка).
+type TableWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(cells []interface{}) error
+	Close() error
+}
+
+type csvTable struct {
+	w *csv.Writer
+}
+
+func NewCSVTable(w io.Writer) TableWriter {
+	return &csvTable{w: csv.NewWriter(w)}
+}
+
+func (t *csvTable) WriteHeader(columns []string) error {
+	return t.w.Write(columns)
+}
+
+func (t *csvTable) WriteRow(cells []interface{}) error {
+	record := make([]string, len(cells))
+	for i, c := range cells {
+		record[i] = formatCell(c)
+	}
+	if err := t.w.Write(record); err != nil {
+		return err
+	}
+	t.w.Flush()
+	return t.w.Error()
+}
+
+func (t *csvTable) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+func formatCell(c interface{}) string {
+	switch v := c.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', 4, 64)
+	case int:
+		return strconv.Itoa(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// xlsxTable пишет минимальную книгу Office Open XML с одним листом.
+// Строки хранятся как inline strings, поэтому sharedStrings.xml не нужен
+// и лист можно писать потоково.
+type xlsxTable struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+}
+
+func NewXLSXTable(w io.Writer, sheetName string) (TableWriter, error) {
+	zw := zip.NewWriter(w)
+
+	static := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, xmlEscape(sheetName))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, f := range static {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(fw, f.body); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, xlsxSheetHeader); err != nil {
+		return nil, err
+	}
+
+	return &xlsxTable{zw: zw, sheet: sheet}, nil
+}
+
+func (t *xlsxTable) WriteHeader(columns []string) error {
+	cells := make([]interface{}, len(columns))
+	for i, c := range columns {
+		cells[i] = c
+	}
+	return t.writeRow(cells, xlsxStyleHeader)
+}
+
+func (t *xlsxTable) WriteRow(cells []interface{}) error {
+	return t.writeRow(cells, 0)
+}
+
+func (t *xlsxTable) writeRow(cells []interface{}, style int) error {
+	t.row++
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, t.row)
+	for i, c := range cells {
+		ref := xlsxColumn(i) + strconv.Itoa(t.row)
+		styleAttr := ""
+		if style != 0 {
+			styleAttr = fmt.Sprintf(` s="%d"`, style)
+		}
+
+		switch v := c.(type) {
+		case nil:
+			continue
+		case float64:
+			fmt.Fprintf(&b, `<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, strconv.FormatFloat(v, 'f', -1, 64))
+		case int:
+			fmt.Fprintf(&b, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+		default:
+			fmt.Fprintf(&b, `<c r="%s" t="inlineStr"%s><is><t>%s</t></is></c>`, ref, styleAttr, xmlEscape(formatCell(c)))
+		}
+	}
+	b.WriteString("</row>")
+
+	_, err := io.WriteString(t.sheet, b.String())
+	return err
+}
+
+func (t *xlsxTable) Close() error {
+	if _, err := io.WriteString(t.sheet, xlsxSheetFooter); err != nil {
+		return err
+	}
+	return t.zw.Close()
+}
+
+// xlsxColumn переводит индекс столбца с нуля в буквенное имя: 0 -> A, 26 -> AA.
+func xlsxColumn(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const xlsxStyleHeader = 1
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>
+</styleSheet>`
+
+const xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const xlsxSheetFooter = `</sheetData></worksheet>`
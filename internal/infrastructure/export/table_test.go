@@ -0,0 +1,58 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVTable(t *testing.T) {
+	var buf bytes.Buffer
+	table := NewCSVTable(&buf)
+
+	assert.NoError(t, table.WriteHeader([]string{"work_id", "score", "status"}))
+	assert.NoError(t, table.WriteRow([]interface{}{"w1", 0.5, "clean"}))
+	assert.NoError(t, table.WriteRow([]interface{}{"w2", nil, "pending"}))
+	assert.NoError(t, table.Close())
+
+	assert.Equal(t, "work_id,score,status\nw1,0.5000,clean\nw2,,pending\n", buf.String())
+}
+
+func TestXLSXTable(t *testing.T) {
+	var buf bytes.Buffer
+	table, err := NewXLSXTable(&buf, "Reports")
+	assert.NoError(t, err)
+
+	assert.NoError(t, table.WriteHeader([]string{"student_id", "score"}))
+	assert.NoError(t, table.WriteRow([]interface{}{"Иванов <A&B>", 0.25}))
+	assert.NoError(t, table.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(body)
+	}
+
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files, "xl/workbook.xml")
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A1" t="inlineStr" s="1"><is><t>student_id</t></is></c>`)
+	assert.Contains(t, sheet, `<t>Иванов &lt;A&amp;B&gt;</t>`)
+	assert.Contains(t, sheet, `<c r="B2"><v>0.25</v></c>`)
+}
+
+func TestXLSXColumn(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumn(0))
+	assert.Equal(t, "Z", xlsxColumn(25))
+	assert.Equal(t, "AA", xlsxColumn(26))
+	assert.Equal(t, "BA", xlsxColumn(52))
+}
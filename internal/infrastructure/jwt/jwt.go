@@ -0,0 +1,250 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+)
+
+// Компактная реализация JWS (RFC 7515/7519) для двух алгоритмов: HS256 с общим секретом
+// и RS256 с парой ключей. Другие алгоритмы, в том числе "none", отвергаются.
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+
+	// leeway — допуск на расхождение часов между выпускающей стороной и сервисами.
+	leeway = 30 * time.Second
+)
+
+var (
+	ErrMalformed        = errors.New("malformed token")
+	ErrUnsupportedAlg   = errors.New("unsupported signing algorithm")
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrExpired          = errors.New("token expired")
+	ErrNotYetValid      = errors.New("token not valid yet")
+	ErrInvalidIssuer    = errors.New("invalid token issuer")
+	ErrInvalidClaims    = errors.New("invalid token claims")
+	ErrNotConfigured    = errors.New("JWT verification is not configured: set JWT_SECRET or JWT_PUBLIC_KEY_FILE")
+)
+
+// Claims — полезная нагрузка токена. Assignments — задания, которые ведет преподаватель.
+type Claims struct {
+	Subject     string   `json:"sub"`
+	Role        string   `json:"role"`
+	Assignments []string `json:"assignments,omitempty"`
+	Issuer      string   `json:"iss,omitempty"`
+	IssuedAt    int64    `json:"iat,omitempty"`
+	NotBefore   int64    `json:"nbf,omitempty"`
+	ExpiresAt   int64    `json:"exp"`
+}
+
+// Principal переводит утверждения токена в доменного пользователя.
+func (c Claims) Principal() (*auth.Principal, error) {
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sub must be a UUID", ErrInvalidClaims)
+	}
+	role, err := auth.ParseRole(c.Role)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidClaims, err)
+	}
+
+	p := &auth.Principal{UserID: userID, Role: role}
+	for _, a := range c.Assignments {
+		id, err := uuid.Parse(a)
+		if err != nil {
+			return nil, fmt.Errorf("%w: assignments must be UUIDs", ErrInvalidClaims)
+		}
+		p.Assignments = append(p.Assignments, id)
+	}
+	return p, nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+type Signer struct {
+	alg        string
+	secret     []byte
+	privateKey *rsa.PrivateKey
+}
+
+func NewHMACSigner(secret []byte) *Signer {
+	return &Signer{alg: AlgHS256, secret: secret}
+}
+
+func NewRSASigner(key *rsa.PrivateKey) *Signer {
+	return &Signer{alg: AlgRS256, privateKey: key}
+}
+
+func (s *Signer) Sign(claims Claims) (string, error) {
+	h, err := json.Marshal(header{Alg: s.alg, Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	p, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encode(h) + "." + encode(p)
+	var sig []byte
+	switch s.alg {
+	case AlgHS256:
+		sig = hmacSHA256(s.secret, signingInput)
+	case AlgRS256:
+		digest := sha256.Sum256([]byte(signingInput))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+		if err != nil {
+			return "", err
+		}
+	}
+	return signingInput + "." + encode(sig), nil
+}
+
+type Verifier struct {
+	alg       string
+	secret    []byte
+	publicKey *rsa.PublicKey
+	issuer    string
+	now       func() time.Time
+}
+
+func NewHMACVerifier(secret []byte, issuer string) *Verifier {
+	return &Verifier{alg: AlgHS256, secret: secret, issuer: issuer, now: time.Now}
+}
+
+func NewRSAVerifier(key *rsa.PublicKey, issuer string) *Verifier {
+	return &Verifier{alg: AlgRS256, publicKey: key, issuer: issuer, now: time.Now}
+}
+
+// LoadVerifier выбирает алгоритм по конфигурации: файл открытого ключа (RS256)
+// имеет приоритет над общим секретом (HS256).
+func LoadVerifier(secret, publicKeyFile, issuer string) (*Verifier, error) {
+	if publicKeyFile != "" {
+		data, err := os.ReadFile(publicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+		}
+		key, err := ParseRSAPublicKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return NewRSAVerifier(key, issuer), nil
+	}
+	if secret != "" {
+		return NewHMACVerifier([]byte(secret), issuer), nil
+	}
+	return nil, ErrNotConfigured
+}
+
+// ParseRSAPublicKey принимает PEM с PKIX ("PUBLIC KEY") или PKCS#1 ("RSA PUBLIC KEY").
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("JWT public key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWT public key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("JWT public key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// Verify проверяет подпись, алгоритм, срок действия и издателя токена.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var h header
+	if err := decodeJSON(parts[0], &h); err != nil {
+		return nil, err
+	}
+	// Алгоритм задается конфигурацией, а не токеном: иначе токен, подписанный
+	// HS256 открытым ключом RS256, прошел бы проверку.
+	if h.Alg != v.alg {
+		return nil, ErrUnsupportedAlg
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	signingInput := parts[0] + "." + parts[1]
+	switch v.alg {
+	case AlgHS256:
+		if !hmac.Equal(sig, hmacSHA256(v.secret, signingInput)) {
+			return nil, ErrInvalidSignature
+		}
+	case AlgRS256:
+		digest := sha256.Sum256([]byte(signingInput))
+		if rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, digest[:], sig) != nil {
+			return nil, ErrInvalidSignature
+		}
+	}
+
+	var claims Claims
+	if err := decodeJSON(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	now := v.now()
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(leeway)) {
+		return nil, ErrExpired
+	}
+	if claims.NotBefore != 0 && now.Add(leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, ErrNotYetValid
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return nil, ErrInvalidIssuer
+	}
+	return &claims, nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeJSON(part string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
+
+func hmacSHA256(secret []byte, input string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,135 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+)
+
+func testSecret(t *testing.T) []byte {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	require.NoError(t, err)
+	return secret
+}
+
+func validClaims() Claims {
+	return Claims{
+		Subject:   uuid.New().String(),
+		Role:      string(auth.RoleStudent),
+		Issuer:    "antiplague-test",
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestHS256_RoundTrip(t *testing.T) {
+	secret := testSecret(t)
+	claims := validClaims()
+
+	token, err := NewHMACSigner(secret).Sign(claims)
+	require.NoError(t, err)
+
+	got, err := NewHMACVerifier(secret, "antiplague-test").Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, claims, *got)
+
+	_, err = NewHMACVerifier(testSecret(t), "").Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestRS256_RoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	token, err := NewRSASigner(key).Sign(validClaims())
+	require.NoError(t, err)
+
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)})
+	pub, err := ParseRSAPublicKey(pemKey)
+	require.NoError(t, err)
+
+	_, err = NewRSAVerifier(pub, "antiplague-test").Verify(token)
+	assert.NoError(t, err)
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = NewRSAVerifier(&other.PublicKey, "").Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerify_RejectsAlgorithmSwitch(t *testing.T) {
+	secret := testSecret(t)
+	token, err := NewHMACSigner(secret).Sign(validClaims())
+	require.NoError(t, err)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = NewRSAVerifier(&key.PublicKey, "").Verify(token)
+	assert.ErrorIs(t, err, ErrUnsupportedAlg)
+
+	parts := strings.Split(token, ".")
+	unsigned := encode([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+	_, err = NewHMACVerifier(secret, "").Verify(unsigned)
+	assert.ErrorIs(t, err, ErrUnsupportedAlg)
+}
+
+func TestVerify_TimeAndIssuer(t *testing.T) {
+	secret := testSecret(t)
+	signer := NewHMACSigner(secret)
+	verifier := NewHMACVerifier(secret, "antiplague-test")
+
+	expired := validClaims()
+	expired.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+	token, _ := signer.Sign(expired)
+	_, err := verifier.Verify(token)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	noExp := validClaims()
+	noExp.ExpiresAt = 0
+	token, _ = signer.Sign(noExp)
+	_, err = verifier.Verify(token)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	future := validClaims()
+	future.NotBefore = time.Now().Add(time.Hour).Unix()
+	token, _ = signer.Sign(future)
+	_, err = verifier.Verify(token)
+	assert.ErrorIs(t, err, ErrNotYetValid)
+
+	foreign := validClaims()
+	foreign.Issuer = "someone-else"
+	token, _ = signer.Sign(foreign)
+	_, err = verifier.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidIssuer)
+
+	_, err = verifier.Verify("not-a-token")
+	assert.ErrorIs(t, err, ErrMalformed)
+}
+
+func TestClaims_Principal(t *testing.T) {
+	assignment := uuid.New()
+	claims := validClaims()
+	claims.Role = string(auth.RoleTeacher)
+	claims.Assignments = []string{assignment.String()}
+
+	p, err := claims.Principal()
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleTeacher, p.Role)
+	assert.True(t, p.TeachesAssignment(assignment))
+	assert.False(t, p.TeachesAssignment(uuid.New()))
+
+	claims.Role = "superuser"
+	_, err = claims.Principal()
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+}
@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"context"
+	"log"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/appeal"
+)
+
+// LogNotifier пишет уведомления в журнал сервиса. Почтовой или иной доставки в системе
+// нет; ее можно подключить, реализовав appeal.Notifier.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (LogNotifier) Notify(_ context.Context, n appeal.Notification) error {
+	log.Printf("Notification %s: appeal=%s work=%s assignment=%s student=%s status=%s deadline=%s",
+		n.Kind, n.AppealID, n.WorkID, n.AssignmentID, n.StudentID, n.Status, n.Deadline.UTC().Format("2006-01-02T15:04:05Z"))
+	return nil
+}
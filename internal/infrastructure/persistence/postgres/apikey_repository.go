@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/apikey"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+// lastUsedGranularity: время последнего использования обновляется не чаще раза в минуту,
+// чтобы активный ключ не создавал запись в базу на каждый запрос.
+const lastUsedGranularity = time.Minute
+
+const apiKeyColumns = "id, name, prefix, key_hash, scopes, assignment_id, created_by, created_at, expires_at, last_used_at, revoked_at"
+
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+type apiKeyDB struct {
+	ID           uuid.UUID      `db:"id"`
+	Name         string         `db:"name"`
+	Prefix       string         `db:"prefix"`
+	Hash         string         `db:"key_hash"`
+	Scopes       pq.StringArray `db:"scopes"`
+	AssignmentID *uuid.UUID     `db:"assignment_id"`
+	CreatedBy    uuid.UUID      `db:"created_by"`
+	CreatedAt    time.Time      `db:"created_at"`
+	ExpiresAt    *time.Time     `db:"expires_at"`
+	LastUsedAt   *time.Time     `db:"last_used_at"`
+	RevokedAt    *time.Time     `db:"revoked_at"`
+}
+
+func (m apiKeyDB) toDomain() *apikey.APIKey {
+	scopes := make([]auth.Scope, len(m.Scopes))
+	for i, s := range m.Scopes {
+		scopes[i] = auth.Scope(s)
+	}
+	return &apikey.APIKey{
+		ID:           m.ID,
+		Name:         m.Name,
+		Prefix:       m.Prefix,
+		Hash:         m.Hash,
+		Scopes:       scopes,
+		AssignmentID: m.AssignmentID,
+		CreatedBy:    m.CreatedBy,
+		CreatedAt:    m.CreatedAt,
+		ExpiresAt:    m.ExpiresAt,
+		LastUsedAt:   m.LastUsedAt,
+		RevokedAt:    m.RevokedAt,
+	}
+}
+
+func (r *APIKeyRepository) Save(ctx context.Context, k *apikey.APIKey) error {
+	scopes := make(pq.StringArray, len(k.Scopes))
+	for i, s := range k.Scopes {
+		scopes[i] = string(s)
+	}
+	model := apiKeyDB{
+		ID:           k.ID,
+		Name:         k.Name,
+		Prefix:       k.Prefix,
+		Hash:         k.Hash,
+		Scopes:       scopes,
+		AssignmentID: k.AssignmentID,
+		CreatedBy:    k.CreatedBy,
+		CreatedAt:    k.CreatedAt,
+		ExpiresAt:    k.ExpiresAt,
+	}
+
+	query := `
+		INSERT INTO api_keys (id, name, prefix, key_hash, scopes, assignment_id, created_by, created_at, expires_at)
+		VALUES (:id, :name, :prefix, :key_hash, :scopes, :assignment_id, :created_by, :created_at, :expires_at)
+	`
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		return fmt.Errorf("failed to save api key: %w", err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*apikey.APIKey, error) {
+	return r.getOne(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE id = $1", id)
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*apikey.APIKey, error) {
+	return r.getOne(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = $1", hash)
+}
+
+func (r *APIKeyRepository) getOne(ctx context.Context, query string, arg interface{}) (*apikey.APIKey, error) {
+	var model apiKeyDB
+	if err := r.db.GetContext(ctx, &model, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return model.toDomain(), nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]*apikey.APIKey, error) {
+	var models []apiKeyDB
+	if err := r.db.SelectContext(ctx, &models, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY created_at DESC"); err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	keys := make([]*apikey.APIKey, len(models))
+	for i, m := range models {
+		keys[i] = m.toDomain()
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $2) WHERE id = $1", id, at)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return shared.ErrNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3)
+	`, id, at, at.Add(-lastUsedGranularity))
+	if err != nil {
+		return fmt.Errorf("failed to update api key usage: %w", err)
+	}
+	return nil
+}
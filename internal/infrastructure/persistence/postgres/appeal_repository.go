@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/appeal"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+const appealColumns = `id, report_id, work_id, assignment_id, student_id, statement, status, reviewer_id,
+	resolution, resolved_by, submitted_at, review_deadline, resolved_at, overdue_notified_at, updated_at`
+
+type AppealRepository struct {
+	db *sqlx.DB
+}
+
+func NewAppealRepository(db *sqlx.DB) *AppealRepository {
+	return &AppealRepository{db: db}
+}
+
+type appealDB struct {
+	ID                uuid.UUID  `db:"id"`
+	ReportID          uuid.UUID  `db:"report_id"`
+	WorkID            uuid.UUID  `db:"work_id"`
+	AssignmentID      uuid.UUID  `db:"assignment_id"`
+	StudentID         uuid.UUID  `db:"student_id"`
+	Statement         string     `db:"statement"`
+	Status            string     `db:"status"`
+	ReviewerID        *uuid.UUID `db:"reviewer_id"`
+	Resolution        string     `db:"resolution"`
+	ResolvedBy        *uuid.UUID `db:"resolved_by"`
+	SubmittedAt       time.Time  `db:"submitted_at"`
+	ReviewDeadline    time.Time  `db:"review_deadline"`
+	ResolvedAt        *time.Time `db:"resolved_at"`
+	OverdueNotifiedAt *time.Time `db:"overdue_notified_at"`
+	UpdatedAt         time.Time  `db:"updated_at"`
+}
+
+func (m appealDB) toDomain() *appeal.Appeal {
+	return &appeal.Appeal{
+		ID:                m.ID,
+		ReportID:          m.ReportID,
+		WorkID:            m.WorkID,
+		AssignmentID:      m.AssignmentID,
+		StudentID:         m.StudentID,
+		Statement:         m.Statement,
+		Status:            appeal.Status(m.Status),
+		ReviewerID:        m.ReviewerID,
+		Resolution:        m.Resolution,
+		ResolvedBy:        m.ResolvedBy,
+		SubmittedAt:       m.SubmittedAt,
+		ReviewDeadline:    m.ReviewDeadline,
+		ResolvedAt:        m.ResolvedAt,
+		OverdueNotifiedAt: m.OverdueNotifiedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
+
+type evidenceDB struct {
+	ID          uuid.UUID `db:"id"`
+	AppealID    uuid.UUID `db:"appeal_id"`
+	FileName    string    `db:"file_name"`
+	StoragePath string    `db:"storage_path"`
+	MimeType    string    `db:"mime_type"`
+	Size        int64     `db:"size"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (r *AppealRepository) Create(ctx context.Context, a *appeal.Appeal) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO appeals (`+appealColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`, a.ID, a.ReportID, a.WorkID, a.AssignmentID, a.StudentID, a.Statement, string(a.Status), a.ReviewerID,
+		a.Resolution, a.ResolvedBy, a.SubmittedAt, a.ReviewDeadline, a.ResolvedAt, a.OverdueNotifiedAt, a.UpdatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return appeal.ErrAlreadyOpen
+		}
+		return fmt.Errorf("failed to create appeal: %w", err)
+	}
+
+	for _, e := range a.Evidence {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO appeal_evidence (id, appeal_id, file_name, storage_path, mime_type, size, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, e.ID, a.ID, e.FileName, e.StoragePath, e.MimeType, e.Size, e.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save appeal evidence: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *AppealRepository) Update(ctx context.Context, a *appeal.Appeal) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE appeals SET status = $2, reviewer_id = $3, resolution = $4, resolved_by = $5,
+			resolved_at = $6, overdue_notified_at = $7, updated_at = $8
+		WHERE id = $1
+	`, a.ID, string(a.Status), a.ReviewerID, a.Resolution, a.ResolvedBy, a.ResolvedAt, a.OverdueNotifiedAt, a.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update appeal: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return shared.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AppealRepository) GetByID(ctx context.Context, id uuid.UUID) (*appeal.Appeal, error) {
+	var model appealDB
+	err := r.db.GetContext(ctx, &model, "SELECT "+appealColumns+" FROM appeals WHERE id = $1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get appeal: %w", err)
+	}
+	a := model.toDomain()
+
+	var files []evidenceDB
+	err = r.db.SelectContext(ctx, &files, `
+		SELECT id, appeal_id, file_name, storage_path, mime_type, size, created_at
+		FROM appeal_evidence WHERE appeal_id = $1 ORDER BY created_at, id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appeal evidence: %w", err)
+	}
+	for _, f := range files {
+		a.Evidence = append(a.Evidence, appeal.Evidence{
+			ID:          f.ID,
+			AppealID:    f.AppealID,
+			FileName:    f.FileName,
+			StoragePath: f.StoragePath,
+			MimeType:    f.MimeType,
+			Size:        f.Size,
+			CreatedAt:   f.CreatedAt,
+		})
+	}
+	return a, nil
+}
+
+func (r *AppealRepository) ListByWork(ctx context.Context, workID uuid.UUID) ([]*appeal.Appeal, error) {
+	return r.list(ctx, "SELECT "+appealColumns+" FROM appeals WHERE work_id = $1 ORDER BY submitted_at DESC", workID)
+}
+
+func (r *AppealRepository) HasOpen(ctx context.Context, reportID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, `
+		SELECT EXISTS (SELECT 1 FROM appeals WHERE report_id = $1 AND status IN ($2, $3))
+	`, reportID, string(appeal.StatusSubmitted), string(appeal.StatusUnderReview))
+	if err != nil {
+		return false, fmt.Errorf("failed to check open appeals: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *AppealRepository) ListOverdue(ctx context.Context, now time.Time) ([]*appeal.Appeal, error) {
+	return r.list(ctx, `
+		SELECT `+appealColumns+` FROM appeals
+		WHERE status IN ($1, $2) AND review_deadline < $3 AND overdue_notified_at IS NULL
+		ORDER BY review_deadline
+	`, string(appeal.StatusSubmitted), string(appeal.StatusUnderReview), now)
+}
+
+func (r *AppealRepository) list(ctx context.Context, query string, args ...interface{}) ([]*appeal.Appeal, error) {
+	var models []appealDB
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list appeals: %w", err)
+	}
+	appeals := make([]*appeal.Appeal, 0, len(models))
+	for _, m := range models {
+		appeals = append(appeals, m.toDomain())
+	}
+	return appeals, nil
+}
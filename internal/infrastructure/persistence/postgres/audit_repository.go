@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/audit"
+)
+
+// auditAppendLockID сериализует добавление записей: каждая новая запись ссылается на последнюю.
+const auditAppendLockID = 7_041_912
+
+const auditColumns = "seq, actor_id, actor_type, actor_role, action, resource_type, resource_id, request_id, details, created_at, prev_hash, hash"
+
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+type auditDB struct {
+	Seq          int64     `db:"seq"`
+	ActorID      uuid.UUID `db:"actor_id"`
+	ActorType    string    `db:"actor_type"`
+	ActorRole    string    `db:"actor_role"`
+	Action       string    `db:"action"`
+	ResourceType string    `db:"resource_type"`
+	ResourceID   string    `db:"resource_id"`
+	RequestID    string    `db:"request_id"`
+	Details      []byte    `db:"details"`
+	CreatedAt    time.Time `db:"created_at"`
+	PrevHash     string    `db:"prev_hash"`
+	Hash         string    `db:"hash"`
+}
+
+func (m auditDB) toDomain() (*audit.Entry, error) {
+	var details map[string]string
+	if err := json.Unmarshal(m.Details, &details); err != nil {
+		return nil, fmt.Errorf("failed to decode audit details of entry %d: %w", m.Seq, err)
+	}
+	return &audit.Entry{
+		Seq:          m.Seq,
+		ActorID:      m.ActorID,
+		ActorType:    m.ActorType,
+		ActorRole:    m.ActorRole,
+		Action:       audit.Action(m.Action),
+		ResourceType: m.ResourceType,
+		ResourceID:   m.ResourceID,
+		RequestID:    m.RequestID,
+		Details:      details,
+		CreatedAt:    m.CreatedAt,
+		PrevHash:     m.PrevHash,
+		Hash:         m.Hash,
+	}, nil
+}
+
+func (r *AuditRepository) Append(ctx context.Context, e *audit.Entry) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", auditAppendLockID); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+
+	var last *audit.Entry
+	var model auditDB
+	err = tx.GetContext(ctx, &model, "SELECT "+auditColumns+" FROM audit_log ORDER BY seq DESC LIMIT 1")
+	switch {
+	case err == nil:
+		if last, err = model.toDomain(); err != nil {
+			return err
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("failed to read last audit entry: %w", err)
+	}
+
+	audit.Link(e, last)
+
+	details := []byte("{}")
+	if len(e.Details) > 0 {
+		details, _ = json.Marshal(e.Details)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_log (`+auditColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, e.Seq, e.ActorID, e.ActorType, e.ActorRole, string(e.Action), e.ResourceType, e.ResourceID,
+		e.RequestID, details, e.CreatedAt, e.PrevHash, e.Hash)
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *AuditRepository) Query(ctx context.Context, f audit.Filter) ([]*audit.Entry, error) {
+	var where []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if f.ActorID != nil {
+		add("actor_id = $%d", *f.ActorID)
+	}
+	if f.Action != "" {
+		add("action = $%d", string(f.Action))
+	}
+	if f.ResourceType != "" {
+		add("resource_type = $%d", f.ResourceType)
+	}
+	if f.ResourceID != "" {
+		add("resource_id = $%d", f.ResourceID)
+	}
+	if f.From != nil {
+		add("created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("created_at < $%d", *f.To)
+	}
+
+	query := "SELECT " + auditColumns + " FROM audit_log"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, f.Limit, f.Offset)
+	query += fmt.Sprintf(" ORDER BY seq DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	return r.selectEntries(ctx, query, args...)
+}
+
+func (r *AuditRepository) After(ctx context.Context, seq int64, limit int) ([]*audit.Entry, error) {
+	return r.selectEntries(ctx, "SELECT "+auditColumns+" FROM audit_log WHERE seq > $1 ORDER BY seq LIMIT $2", seq, limit)
+}
+
+func (r *AuditRepository) selectEntries(ctx context.Context, query string, args ...interface{}) ([]*audit.Entry, error) {
+	var models []auditDB
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+
+	entries := make([]*audit.Entry, len(models))
+	for i, m := range models {
+		e, err := m.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = e
+	}
+	return entries, nil
+}
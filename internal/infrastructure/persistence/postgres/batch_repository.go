@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/batch"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+const batchColumns = `id, assignment_id, created_by, status, checked, error, created_at, started_at, finished_at`
+
+type BatchRepository struct {
+	db *sqlx.DB
+}
+
+func NewBatchRepository(db *sqlx.DB) *BatchRepository {
+	return &BatchRepository{db: db}
+}
+
+type batchDB struct {
+	ID           uuid.UUID  `db:"id"`
+	AssignmentID uuid.UUID  `db:"assignment_id"`
+	CreatedBy    uuid.UUID  `db:"created_by"`
+	Status       string     `db:"status"`
+	Checked      int        `db:"checked"`
+	Error        string     `db:"error"`
+	CreatedAt    time.Time  `db:"created_at"`
+	StartedAt    *time.Time `db:"started_at"`
+	FinishedAt   *time.Time `db:"finished_at"`
+}
+
+func (m batchDB) toDomain() *batch.Batch {
+	return &batch.Batch{
+		ID:           m.ID,
+		AssignmentID: m.AssignmentID,
+		CreatedBy:    m.CreatedBy,
+		Status:       batch.Status(m.Status),
+		Checked:      m.Checked,
+		Error:        m.Error,
+		CreatedAt:    m.CreatedAt,
+		StartedAt:    m.StartedAt,
+		FinishedAt:   m.FinishedAt,
+	}
+}
+
+type batchItemDB struct {
+	FileName  string     `db:"file_name"`
+	StudentID uuid.UUID  `db:"student_id"`
+	WorkID    *uuid.UUID `db:"work_id"`
+	Error     string     `db:"error"`
+}
+
+func (r *BatchRepository) Create(ctx context.Context, b *batch.Batch) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Пакет доступен обработчику, как только встанет в очередь: аренда истекла в момент создания.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO batches (`+batchColumns+`, lease_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $7)
+	`, b.ID, b.AssignmentID, b.CreatedBy, string(b.Status), b.Checked, b.Error, b.CreatedAt, b.StartedAt, b.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create batch: %w", err)
+	}
+
+	for i, it := range b.Items {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO batch_items (batch_id, position, file_name, student_id, work_id, error)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, b.ID, i, it.FileName, it.StudentID, it.WorkID, it.Error)
+		if err != nil {
+			return fmt.Errorf("failed to save batch item: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *BatchRepository) SaveItem(ctx context.Context, batchID uuid.UUID, position int, it batch.Item) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE batch_items SET work_id = $3, error = $4
+		WHERE batch_id = $1 AND position = $2
+	`, batchID, position, it.WorkID, it.Error)
+	if err != nil {
+		return fmt.Errorf("failed to save batch item: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return shared.ErrNotFound
+	}
+	return nil
+}
+
+func (r *BatchRepository) Update(ctx context.Context, b *batch.Batch) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE batches SET status = $2, checked = $3, error = $4, started_at = $5, finished_at = $6
+		WHERE id = $1
+	`, b.ID, string(b.Status), b.Checked, b.Error, b.StartedAt, b.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update batch: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return shared.ErrNotFound
+	}
+	return nil
+}
+
+func (r *BatchRepository) GetByID(ctx context.Context, id uuid.UUID) (*batch.Batch, error) {
+	var model batchDB
+	err := r.db.GetContext(ctx, &model, "SELECT "+batchColumns+" FROM batches WHERE id = $1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get batch: %w", err)
+	}
+	return r.withItems(ctx, model.toDomain())
+}
+
+func (r *BatchRepository) Claim(ctx context.Context, now time.Time, lease time.Duration) (*batch.Batch, error) {
+	var model batchDB
+	// SKIP LOCKED позволяет нескольким экземплярам разбирать очередь, не дожидаясь друг друга.
+	err := r.db.GetContext(ctx, &model, `
+		UPDATE batches SET lease_until = $2
+		WHERE id = (
+			SELECT id FROM batches
+			WHERE status IN ($3, $4) AND lease_until <= $1
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+batchColumns,
+		now, now.Add(lease), string(batch.StatusQueued), string(batch.StatusProcessing))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to claim batch: %w", err)
+	}
+	return r.withItems(ctx, model.toDomain())
+}
+
+func (r *BatchRepository) withItems(ctx context.Context, b *batch.Batch) (*batch.Batch, error) {
+	var items []batchItemDB
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT file_name, student_id, work_id, error FROM batch_items
+		WHERE batch_id = $1 ORDER BY position
+	`, b.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list batch items: %w", err)
+	}
+	for _, it := range items {
+		b.Items = append(b.Items, batch.Item{FileName: it.FileName, StudentID: it.StudentID, WorkID: it.WorkID, Error: it.Error})
+	}
+	return b, nil
+}
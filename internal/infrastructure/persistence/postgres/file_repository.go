@@ -94,3 +94,57 @@ func (r *FileRepository) GetByHash(ctx context.Context, hash string) (*file.File
 		CreatedAt:    model.CreatedAt,
 	}, nil
 }
+
+// List возвращает метаданные всех файлов, старые первыми.
+func (r *FileRepository) List(ctx context.Context) ([]*file.File, error) {
+	var models []fileDB
+	err := r.db.SelectContext(ctx, &models, "SELECT id, filename, storage_path, file_size, mime_type, content_hash, created_at FROM files ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*file.File, len(models))
+	for i, m := range models {
+		files[i] = &file.File{
+			ID:           m.ID,
+			OriginalName: m.OriginalName,
+			StoragePath:  m.StoragePath,
+			Size:         m.Size,
+			MimeType:     m.MimeType,
+			Hash:         m.Hash,
+			CreatedAt:    m.CreatedAt,
+		}
+	}
+	return files, nil
+}
+
+func (r *FileRepository) UpdateHash(ctx context.Context, id uuid.UUID, hash string) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE files SET content_hash = $2 WHERE id = $1", id, hash)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return shared.ErrNotFound
+	}
+	return nil
+}
+
+// StoragePaths возвращает все пути хранилища, на которые ссылается база: файлы работ
+// и приложения к апелляциям.
+func (r *FileRepository) StoragePaths(ctx context.Context) (map[string]struct{}, error) {
+	var paths []string
+	err := r.db.SelectContext(ctx, &paths, `
+		SELECT storage_path FROM files
+		UNION
+		SELECT storage_path FROM appeal_evidence
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		set[p] = struct{}{}
+	}
+	return set, nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID — ключ advisory-блокировки, чтобы сервисы, стартующие одновременно,
+// не применяли одну и ту же миграцию дважды.
+const migrationLockID = 7_041_911
+
+// Migrate применяет еще не примененные миграции из migrations/ в порядке имен файлов
+// и возвращает их имена. Базовые таблицы создает scripts/init.sql при инициализации контейнера.
+func Migrate(ctx context.Context, db *sqlx.DB) ([]string, error) {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return nil, fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var done []string
+	if err := conn.SelectContext(ctx, &done, "SELECT version FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	isDone := make(map[string]bool, len(done))
+	for _, v := range done {
+		isDone[v] = true
+	}
+
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	var applied []string
+	for _, name := range names {
+		version := name[len("migrations/"):]
+		if isDone[version] {
+			continue
+		}
+		script, err := migrationFiles.ReadFile(name)
+		if err != nil {
+			return applied, err
+		}
+
+		tx, err := conn.BeginTxx(ctx, nil)
+		if err != nil {
+			return applied, err
+		}
+		if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+			tx.Rollback()
+			return applied, fmt.Errorf("migration %s failed: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+			tx.Rollback()
+			return applied, fmt.Errorf("failed to record migration %s: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return applied, err
+		}
+		applied = append(applied, version)
+	}
+	return applied, nil
+}
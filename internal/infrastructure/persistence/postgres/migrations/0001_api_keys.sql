@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id            UUID PRIMARY KEY,
+    name          TEXT        NOT NULL,
+    prefix        TEXT        NOT NULL,
+    key_hash      TEXT        NOT NULL UNIQUE,
+    scopes        TEXT[]      NOT NULL,
+    assignment_id UUID,
+    created_by    UUID        NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL,
+    expires_at    TIMESTAMPTZ,
+    last_used_at  TIMESTAMPTZ,
+    revoked_at    TIMESTAMPTZ
+);
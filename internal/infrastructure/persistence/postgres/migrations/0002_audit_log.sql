@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    seq           BIGINT PRIMARY KEY,
+    actor_id      UUID        NOT NULL,
+    actor_type    TEXT        NOT NULL,
+    actor_role    TEXT        NOT NULL,
+    action        TEXT        NOT NULL,
+    resource_type TEXT        NOT NULL,
+    resource_id   TEXT        NOT NULL,
+    request_id    TEXT        NOT NULL,
+    details       JSONB       NOT NULL DEFAULT '{}',
+    created_at    TIMESTAMPTZ NOT NULL,
+    prev_hash     TEXT        NOT NULL,
+    hash          TEXT        NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log (resource_type, resource_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+
+-- Журнал только на добавление: изменение и удаление запрещены на уровне базы.
+-- Обход триггера владельцем таблицы обнаруживается проверкой цепочки хешей.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update_delete
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
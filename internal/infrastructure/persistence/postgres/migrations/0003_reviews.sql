@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS reviews (
+    report_id       UUID PRIMARY KEY,
+    work_id         UUID        NOT NULL,
+    assignment_id   UUID        NOT NULL,
+    assignee_id     UUID,
+    verdict         TEXT,
+    verdict_by      UUID,
+    verdict_comment TEXT        NOT NULL DEFAULT '',
+    decided_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_assignment ON reviews (assignment_id);
+
+CREATE TABLE IF NOT EXISTS review_events (
+    id          UUID PRIMARY KEY,
+    report_id   UUID        NOT NULL REFERENCES reviews (report_id),
+    actor_id    UUID        NOT NULL,
+    kind        TEXT        NOT NULL,
+    assignee_id UUID,
+    verdict     TEXT,
+    comment     TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_events_report ON review_events (report_id, created_at);
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS appeals (
+    id                  UUID PRIMARY KEY,
+    report_id           UUID        NOT NULL,
+    work_id             UUID        NOT NULL,
+    assignment_id       UUID        NOT NULL,
+    student_id          UUID        NOT NULL,
+    statement           TEXT        NOT NULL,
+    status              TEXT        NOT NULL,
+    reviewer_id         UUID,
+    resolution          TEXT        NOT NULL DEFAULT '',
+    resolved_by         UUID,
+    submitted_at        TIMESTAMPTZ NOT NULL,
+    review_deadline     TIMESTAMPTZ NOT NULL,
+    resolved_at         TIMESTAMPTZ,
+    overdue_notified_at TIMESTAMPTZ,
+    updated_at          TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_appeals_work ON appeals (work_id, submitted_at DESC);
+
+-- Не больше одной открытой апелляции на отчет.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appeals_open_report ON appeals (report_id)
+    WHERE status IN ('submitted', 'under_review');
+
+CREATE TABLE IF NOT EXISTS appeal_evidence (
+    id           UUID PRIMARY KEY,
+    appeal_id    UUID        NOT NULL REFERENCES appeals (id),
+    file_name    TEXT        NOT NULL,
+    storage_path TEXT        NOT NULL,
+    mime_type    TEXT        NOT NULL,
+    size         BIGINT      NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_appeal_evidence_appeal ON appeal_evidence (appeal_id);
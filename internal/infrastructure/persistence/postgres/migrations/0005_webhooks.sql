@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id            UUID PRIMARY KEY,
+    name          TEXT        NOT NULL,
+    url           TEXT        NOT NULL,
+    events        TEXT[]      NOT NULL,
+    secret        TEXT        NOT NULL,
+    assignment_id UUID,
+    created_by    UUID        NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL
+);
+
+-- Журнал доставок переживает удаление подписки, поэтому внешнего ключа нет.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id               UUID PRIMARY KEY,
+    subscription_id  UUID        NOT NULL,
+    event_id         UUID        NOT NULL,
+    event_type       TEXT        NOT NULL,
+    payload          BYTEA       NOT NULL,
+    status           TEXT        NOT NULL,
+    attempts         INT         NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL,
+    last_status_code INT         NOT NULL DEFAULT 0,
+    last_error       TEXT        NOT NULL DEFAULT '',
+    replay_of        UUID,
+    created_at       TIMESTAMPTZ NOT NULL,
+    delivered_at     TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, created_at DESC);
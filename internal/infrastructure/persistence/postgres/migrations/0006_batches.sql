@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS batches (
+    id            UUID PRIMARY KEY,
+    assignment_id UUID        NOT NULL,
+    created_by    UUID        NOT NULL,
+    status        TEXT        NOT NULL,
+    checked       INT         NOT NULL DEFAULT 0,
+    error         TEXT        NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL,
+    started_at    TIMESTAMPTZ,
+    finished_at   TIMESTAMPTZ,
+    -- lease_until — до какого момента пакет закреплен за обработчиком.
+    lease_until   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_batches_pending ON batches (lease_until)
+    WHERE status IN ('queued', 'processing');
+
+CREATE TABLE IF NOT EXISTS batch_items (
+    batch_id   UUID NOT NULL REFERENCES batches (id),
+    position   INT  NOT NULL,
+    file_name  TEXT NOT NULL,
+    student_id UUID NOT NULL,
+    work_id    UUID,
+    error      TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (batch_id, position)
+);
@@ -0,0 +1,18 @@
+-- Ревизии отчетов: номер по работе, политика анализа и причина пересчета.
+ALTER TABLE plagiarism_reports
+    ADD COLUMN IF NOT EXISTS revision INT,
+    ADD COLUMN IF NOT EXISTS policy   JSONB,
+    ADD COLUMN IF NOT EXISTS reason   TEXT NOT NULL DEFAULT 'submission';
+
+-- Уже накопленные отчеты нумеруются по времени создания; их политика неизвестна.
+UPDATE plagiarism_reports pr
+SET revision = numbered.revision
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY work_id ORDER BY created_at) AS revision
+    FROM plagiarism_reports
+) numbered
+WHERE pr.id = numbered.id AND pr.revision IS NULL;
+
+ALTER TABLE plagiarism_reports ALTER COLUMN revision SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_plagiarism_reports_work_revision ON plagiarism_reports (work_id, revision);
@@ -0,0 +1,3 @@
+-- Все совпавшие работы с наибольшими оценками, а не только лучшая: нужны для сравнения ревизий.
+ALTER TABLE plagiarism_reports
+    ADD COLUMN IF NOT EXISTS matches JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,3 @@
+-- Решение правил политики: уровень и сработавшие правила. NULL у отчетов до появления правил.
+ALTER TABLE plagiarism_reports
+    ADD COLUMN IF NOT EXISTS decision JSONB;
@@ -0,0 +1,18 @@
+-- Проверка относится к работе, а не к ревизии отчета: повторный анализ добавляет ревизию,
+-- и вердикт преподавателя должен оставаться в силе. Если у работы уже несколько проверок,
+-- остается последняя измененная, а история остальных переносится в нее.
+CREATE TEMP TABLE review_keep ON COMMIT DROP AS
+SELECT report_id,
+       first_value(report_id) OVER (PARTITION BY work_id ORDER BY updated_at DESC, report_id) AS kept
+FROM reviews;
+
+UPDATE review_events e
+SET report_id = k.kept
+FROM review_keep k
+WHERE e.report_id = k.report_id AND k.report_id <> k.kept;
+
+DELETE FROM reviews r
+USING review_keep k
+WHERE r.report_id = k.report_id AND k.report_id <> k.kept;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_work ON reviews (work_id);
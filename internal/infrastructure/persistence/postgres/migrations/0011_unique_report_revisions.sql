@@ -0,0 +1,12 @@
+-- Номер ревизии уникален в пределах работы. Совпавшие номера, которые могли выдать
+-- параллельные сохранения, перенумеровываются по порядку создания.
+UPDATE plagiarism_reports pr
+SET revision = numbered.revision
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY work_id ORDER BY revision, created_at, id) AS revision
+    FROM plagiarism_reports
+) numbered
+WHERE pr.id = numbered.id AND pr.revision <> numbered.revision;
+
+DROP INDEX IF EXISTS idx_plagiarism_reports_work_revision;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_plagiarism_reports_work_revision ON plagiarism_reports (work_id, revision);
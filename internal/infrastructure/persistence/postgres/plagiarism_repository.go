@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,9 @@ import (
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
 )
 
+// reportRevisionLockID — первый ключ advisory-блокировки ревизий отчетов; второй — хеш работы.
+const reportRevisionLockID = 7_041_913
+
 type PlagiarismRepository struct {
 	db *sqlx.DB
 }
@@ -23,20 +27,110 @@ func NewPlagiarismRepository(db *sqlx.DB) *PlagiarismRepository {
 }
 
 type reportDB struct {
-	ID            uuid.UUID       `db:"id"`
-	WorkID        uuid.UUID       `db:"work_id"`
-	IsPlagiarized bool            `db:"is_plagiarized"`
-	Score         float64         `db:"similarity_score"`
-	MatchedWorkID *uuid.UUID      `db:"matched_with_work_id"`
-	DetailsJSON   json.RawMessage `db:"analysis_details"`
-	CreatedAt     time.Time       `db:"created_at"`
+	ID            uuid.UUID        `db:"id"`
+	WorkID        uuid.UUID        `db:"work_id"`
+	IsPlagiarized bool             `db:"is_plagiarized"`
+	Score         float64          `db:"similarity_score"`
+	MatchedWorkID *uuid.UUID       `db:"matched_with_work_id"`
+	MatchesJSON   json.RawMessage  `db:"matches"`
+	DetailsJSON   json.RawMessage  `db:"analysis_details"`
+	Revision      int              `db:"revision"`
+	PolicyJSON    *json.RawMessage `db:"policy"`
+	DecisionJSON  *json.RawMessage `db:"decision"`
+	Reason        string           `db:"reason"`
+	CreatedAt     time.Time        `db:"created_at"`
 }
 
+// Save добавляет ревизию отчета; номер ревизии назначается следующим после последнего
+// по работе и записывается в report.Revision.
 func (r *PlagiarismRepository) Save(ctx context.Context, report *plagiarism.Report) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockReportRevisions(ctx, tx, report.WorkID); err != nil {
+		return err
+	}
+	if err := insertReport(ctx, tx, report); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PlagiarismRepository) Amend(ctx context.Context, workID uuid.UUID, change func(*plagiarism.Report) (*plagiarism.Report, error)) (*plagiarism.Report, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockReportRevisions(ctx, tx, workID); err != nil {
+		return nil, err
+	}
+	var latest *plagiarism.Report
+	var model reportDB
+	err = tx.GetContext(ctx, &model, "SELECT * FROM plagiarism_reports WHERE work_id = $1 ORDER BY revision DESC LIMIT 1", workID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return nil, err
+	default:
+		if latest, err = model.toDomainEntity(); err != nil {
+			return nil, err
+		}
+	}
+
+	next, err := change(latest)
+	if err != nil || next == nil {
+		return nil, err
+	}
+	if err := insertReport(ctx, tx, next); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// lockReportRevisions блокирует ревизии отчета работы до конца транзакции: параллельные
+// сохранения иначе прочитали бы один и тот же MAX(revision) или одну и ту же последнюю ревизию.
+func lockReportRevisions(ctx context.Context, tx *sqlx.Tx, workID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1, hashtext($2))", reportRevisionLockID, workID.String()); err != nil {
+		return fmt.Errorf("failed to lock report revisions of work %s: %w", workID, err)
+	}
+	return nil
+}
+
+// insertReport вставляет ревизию под блокировкой lockReportRevisions и записывает ее номер
+// в report.Revision.
+func insertReport(ctx context.Context, tx *sqlx.Tx, report *plagiarism.Report) error {
 	detailsBytes, err := json.Marshal(report.Details)
 	if err != nil {
 		return err
 	}
+	matches := report.Matches
+	if matches == nil {
+		matches = []plagiarism.Match{}
+	}
+	matchesBytes, err := json.Marshal(matches)
+	if err != nil {
+		return err
+	}
+	var policyJSON *json.RawMessage
+	if report.Policy != nil {
+		if policyJSON, err = nullableJSON(report.Policy); err != nil {
+			return err
+		}
+	}
+	var decisionJSON *json.RawMessage
+	if report.Decision != nil {
+		if decisionJSON, err = nullableJSON(report.Decision); err != nil {
+			return err
+		}
+	}
 
 	model := reportDB{
 		ID:            report.ID,
@@ -44,27 +138,40 @@ func (r *PlagiarismRepository) Save(ctx context.Context, report *plagiarism.Repo
 		IsPlagiarized: report.IsPlagiarized,
 		Score:         report.Score,
 		MatchedWorkID: report.MatchedWorkID,
+		MatchesJSON:   matchesBytes,
 		DetailsJSON:   detailsBytes,
+		PolicyJSON:    policyJSON,
+		DecisionJSON:  decisionJSON,
+		Reason:        report.Reason,
 		CreatedAt:     report.CreatedAt,
 	}
 
+	err = tx.GetContext(ctx, &model.Revision,
+		"SELECT COALESCE(MAX(revision), 0) + 1 FROM plagiarism_reports WHERE work_id = $1", report.WorkID)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO plagiarism_reports (
 			id, work_id, is_plagiarized, similarity_score, 
-			matched_with_work_id, analysis_details, created_at
+			matched_with_work_id, matches, analysis_details, revision, policy, decision, reason, created_at
 		) VALUES (
 			:id, :work_id, :is_plagiarized, :similarity_score, 
-			:matched_with_work_id, :analysis_details, :created_at
+			:matched_with_work_id, :matches, :analysis_details, :revision,
+			:policy, :decision, :reason, :created_at
 		)
 	`
-
-	_, err = r.db.NamedExecContext(ctx, query, model)
-	return err
+	if _, err := tx.NamedExecContext(ctx, query, model); err != nil {
+		return err
+	}
+	report.Revision = model.Revision
+	return nil
 }
 
 func (r *PlagiarismRepository) GetByWorkID(ctx context.Context, workID uuid.UUID) (*plagiarism.Report, error) {
 	var model reportDB
-	query := "SELECT * FROM plagiarism_reports WHERE work_id = $1 ORDER BY created_at DESC LIMIT 1"
+	query := "SELECT * FROM plagiarism_reports WHERE work_id = $1 ORDER BY revision DESC LIMIT 1"
 
 	err := r.db.GetContext(ctx, &model, query, workID)
 	if err != nil {
@@ -74,20 +181,175 @@ func (r *PlagiarismRepository) GetByWorkID(ctx context.Context, workID uuid.UUID
 		return nil, err
 	}
 
+	return model.toDomainEntity()
+}
+
+func (r *PlagiarismRepository) GetByID(ctx context.Context, id uuid.UUID) (*plagiarism.Report, error) {
+	var model reportDB
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM plagiarism_reports WHERE id = $1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return model.toDomainEntity()
+}
+
+func (r *PlagiarismRepository) ListByWorkID(ctx context.Context, workID uuid.UUID) ([]*plagiarism.Report, error) {
+	var models []reportDB
+	err := r.db.SelectContext(ctx, &models, "SELECT * FROM plagiarism_reports WHERE work_id = $1 ORDER BY revision", workID)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*plagiarism.Report, len(models))
+	for i, m := range models {
+		report, err := m.toDomainEntity()
+		if err != nil {
+			return nil, err
+		}
+		reports[i] = report
+	}
+	return reports, nil
+}
+
+func (m reportDB) toDomainEntity() (*plagiarism.Report, error) {
 	var details plagiarism.AnalysisDetails
-	if len(model.DetailsJSON) > 0 {
-		if err := json.Unmarshal(model.DetailsJSON, &details); err != nil {
+	if len(m.DetailsJSON) > 0 {
+		if err := json.Unmarshal(m.DetailsJSON, &details); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []plagiarism.Match
+	if len(m.MatchesJSON) > 0 {
+		if err := json.Unmarshal(m.MatchesJSON, &matches); err != nil {
+			return nil, err
+		}
+	}
+
+	var policy *plagiarism.Policy
+	if m.PolicyJSON != nil {
+		policy = &plagiarism.Policy{}
+		if err := json.Unmarshal(*m.PolicyJSON, policy); err != nil {
+			return nil, err
+		}
+	}
+
+	var decision *plagiarism.Decision
+	if m.DecisionJSON != nil {
+		decision = &plagiarism.Decision{}
+		if err := json.Unmarshal(*m.DecisionJSON, decision); err != nil {
 			return nil, err
 		}
 	}
 
 	return &plagiarism.Report{
-		ID:            model.ID,
-		WorkID:        model.WorkID,
-		IsPlagiarized: model.IsPlagiarized,
-		Score:         model.Score,
-		MatchedWorkID: model.MatchedWorkID,
+		ID:            m.ID,
+		WorkID:        m.WorkID,
+		IsPlagiarized: m.IsPlagiarized,
+		Score:         m.Score,
+		MatchedWorkID: m.MatchedWorkID,
+		Matches:       matches,
 		Details:       details,
-		CreatedAt:     model.CreatedAt,
+		Decision:      decision,
+		Revision:      m.Revision,
+		Policy:        policy,
+		Reason:        m.Reason,
+		CreatedAt:     m.CreatedAt,
 	}, nil
 }
+
+// assignmentEntryDB — строка LEFT JOIN works × последний отчет; поля отчета NULL, если проверки не было.
+type assignmentEntryDB struct {
+	WorkID        uuid.UUID        `db:"work_id"`
+	StudentID     uuid.UUID        `db:"student_id"`
+	SubmittedAt   time.Time        `db:"submitted_at"`
+	ReportID      *uuid.UUID       `db:"report_id"`
+	IsPlagiarized sql.NullBool     `db:"is_plagiarized"`
+	Score         sql.NullFloat64  `db:"similarity_score"`
+	MatchedWorkID *uuid.UUID       `db:"matched_with_work_id"`
+	MatchesJSON   *json.RawMessage `db:"matches"`
+	DetailsJSON   *json.RawMessage `db:"analysis_details"`
+	Revision      sql.NullInt64    `db:"revision"`
+	PolicyJSON    *json.RawMessage `db:"policy"`
+	DecisionJSON  *json.RawMessage `db:"decision"`
+	Reason        sql.NullString   `db:"reason"`
+	CreatedAt     sql.NullTime     `db:"created_at"`
+}
+
+func (r *PlagiarismRepository) ListByAssignmentID(ctx context.Context, assignmentID uuid.UUID) ([]plagiarism.AssignmentEntry, error) {
+	var models []assignmentEntryDB
+	query := `
+		SELECT
+			w.id AS work_id, w.student_id, w.submitted_at,
+			pr.id AS report_id, pr.is_plagiarized, pr.similarity_score,
+			pr.matched_with_work_id, pr.matches, pr.analysis_details,
+			pr.revision, pr.policy, pr.decision, pr.reason, pr.created_at
+		FROM works w
+		LEFT JOIN LATERAL (
+			SELECT * FROM plagiarism_reports
+			WHERE work_id = w.id
+			ORDER BY revision DESC
+			LIMIT 1
+		) pr ON true
+		WHERE w.assignment_id = $1
+		ORDER BY w.submitted_at
+	`
+
+	if err := r.db.SelectContext(ctx, &models, query, assignmentID); err != nil {
+		return nil, err
+	}
+
+	entries := make([]plagiarism.AssignmentEntry, len(models))
+	for i, m := range models {
+		entries[i] = plagiarism.AssignmentEntry{
+			WorkID:      m.WorkID,
+			StudentID:   m.StudentID,
+			SubmittedAt: m.SubmittedAt,
+		}
+		if m.ReportID == nil {
+			continue
+		}
+
+		report, err := reportDB{
+			ID:            *m.ReportID,
+			WorkID:        m.WorkID,
+			IsPlagiarized: m.IsPlagiarized.Bool,
+			Score:         m.Score.Float64,
+			MatchedWorkID: m.MatchedWorkID,
+			MatchesJSON:   rawJSON(m.MatchesJSON),
+			DetailsJSON:   rawJSON(m.DetailsJSON),
+			Revision:      int(m.Revision.Int64),
+			PolicyJSON:    m.PolicyJSON,
+			DecisionJSON:  m.DecisionJSON,
+			Reason:        m.Reason.String,
+			CreatedAt:     m.CreatedAt.Time,
+		}.toDomainEntity()
+		if err != nil {
+			return nil, err
+		}
+		entries[i].Report = report
+	}
+	return entries, nil
+}
+
+// nullableJSON кодирует значение для JSON-колонки, которая может быть NULL.
+func nullableJSON(v any) (*json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	raw := json.RawMessage(b)
+	return &raw, nil
+}
+
+// rawJSON разворачивает JSON-колонку, которая может быть NULL.
+func rawJSON(v *json.RawMessage) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/review"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+)
+
+const reviewColumns = "report_id, work_id, assignment_id, assignee_id, verdict, verdict_by, verdict_comment, decided_at, updated_at"
+
+type ReviewRepository struct {
+	db *sqlx.DB
+}
+
+func NewReviewRepository(db *sqlx.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+type reviewDB struct {
+	ReportID       uuid.UUID      `db:"report_id"`
+	WorkID         uuid.UUID      `db:"work_id"`
+	AssignmentID   uuid.UUID      `db:"assignment_id"`
+	AssigneeID     *uuid.UUID     `db:"assignee_id"`
+	Verdict        sql.NullString `db:"verdict"`
+	VerdictBy      *uuid.UUID     `db:"verdict_by"`
+	VerdictComment string         `db:"verdict_comment"`
+	DecidedAt      *time.Time     `db:"decided_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+func (m reviewDB) toDomain() *review.Review {
+	return &review.Review{
+		ReportID:       m.ReportID,
+		WorkID:         m.WorkID,
+		AssignmentID:   m.AssignmentID,
+		AssigneeID:     m.AssigneeID,
+		Verdict:        review.Verdict(m.Verdict.String),
+		VerdictBy:      m.VerdictBy,
+		VerdictComment: m.VerdictComment,
+		DecidedAt:      m.DecidedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+type reviewEventDB struct {
+	ID         uuid.UUID      `db:"id"`
+	ReportID   uuid.UUID      `db:"report_id"`
+	ActorID    uuid.UUID      `db:"actor_id"`
+	Kind       string         `db:"kind"`
+	AssigneeID *uuid.UUID     `db:"assignee_id"`
+	Verdict    sql.NullString `db:"verdict"`
+	Comment    string         `db:"comment"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func (r *ReviewRepository) Get(ctx context.Context, workID uuid.UUID) (*review.Review, error) {
+	var model reviewDB
+	err := r.db.GetContext(ctx, &model, "SELECT "+reviewColumns+" FROM reviews WHERE work_id = $1", workID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	rv := model.toDomain()
+
+	var events []reviewEventDB
+	err = r.db.SelectContext(ctx, &events, `
+		SELECT id, report_id, actor_id, kind, assignee_id, verdict, comment, created_at
+		FROM review_events WHERE report_id = $1 ORDER BY created_at, id
+	`, model.ReportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review history: %w", err)
+	}
+	for _, e := range events {
+		rv.History = append(rv.History, review.Event{
+			ID:         e.ID,
+			ReportID:   e.ReportID,
+			ActorID:    e.ActorID,
+			Kind:       review.EventKind(e.Kind),
+			AssigneeID: e.AssigneeID,
+			Verdict:    review.Verdict(e.Verdict.String),
+			Comment:    e.Comment,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+	return rv, nil
+}
+
+func (r *ReviewRepository) Save(ctx context.Context, rv *review.Review, e review.Event) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO reviews (`+reviewColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (work_id) DO UPDATE SET
+			assignee_id = EXCLUDED.assignee_id,
+			verdict = EXCLUDED.verdict,
+			verdict_by = EXCLUDED.verdict_by,
+			verdict_comment = EXCLUDED.verdict_comment,
+			decided_at = EXCLUDED.decided_at,
+			updated_at = EXCLUDED.updated_at
+	`, rv.ReportID, rv.WorkID, rv.AssignmentID, rv.AssigneeID, nullString(string(rv.Verdict)),
+		rv.VerdictBy, rv.VerdictComment, rv.DecidedAt, rv.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save review: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO review_events (id, report_id, actor_id, kind, assignee_id, verdict, comment, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, e.ID, e.ReportID, e.ActorID, string(e.Kind), e.AssigneeID, nullString(string(e.Verdict)), e.Comment, e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save review event: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *ReviewRepository) ListByAssignment(ctx context.Context, assignmentID uuid.UUID) (map[uuid.UUID]*review.Review, error) {
+	var models []reviewDB
+	if err := r.db.SelectContext(ctx, &models, "SELECT "+reviewColumns+" FROM reviews WHERE assignment_id = $1", assignmentID); err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	reviews := make(map[uuid.UUID]*review.Review, len(models))
+	for _, m := range models {
+		reviews[m.WorkID] = m.toDomain()
+	}
+	return reviews, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/webhook"
+)
+
+const (
+	subscriptionColumns = "id, name, url, events, secret, assignment_id, created_by, created_at"
+	deliveryColumns     = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_status_code, last_error, replay_of, created_at, delivered_at, updated_at`
+)
+
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+type subscriptionDB struct {
+	ID           uuid.UUID      `db:"id"`
+	Name         string         `db:"name"`
+	URL          string         `db:"url"`
+	Events       pq.StringArray `db:"events"`
+	Secret       string         `db:"secret"`
+	AssignmentID *uuid.UUID     `db:"assignment_id"`
+	CreatedBy    uuid.UUID      `db:"created_by"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
+
+func (m subscriptionDB) toDomain() *webhook.Subscription {
+	events := make([]webhook.EventType, len(m.Events))
+	for i, e := range m.Events {
+		events[i] = webhook.EventType(e)
+	}
+	return &webhook.Subscription{
+		ID:           m.ID,
+		Name:         m.Name,
+		URL:          m.URL,
+		Events:       events,
+		Secret:       m.Secret,
+		AssignmentID: m.AssignmentID,
+		CreatedBy:    m.CreatedBy,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+type deliveryDB struct {
+	ID             uuid.UUID  `db:"id"`
+	SubscriptionID uuid.UUID  `db:"subscription_id"`
+	EventID        uuid.UUID  `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	Payload        []byte     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastStatusCode int        `db:"last_status_code"`
+	LastError      string     `db:"last_error"`
+	ReplayOf       *uuid.UUID `db:"replay_of"`
+	CreatedAt      time.Time  `db:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+func (m deliveryDB) toDomain() *webhook.Delivery {
+	return &webhook.Delivery{
+		ID:             m.ID,
+		SubscriptionID: m.SubscriptionID,
+		EventID:        m.EventID,
+		EventType:      webhook.EventType(m.EventType),
+		Payload:        m.Payload,
+		Status:         webhook.DeliveryStatus(m.Status),
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		LastStatusCode: m.LastStatusCode,
+		LastError:      m.LastError,
+		ReplayOf:       m.ReplayOf,
+		CreatedAt:      m.CreatedAt,
+		DeliveredAt:    m.DeliveredAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func (r *WebhookRepository) SaveSubscription(ctx context.Context, s *webhook.Subscription) error {
+	events := make(pq.StringArray, len(s.Events))
+	for i, e := range s.Events {
+		events[i] = string(e)
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_subscriptions (`+subscriptionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, s.ID, s.Name, s.URL, events, s.Secret, s.AssignmentID, s.CreatedBy, s.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	var model subscriptionDB
+	err := r.db.GetContext(ctx, &model, "SELECT "+subscriptionColumns+" FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return model.toDomain(), nil
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	return r.listSubscriptions(ctx, "SELECT "+subscriptionColumns+" FROM webhook_subscriptions ORDER BY created_at DESC")
+}
+
+func (r *WebhookRepository) SubscriptionsFor(ctx context.Context, t webhook.EventType) ([]*webhook.Subscription, error) {
+	return r.listSubscriptions(ctx, "SELECT "+subscriptionColumns+" FROM webhook_subscriptions WHERE $1 = ANY (events)", string(t))
+}
+
+func (r *WebhookRepository) listSubscriptions(ctx context.Context, query string, args ...interface{}) ([]*webhook.Subscription, error) {
+	var models []subscriptionDB
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	subs := make([]*webhook.Subscription, 0, len(models))
+	for _, m := range models {
+		subs = append(subs, m.toDomain())
+	}
+	return subs, nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return shared.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) SaveDeliveries(ctx context.Context, ds []*webhook.Delivery) error {
+	if len(ds) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, d := range ds {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (`+deliveryColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		`, d.ID, d.SubscriptionID, d.EventID, string(d.EventType), d.Payload, string(d.Status), d.Attempts,
+			d.NextAttemptAt, d.LastStatusCode, d.LastError, d.ReplayOf, d.CreatedAt, d.DeliveredAt, d.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save webhook delivery: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5,
+			last_error = $6, delivered_at = $7, updated_at = $8
+		WHERE id = $1
+	`, d.ID, string(d.Status), d.Attempts, d.NextAttemptAt, d.LastStatusCode, d.LastError, d.DeliveredAt, d.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	var model deliveryDB
+	err := r.db.GetContext(ctx, &model, "SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE id = $1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return model.toDomain(), nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*webhook.Delivery, error) {
+	return r.listDeliveries(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE subscription_id = $1 ORDER BY created_at DESC LIMIT $2
+	`, subscriptionID, limit)
+}
+
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*webhook.Delivery, error) {
+	// SKIP LOCKED позволяет нескольким отправителям разбирать очередь, не дожидаясь друг друга.
+	return r.listDeliveries(ctx, `
+		UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $3 AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns,
+		now, now.Add(lease), string(webhook.DeliveryPending), limit)
+}
+
+func (r *WebhookRepository) listDeliveries(ctx context.Context, query string, args ...interface{}) ([]*webhook.Delivery, error) {
+	var models []deliveryDB
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	ds := make([]*webhook.Delivery, 0, len(models))
+	for _, m := range models {
+		ds = append(ds, m.toDomain())
+	}
+	return ds, nil
+}
@@ -95,6 +95,21 @@ func (r *WorkRepository) FindByAssignmentID(ctx context.Context, assignmentID uu
 	return result, nil
 }
 
+// FindByStudentID возвращает все работы студента по всем заданиям, от ранних к поздним.
+func (r *WorkRepository) FindByStudentID(ctx context.Context, studentID uuid.UUID) ([]*work.Work, error) {
+	var models []workDB
+	err := r.db.SelectContext(ctx, &models, "SELECT * FROM works WHERE student_id = $1 ORDER BY submitted_at", studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*work.Work, len(models))
+	for i, m := range models {
+		result[i] = r.toDomainEntity(m)
+	}
+	return result, nil
+}
+
 func (r *WorkRepository) Exists(ctx context.Context, studentID, assignmentID uuid.UUID) (bool, error) {
 	var exists bool
 	query := "SELECT EXISTS(SELECT 1 FROM works WHERE student_id = $1 AND assignment_id = $2)"
@@ -0,0 +1,148 @@
+package progress
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Stage string
+
+const (
+	StageQueued     Stage = "queued"
+	StageExtracting Stage = "extracting"
+	StageComparing  Stage = "comparing"
+	StageCompleted  Stage = "completed"
+	StageFailed     Stage = "failed"
+)
+
+// Event — состояние проверки одной работы. Done и Total заполняются на этапе comparing,
+// итог проверки — на этапе completed.
+type Event struct {
+	WorkID        uuid.UUID  `json:"work_id"`
+	Stage         Stage      `json:"stage"`
+	Done          int        `json:"done,omitempty"`
+	Total         int        `json:"total,omitempty"`
+	ReportID      *uuid.UUID `json:"report_id,omitempty"`
+	Score         *float64   `json:"score,omitempty"`
+	IsPlagiarized *bool      `json:"is_plagiarized,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	At            time.Time  `json:"at"`
+}
+
+// Terminal сообщает, что после события проверка больше не продолжится.
+func (e Event) Terminal() bool {
+	return e.Stage == StageCompleted || e.Stage == StageFailed
+}
+
+// subscriberBuffer — сколько событий ждет медленного читателя. Промежуточные события
+// comparing при переполнении пропускаются, завершающее доставляется всегда.
+const subscriberBuffer = 32
+
+// Bus раздает события проверки подписчикам внутри процесса и помнит последнее событие
+// по каждой работе, чтобы подключившийся позже сразу получил текущее состояние.
+type Bus struct {
+	mu        sync.Mutex
+	subs      map[uuid.UUID]map[chan Event]struct{}
+	last      map[uuid.UUID]Event
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewBus создает шину; последнее событие работы забывается через retention после публикации.
+func NewBus(retention time.Duration) *Bus {
+	return &Bus{
+		subs:      make(map[uuid.UUID]map[chan Event]struct{}),
+		last:      make(map[uuid.UUID]Event),
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Publish рассылает событие подписчикам работы. После завершающего события их каналы
+// закрываются.
+func (b *Bus) Publish(e Event) {
+	if e.At.IsZero() {
+		e.At = b.now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.prune()
+	b.last[e.WorkID] = e
+
+	for ch := range b.subs[e.WorkID] {
+		send(ch, e)
+		if e.Terminal() {
+			close(ch)
+		}
+	}
+	if e.Terminal() {
+		delete(b.subs, e.WorkID)
+	}
+}
+
+// Subscribe возвращает канал событий работы и функцию отписки. Если по работе уже
+// что-то публиковалось, последнее событие приходит первым; после завершающего события
+// канал закрыт.
+func (b *Bus) Subscribe(workID uuid.UUID) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if e, ok := b.last[workID]; ok && b.now().Sub(e.At) <= b.retention {
+		ch <- e
+		if e.Terminal() {
+			close(ch)
+			return ch, func() {}
+		}
+	}
+	if b.subs[workID] == nil {
+		b.subs[workID] = make(map[chan Event]struct{})
+	}
+	b.subs[workID][ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[workID][ch]; !ok {
+			return
+		}
+		delete(b.subs[workID], ch)
+		if len(b.subs[workID]) == 0 {
+			delete(b.subs, workID)
+		}
+		close(ch)
+	}
+}
+
+// send не блокирует публикацию: промежуточное событие для переполненного канала
+// отбрасывается, а для завершающего освобождается место за счет самого старого.
+// Пишет в каналы только Publish под b.mu, поэтому после вычитывания место гарантировано.
+func send(ch chan Event, e Event) {
+	select {
+	case ch <- e:
+		return
+	default:
+	}
+	if !e.Terminal() {
+		return
+	}
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- e
+}
+
+func (b *Bus) prune() {
+	cutoff := b.now().Add(-b.retention)
+	for id, e := range b.last {
+		if e.At.Before(cutoff) {
+			delete(b.last, id)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package progress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func drain(t *testing.T, ch <-chan Event) []Event {
+	t.Helper()
+	var events []Event
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				return events
+			}
+			events = append(events, e)
+		case <-time.After(time.Second):
+			t.Fatal("канал не закрыт после завершающего события")
+		}
+	}
+}
+
+func TestBusDeliversStagesInOrder(t *testing.T) {
+	bus := NewBus(time.Minute)
+	workID := uuid.New()
+
+	ch, unsubscribe := bus.Subscribe(workID)
+	defer unsubscribe()
+
+	score := 0.4
+	bus.Publish(Event{WorkID: workID, Stage: StageQueued})
+	bus.Publish(Event{WorkID: workID, Stage: StageExtracting})
+	bus.Publish(Event{WorkID: workID, Stage: StageComparing, Done: 1, Total: 2})
+	bus.Publish(Event{WorkID: uuid.New(), Stage: StageQueued})
+	bus.Publish(Event{WorkID: workID, Stage: StageComparing, Done: 2, Total: 2})
+	bus.Publish(Event{WorkID: workID, Stage: StageCompleted, Score: &score})
+
+	events := drain(t, ch)
+	require.Len(t, events, 5, "события другой работы не приходят")
+	stages := make([]Stage, len(events))
+	for i, e := range events {
+		stages[i] = e.Stage
+		assert.False(t, e.At.IsZero())
+	}
+	assert.Equal(t, []Stage{StageQueued, StageExtracting, StageComparing, StageComparing, StageCompleted}, stages)
+	assert.Equal(t, 1, events[2].Done)
+	assert.Equal(t, 0.4, *events[4].Score)
+}
+
+func TestBusLateSubscriberGetsLastEvent(t *testing.T) {
+	bus := NewBus(time.Minute)
+	workID := uuid.New()
+
+	bus.Publish(Event{WorkID: workID, Stage: StageComparing, Done: 3, Total: 10})
+	ch, unsubscribe := bus.Subscribe(workID)
+	first := <-ch
+	assert.Equal(t, StageComparing, first.Stage)
+	assert.Equal(t, 3, first.Done)
+	unsubscribe()
+	unsubscribe()
+
+	bus.Publish(Event{WorkID: workID, Stage: StageFailed, Error: "storage unavailable"})
+	events := drain(t, mustSubscribe(bus, workID))
+	require.Len(t, events, 1, "после завершения канал сразу закрыт")
+	assert.Equal(t, StageFailed, events[0].Stage)
+}
+
+func TestBusForgetsAfterRetention(t *testing.T) {
+	bus := NewBus(time.Minute)
+	now := time.Now()
+	bus.now = func() time.Time { return now }
+	workID := uuid.New()
+
+	bus.Publish(Event{WorkID: workID, Stage: StageCompleted})
+	now = now.Add(2 * time.Minute)
+
+	ch, unsubscribe := bus.Subscribe(workID)
+	defer unsubscribe()
+	select {
+	case e := <-ch:
+		t.Fatalf("устаревшее событие %s не должно приходить", e.Stage)
+	default:
+	}
+
+	bus.Publish(Event{WorkID: uuid.New(), Stage: StageQueued})
+	assert.NotContains(t, bus.last, workID)
+}
+
+func TestBusSlowSubscriberStillGetsTerminalEvent(t *testing.T) {
+	bus := NewBus(time.Minute)
+	workID := uuid.New()
+	ch, unsubscribe := bus.Subscribe(workID)
+	defer unsubscribe()
+
+	for i := 1; i <= subscriberBuffer*2; i++ {
+		bus.Publish(Event{WorkID: workID, Stage: StageComparing, Done: i, Total: subscriberBuffer * 2})
+	}
+	bus.Publish(Event{WorkID: workID, Stage: StageCompleted})
+
+	events := drain(t, ch)
+	require.Len(t, events, subscriberBuffer)
+	assert.Equal(t, StageCompleted, events[len(events)-1].Stage)
+}
+
+func mustSubscribe(bus *Bus, workID uuid.UUID) <-chan Event {
+	ch, _ := bus.Subscribe(workID)
+	return ch
+}
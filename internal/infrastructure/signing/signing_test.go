@@ -0,0 +1,150 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(t *testing.T) []byte {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("gateway:abc, analysis:d:e")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("abc"), keys["gateway"])
+	assert.Equal(t, []byte("d:e"), keys["analysis"], "секрет может содержать двоеточие")
+
+	_, err = ParseKeys("gateway")
+	assert.Error(t, err)
+}
+
+func TestSignVerify(t *testing.T) {
+	key := testKey(t)
+	signer, err := NewSigner("gateway", key)
+	require.NoError(t, err)
+	verifier, err := NewVerifier(map[string][]byte{"gateway": key})
+	require.NoError(t, err)
+
+	body := []byte(`{"work_id":"1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/internal/analyze?x=1", bytes.NewReader(body))
+	signer.Sign(req, body)
+
+	keyID, err := verifier.Verify(req)
+	require.NoError(t, err)
+	assert.Equal(t, "gateway", keyID)
+
+	restored, _ := io.ReadAll(req.Body)
+	assert.Equal(t, body, restored, "обработчик должен получить тело после проверки")
+}
+
+func TestVerify_Rejections(t *testing.T) {
+	key := testKey(t)
+	signer, _ := NewSigner("gateway", key)
+	verifier, _ := NewVerifier(map[string][]byte{"gateway": key})
+
+	unsigned := httptest.NewRequest(http.MethodGet, "/internal/files/1/content", nil)
+	_, err := verifier.Verify(unsigned)
+	assert.ErrorIs(t, err, ErrUnsigned)
+
+	tampered := httptest.NewRequest(http.MethodPost, "/internal/analyze", bytes.NewReader([]byte("evil")))
+	signer.Sign(tampered, []byte("original"))
+	_, err = verifier.Verify(tampered)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	otherPath := httptest.NewRequest(http.MethodGet, "/internal/files/2/content", nil)
+	signer.Sign(otherPath, nil)
+	otherPath.URL.Path = "/internal/files/3/content"
+	_, err = verifier.Verify(otherPath)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	foreign, _ := NewSigner("intruder", testKey(t))
+	req := httptest.NewRequest(http.MethodGet, "/internal/files/1/content", nil)
+	foreign.Sign(req, nil)
+	_, err = verifier.Verify(req)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	wrongSecret, _ := NewSigner("gateway", testKey(t))
+	req = httptest.NewRequest(http.MethodGet, "/internal/files/1/content", nil)
+	wrongSecret.Sign(req, nil)
+	_, err = verifier.Verify(req)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	old := httptest.NewRequest(http.MethodGet, "/internal/files/1/content", nil)
+	signer.now = func() time.Time { return time.Now().Add(-2 * MaxSkew) }
+	signer.Sign(old, nil)
+	_, err = verifier.Verify(old)
+	assert.ErrorIs(t, err, ErrStaleTimestamp)
+
+	garbage := httptest.NewRequest(http.MethodGet, "/internal/files/1/content", nil)
+	garbage.Header.Set(HeaderKeyID, "gateway")
+	garbage.Header.Set(HeaderTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+	garbage.Header.Set(HeaderSignature, "00")
+	_, err = verifier.Verify(garbage)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestClient_SignsRequests(t *testing.T) {
+	key := testKey(t)
+	signer, _ := NewSigner("analysis", key)
+	verifier, _ := NewVerifier(map[string][]byte{"analysis": key})
+
+	var verifyErr error
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, verifyErr = verifier.Verify(r)
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	resp, err := signer.Client(5*time.Second).Post(srv.URL+"/internal/upload?a=b", "text/plain", bytes.NewReader([]byte("payload")))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.NoError(t, verifyErr)
+	assert.Equal(t, "payload", string(gotBody))
+
+	resp, err = http.Get(srv.URL + "/internal/upload")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.ErrorIs(t, verifyErr, ErrUnsigned)
+}
+
+func TestSignCallVerifyCall(t *testing.T) {
+	key := testKey(t)
+	signer, _ := NewSigner("gateway", key)
+	verifier, _ := NewVerifier(map[string][]byte{"gateway": key})
+	const method = "/analysis.v1.AnalysisService/Analyze"
+
+	keyID, ts, sig := signer.SignCall(method, []byte("request"))
+	got, err := verifier.VerifyCall(keyID, ts, sig, method, []byte("request"))
+	require.NoError(t, err)
+	assert.Equal(t, "gateway", got)
+
+	_, err = verifier.VerifyCall(keyID, ts, sig, method, []byte("other"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = verifier.VerifyCall(keyID, ts, sig, "/analysis.v1.AnalysisService/WordCloud", []byte("request"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = verifier.VerifyCall("", "", "", method, nil)
+	assert.ErrorIs(t, err, ErrUnsigned)
+
+	// Подпись вызова не подходит к HTTP-запросу с тем же путем.
+	req := httptest.NewRequest(http.MethodPost, method, bytes.NewReader([]byte("request")))
+	req.Header.Set(HeaderKeyID, keyID)
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderSignature, sig)
+	_, err = verifier.Verify(req)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -54,3 +55,31 @@ func (s *LocalFileStorage) Delete(ctx context.Context, path string) error {
 	fullPath := filepath.Join(s.BaseDir, path)
 	return os.Remove(fullPath)
 }
+
+// Blob — файл в каталоге хранилища.
+type Blob struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// List перечисляет файлы каталога хранилища; пути — в том же виде, что возвращает Upload.
+func (s *LocalFileStorage) List(ctx context.Context) ([]Blob, error) {
+	entries, err := os.ReadDir(s.BaseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs []Blob
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, Blob{Path: e.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	return blobs, nil
+}
@@ -14,7 +14,8 @@ func NewSimpleExtractor() *SimpleExtractor {
 }
 
 func (e *SimpleExtractor) ExtractText(content io.Reader, mimeType string) (string, error) {
-	if mimeType == "text/plain" {
+	// Markdown сравнивается как обычный текст: разметка почти не попадает в шинглы.
+	if mimeType == "text/plain" || mimeType == "text/markdown" {
 		buf := new(bytes.Buffer)
 		_, err := buf.ReadFrom(content)
 		if err != nil {
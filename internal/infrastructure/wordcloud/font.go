@@ -0,0 +1,99 @@
+package wordcloud
+
+// Растровый шрифт 5×7 для PNG: латиница, цифры и кириллица в верхнем регистре.
+// Каждая строка глифа — 5 младших бит, старший из них — левый пиксель.
+const (
+	glyphWidth   = 5
+	glyphHeight  = 7
+	glyphAdvance = glyphWidth + 1
+)
+
+var glyphs = map[rune][glyphHeight]uint8{
+	'A':  {0b01110, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'B':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10001, 0b10001, 0b11110},
+	'C':  {0b01110, 0b10001, 0b10000, 0b10000, 0b10000, 0b10001, 0b01110},
+	'D':  {0b11100, 0b10010, 0b10001, 0b10001, 0b10001, 0b10010, 0b11100},
+	'E':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b11111},
+	'F':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b10000},
+	'G':  {0b01110, 0b10001, 0b10000, 0b10111, 0b10001, 0b10001, 0b01111},
+	'H':  {0b10001, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'I':  {0b01110, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'J':  {0b00111, 0b00010, 0b00010, 0b00010, 0b00010, 0b10010, 0b01100},
+	'K':  {0b10001, 0b10010, 0b10100, 0b11000, 0b10100, 0b10010, 0b10001},
+	'L':  {0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b11111},
+	'M':  {0b10001, 0b11011, 0b10101, 0b10101, 0b10001, 0b10001, 0b10001},
+	'N':  {0b10001, 0b10001, 0b11001, 0b10101, 0b10011, 0b10001, 0b10001},
+	'O':  {0b01110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'P':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10000, 0b10000, 0b10000},
+	'Q':  {0b01110, 0b10001, 0b10001, 0b10001, 0b10101, 0b10010, 0b01101},
+	'R':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10100, 0b10010, 0b10001},
+	'S':  {0b01111, 0b10000, 0b10000, 0b01110, 0b00001, 0b00001, 0b11110},
+	'T':  {0b11111, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100},
+	'U':  {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'V':  {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01010, 0b00100},
+	'W':  {0b10001, 0b10001, 0b10001, 0b10101, 0b10101, 0b10101, 0b01010},
+	'X':  {0b10001, 0b10001, 0b01010, 0b00100, 0b01010, 0b10001, 0b10001},
+	'Y':  {0b10001, 0b10001, 0b01010, 0b00100, 0b00100, 0b00100, 0b00100},
+	'Z':  {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b11111},
+	'0':  {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1':  {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2':  {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3':  {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4':  {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5':  {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6':  {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7':  {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8':  {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9':  {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	'-':  {0b00000, 0b00000, 0b00000, 0b11111, 0b00000, 0b00000, 0b00000},
+	'\'': {0b00100, 0b00100, 0b01000, 0b00000, 0b00000, 0b00000, 0b00000},
+	'Б':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10001, 0b10001, 0b11110},
+	'Г':  {0b11111, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000},
+	'Д':  {0b00110, 0b01010, 0b01010, 0b01010, 0b01010, 0b11111, 0b10001},
+	'Ё':  {0b01010, 0b00000, 0b11111, 0b10000, 0b11110, 0b10000, 0b11111},
+	'Ж':  {0b10101, 0b10101, 0b10101, 0b01110, 0b10101, 0b10101, 0b10101},
+	'З':  {0b01110, 0b10001, 0b00001, 0b00110, 0b00001, 0b10001, 0b01110},
+	'И':  {0b10001, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b10001},
+	'Й':  {0b01010, 0b00100, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001},
+	'Л':  {0b00111, 0b01001, 0b01001, 0b01001, 0b01001, 0b01001, 0b10001},
+	'П':  {0b11111, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001},
+	'У':  {0b10001, 0b10001, 0b10001, 0b01111, 0b00001, 0b10001, 0b01110},
+	'Ф':  {0b00100, 0b01110, 0b10101, 0b10101, 0b10101, 0b01110, 0b00100},
+	'Ц':  {0b10010, 0b10010, 0b10010, 0b10010, 0b10010, 0b11111, 0b00001},
+	'Ч':  {0b10001, 0b10001, 0b10001, 0b01111, 0b00001, 0b00001, 0b00001},
+	'Ш':  {0b10101, 0b10101, 0b10101, 0b10101, 0b10101, 0b10101, 0b11111},
+	'Щ':  {0b10101, 0b10101, 0b10101, 0b10101, 0b10101, 0b11111, 0b00001},
+	'Ъ':  {0b11000, 0b01000, 0b01000, 0b01110, 0b01001, 0b01001, 0b01110},
+	'Ы':  {0b10001, 0b10001, 0b10001, 0b11101, 0b10011, 0b10011, 0b11101},
+	'Ь':  {0b10000, 0b10000, 0b10000, 0b11110, 0b10001, 0b10001, 0b11110},
+	'Э':  {0b01110, 0b10001, 0b00001, 0b00111, 0b00001, 0b10001, 0b01110},
+	'Ю':  {0b10010, 0b10101, 0b10101, 0b11101, 0b10101, 0b10101, 0b10010},
+	'Я':  {0b01111, 0b10001, 0b10001, 0b01111, 0b00101, 0b01001, 0b10001},
+}
+
+// Кириллические буквы, совпадающие по начертанию с латинскими.
+var glyphAliases = map[rune]rune{
+	'А': 'A',
+	'В': 'B',
+	'Е': 'E',
+	'К': 'K',
+	'М': 'M',
+	'Н': 'H',
+	'О': 'O',
+	'Р': 'P',
+	'С': 'C',
+	'Т': 'T',
+	'Х': 'X',
+}
+
+var unknownGlyph = [glyphHeight]uint8{0b11111, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b11111}
+
+func glyph(r rune) [glyphHeight]uint8 {
+	if a, ok := glyphAliases[r]; ok {
+		r = a
+	}
+	if g, ok := glyphs[r]; ok {
+		return g
+	}
+	return unknownGlyph
+}
@@ -0,0 +1,158 @@
+package wordcloud
+
+import (
+	"math"
+	"strings"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/analytics"
+)
+
+type Options struct {
+	Width    int
+	Height   int
+	MaxWords int
+	// MinScale/MaxScale — размер пикселя шрифта 5×7 для самого редкого и самого частого слова.
+	MinScale int
+	MaxScale int
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Width:    800,
+		Height:   600,
+		MaxWords: 100,
+		MinScale: 2,
+		MaxScale: 10,
+	}
+}
+
+var palette = []string{"#1f77b4", "#d62728", "#2ca02c", "#ff7f0e", "#9467bd", "#8c564b", "#e377c2", "#17becf"}
+
+// Placement — размещенное слово: прямоугольник в пикселях холста.
+type Placement struct {
+	Word  string
+	Count int
+	X, Y  int
+	W, H  int
+	Scale int
+	Color string
+}
+
+type Cloud struct {
+	Width  int
+	Height int
+	Words  []Placement
+}
+
+// Layout раскладывает слова по архимедовой спирали от центра: каждое слово ставится
+// в первую точку спирали, где его прямоугольник не пересекается с уже размещенными
+// и не выходит за холст. Не поместившееся слово уменьшается, а при минимальном
+// размере пропускается. Раскладка детерминирована.
+func Layout(freqs []analytics.WordCount, opts Options) *Cloud {
+	cloud := &Cloud{Width: opts.Width, Height: opts.Height}
+	if len(freqs) == 0 {
+		return cloud
+	}
+	if opts.MaxWords > 0 && len(freqs) > opts.MaxWords {
+		freqs = freqs[:opts.MaxWords]
+	}
+
+	maxCount, minCount := freqs[0].Count, freqs[len(freqs)-1].Count
+
+	for i, f := range freqs {
+		scale := scaleFor(f.Count, minCount, maxCount, opts)
+		for ; scale >= opts.MinScale; scale-- {
+			w, h := textSize(f.Word, scale)
+			if x, y, ok := cloud.place(w, h, 2*scale); ok {
+				cloud.Words = append(cloud.Words, Placement{
+					Word:  f.Word,
+					Count: f.Count,
+					X:     x,
+					Y:     y,
+					W:     w,
+					H:     h,
+					Scale: scale,
+					Color: palette[i%len(palette)],
+				})
+				break
+			}
+		}
+	}
+
+	return cloud
+}
+
+func scaleFor(count, minCount, maxCount int, opts Options) int {
+	if maxCount == minCount {
+		return opts.MaxScale
+	}
+	ratio := math.Sqrt(float64(count-minCount) / float64(maxCount-minCount))
+	return opts.MinScale + int(math.Round(ratio*float64(opts.MaxScale-opts.MinScale)))
+}
+
+// textSize — размер слова в пикселях растрового шрифта при данном масштабе.
+func textSize(word string, scale int) (int, int) {
+	n := len([]rune(word))
+	return (n*glyphAdvance - 1) * scale, glyphHeight * scale
+}
+
+func (c *Cloud) place(w, h, padding int) (int, int, bool) {
+	if w > c.Width || h > c.Height {
+		return 0, 0, false
+	}
+
+	cx, cy := float64(c.Width)/2, float64(c.Height)/2
+	maxRadius := math.Hypot(cx, cy)
+	aspect := float64(c.Height) / float64(c.Width)
+
+	for t := 0.0; ; t += 0.1 {
+		r := 2 * t
+		if r > maxRadius {
+			return 0, 0, false
+		}
+		x := int(cx + r*math.Cos(t) - float64(w)/2)
+		y := int(cy + r*math.Sin(t)*aspect - float64(h)/2)
+
+		if x < 0 || y < 0 || x+w > c.Width || y+h > c.Height {
+			continue
+		}
+		if !c.collides(x, y, w, h, padding) {
+			return x, y, true
+		}
+	}
+}
+
+func (c *Cloud) collides(x, y, w, h, padding int) bool {
+	for _, p := range c.Words {
+		if x < p.X+p.W+padding && p.X < x+w+padding &&
+			y < p.Y+p.H+padding && p.Y < y+h+padding {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Placement) upper() string {
+	return strings.ToUpper(p.Word)
+}
+
+const (
+	FormatSVG = "svg"
+	FormatPNG = "png"
+)
+
+// Generate считает частоты слов текста (без стоп-слов RU/EN) и раскладывает облако.
+func Generate(text string, opts Options) *Cloud {
+	return Layout(analytics.Frequencies(text, analytics.MinWordLen, opts.MaxWords), opts)
+}
+
+func ValidFormat(format string) bool {
+	return format == FormatSVG || format == FormatPNG
+}
+
+func ContentType(format string) string {
+	if format == FormatPNG {
+		return "image/png"
+	}
+	return "image/svg+xml"
+}
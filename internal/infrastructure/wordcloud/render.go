@@ -0,0 +1,90 @@
+package wordcloud
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteSVG выводит облако как SVG. Ширина каждого слова зафиксирована через textLength,
+// поэтому прямоугольники раскладки соблюдаются при любом доступном шрифте.
+func (c *Cloud) WriteSVG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		c.Width, c.Height, c.Width, c.Height)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+
+	for _, p := range c.Words {
+		fmt.Fprintf(bw,
+			`<text x="%d" y="%d" font-size="%d" font-family="Helvetica, Arial, sans-serif" font-weight="bold" fill="%s" textLength="%d" lengthAdjust="spacingAndGlyphs"><title>%s: %d</title>`,
+			p.X, p.Y+p.H, p.H*4/3, p.Color, p.W, escapeXML(p.Word), p.Count)
+		bw.WriteString(escapeXML(p.upper()))
+		bw.WriteString("</text>\n")
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// WritePNG рисует облако растровым шрифтом 5×7 и кодирует в PNG.
+func (c *Cloud) WritePNG(w io.Writer) error {
+	img := image.NewRGBA(image.Rect(0, 0, c.Width, c.Height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	for _, p := range c.Words {
+		col := parseHexColor(p.Color)
+		x := p.X
+		for _, r := range p.upper() {
+			drawGlyph(img, glyph(r), x, p.Y, p.Scale, col)
+			x += glyphAdvance * p.Scale
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func drawGlyph(img *image.RGBA, g [glyphHeight]uint8, x0, y0, scale int, col color.RGBA) {
+	for row := 0; row < glyphHeight; row++ {
+		for bit := 0; bit < glyphWidth; bit++ {
+			if g[row]&(1<<(glyphWidth-1-bit)) == 0 {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetRGBA(x0+bit*scale+dx, y0+row*scale+dy, col)
+				}
+			}
+		}
+	}
+}
+
+func parseHexColor(s string) color.RGBA {
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil || len(s) != 7 {
+		return color.RGBA{A: 0xff}
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// Write выводит облако в формате svg или png.
+func (c *Cloud) Write(w io.Writer, format string) error {
+	if format == FormatPNG {
+		return c.WritePNG(w)
+	}
+	return c.WriteSVG(w)
+}
@@ -0,0 +1,50 @@
+package wordcloud
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/analytics"
+)
+
+func TestLayout_NoOverlapsInsideCanvas(t *testing.T) {
+	text := strings.Repeat("плагиат проверка текст ", 10) + strings.Repeat("golang docker shingle ", 5) +
+		"микросервисы архитектура студент работа задание отчет база данных хранилище анализ"
+	freqs := analytics.Frequencies(text, 3, 0)
+
+	opts := DefaultOptions()
+	cloud := Layout(freqs, opts)
+	assert.NotEmpty(t, cloud.Words)
+	assert.Equal(t, "плагиат", freqs[0].Word)
+
+	for i, a := range cloud.Words {
+		assert.GreaterOrEqual(t, a.X, 0)
+		assert.GreaterOrEqual(t, a.Y, 0)
+		assert.LessOrEqual(t, a.X+a.W, opts.Width)
+		assert.LessOrEqual(t, a.Y+a.H, opts.Height)
+		for _, b := range cloud.Words[i+1:] {
+			overlap := a.X < b.X+b.W && b.X < a.X+a.W && a.Y < b.Y+b.H && b.Y < a.Y+a.H
+			assert.False(t, overlap, "%s overlaps %s", a.Word, b.Word)
+		}
+	}
+}
+
+func TestCloud_Render(t *testing.T) {
+	cloud := Layout([]analytics.WordCount{{Word: "тест", Count: 3}, {Word: "a<b", Count: 1}}, DefaultOptions())
+
+	var svg bytes.Buffer
+	assert.NoError(t, cloud.WriteSVG(&svg))
+	assert.Contains(t, svg.String(), "ТЕСТ</text>")
+	assert.Contains(t, svg.String(), "A&lt;B")
+	assert.NotContains(t, svg.String(), "quickchart")
+
+	var buf bytes.Buffer
+	assert.NoError(t, cloud.WritePNG(&buf))
+	img, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 800, img.Bounds().Dx())
+}
@@ -14,3 +14,31 @@ type HealthCheckResponse struct {
 	Database string `json:"database"`
 	Storage  string `json:"storage"`
 }
+
+type AssignReviewRequest struct {
+	AssigneeID string `json:"assignee_id" binding:"required,uuid"`
+}
+
+type ReviewCommentRequest struct {
+	Comment string `json:"comment" binding:"required"`
+}
+
+type ReviewVerdictRequest struct {
+	Verdict string `json:"verdict" binding:"required,oneof=confirmed dismissed needs_discussion"`
+	Comment string `json:"comment"`
+}
+
+type SubmitAppealRequest struct {
+	Statement string `form:"statement" binding:"required"`
+}
+
+type ResolveAppealRequest struct {
+	Decision   string `json:"decision" binding:"required,oneof=upheld rejected"`
+	Resolution string `json:"resolution" binding:"required"`
+}
+
+// ReanalyzeCourseRequest — задания курса: сервис не хранит курсы, поэтому курс задается
+// списком его заданий.
+type ReanalyzeCourseRequest struct {
+	AssignmentIDs []string `json:"assignment_ids" binding:"required,min=1,max=100,dive,uuid"`
+}
@@ -42,6 +42,10 @@ func ErrorStatusCode(errCode string) int {
 	switch errCode {
 	case "VALIDATION_ERROR":
 		return http.StatusBadRequest
+	case "UNAUTHORIZED":
+		return http.StatusUnauthorized
+	case "FORBIDDEN":
+		return http.StatusForbidden
 	case "NOT_FOUND":
 		return http.StatusNotFound
 	case "FILE_TOO_LARGE":
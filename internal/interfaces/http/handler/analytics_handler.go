@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/analytics"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/wordcloud"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+)
+
+const (
+	defaultKeywordLimit    = 20
+	defaultVocabularyLimit = 200
+	maxAnalyticsLimit      = 1000
+)
+
+type AnalyticsHandler struct {
+	analyticsService *service.AnalyticsService
+}
+
+func NewAnalyticsHandler(as *service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: as,
+	}
+}
+
+// WordCloud godoc
+// @Summary      Word cloud of a work
+// @Description  Render the most frequent words (RU/EN stopwords removed) as SVG or PNG without external services
+// @Tags         works
+// @Produce      image/svg+xml
+// @Produce      image/png
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        format query string false "svg (default) or png"
+// @Success      200 {file} file "Word cloud image"
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/wordcloud [get]
+func (h *AnalyticsHandler) WordCloud(c *gin.Context) {
+	workID, err := uuid.Parse(c.Param("work_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid work_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	format := c.DefaultQuery("format", wordcloud.FormatSVG)
+	if !wordcloud.ValidFormat(format) {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "format must be svg or png", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	opts := wordcloud.DefaultOptions()
+	freqs, err := h.analyticsService.WordFrequencies(c.Request.Context(), workID, opts.MaxWords)
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			resp := httpdto.NewErrorResponse("NOT_FOUND", "Work not found", "")
+			c.JSON(http.StatusNotFound, resp)
+			return
+		}
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to analyze work text", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := wordcloud.Layout(freqs, opts).Write(&buf, format); err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to render word cloud", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+	c.Data(http.StatusOK, wordcloud.ContentType(format), buf.Bytes())
+}
+
+// Stylometry godoc
+// @Summary      Stylometric profile of a work
+// @Description  Sentence lengths, type/token ratio, function words, punctuation and readability (RU/EN), compared with the student's earlier works to flag unusual drift
+// @Tags         works
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Success      200 {object} httpdto.APIResponse{data=service.StylometryResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/stylometry [get]
+func (h *AnalyticsHandler) Stylometry(c *gin.Context) {
+	workID, err := uuid.Parse(c.Param("work_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid work_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	result, err := h.analyticsService.Stylometry(c.Request.Context(), workID)
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			resp := httpdto.NewErrorResponse("NOT_FOUND", "Work not found", "")
+			c.JSON(http.StatusNotFound, resp)
+			return
+		}
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to analyze work style", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// GetAssignmentKeywords godoc
+// @Summary      Distinctive keywords per work
+// @Description  TF-IDF across all works of an assignment: words frequent in a work and rare in the others (quotations and bibliography excluded)
+// @Tags         analytics
+// @Produce      json
+// @Param        assignment_id path string true "Assignment ID (UUID)"
+// @Param        limit query int false "Keywords per work (default 20)"
+// @Success      200 {object} httpdto.APIResponse{data=service.AssignmentKeywordsResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/assignments/{assignment_id}/keywords [get]
+func (h *AnalyticsHandler) GetAssignmentKeywords(c *gin.Context) {
+	assignmentID, ok := parseAssignmentID(c)
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit", defaultKeywordLimit, 1, maxAnalyticsLimit)
+	if !ok {
+		return
+	}
+
+	result, err := h.analyticsService.AssignmentKeywords(c.Request.Context(), assignmentID, limit)
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to compute keywords", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// GetAssignmentVocabulary godoc
+// @Summary      Assignment vocabulary
+// @Description  Most frequent meaningful words over all works of an assignment with the number of works using each
+// @Tags         analytics
+// @Produce      json
+// @Param        assignment_id path string true "Assignment ID (UUID)"
+// @Param        limit query int false "Number of words (default 200)"
+// @Success      200 {object} httpdto.APIResponse{data=service.AssignmentVocabularyResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/assignments/{assignment_id}/vocabulary [get]
+func (h *AnalyticsHandler) GetAssignmentVocabulary(c *gin.Context) {
+	assignmentID, ok := parseAssignmentID(c)
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit", defaultVocabularyLimit, 1, maxAnalyticsLimit)
+	if !ok {
+		return
+	}
+
+	result, err := h.analyticsService.AssignmentVocabulary(c.Request.Context(), assignmentID, limit)
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to compute vocabulary", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// GetRarePhrases godoc
+// @Summary      Rare phrases shared by small groups of works
+// @Description  N-grams that occur in at least two but no more than max_group works of the assignment, grouped by the set of works sharing them
+// @Tags         analytics
+// @Produce      json
+// @Param        assignment_id path string true "Assignment ID (UUID)"
+// @Param        n query int false "Phrase length in words, 2..8 (default 4)"
+// @Param        max_group query int false "Maximum number of works sharing a phrase (default 3)"
+// @Success      200 {object} httpdto.APIResponse{data=service.RarePhrasesResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/assignments/{assignment_id}/rare-phrases [get]
+func (h *AnalyticsHandler) GetRarePhrases(c *gin.Context) {
+	assignmentID, ok := parseAssignmentID(c)
+	if !ok {
+		return
+	}
+
+	opts := analytics.DefaultRarePhraseOptions()
+	if opts.N, ok = queryInt(c, "n", opts.N, 2, 8); !ok {
+		return
+	}
+	if opts.MaxGroup, ok = queryInt(c, "max_group", opts.MaxGroup, 2, 100); !ok {
+		return
+	}
+	if opts.MinContentWords > opts.N {
+		opts.MinContentWords = opts.N
+	}
+
+	result, err := h.analyticsService.RarePhrases(c.Request.Context(), assignmentID, opts)
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to find rare phrases", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+func parseAssignmentID(c *gin.Context) (uuid.UUID, bool) {
+	assignmentID, err := uuid.Parse(c.Param("assignment_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid assignment_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return uuid.Nil, false
+	}
+	return assignmentID, true
+}
+
+// queryInt читает целый query-параметр в диапазоне [min, max]; при ошибке отвечает 400.
+func queryInt(c *gin.Context, name string, def, min, max int) (int, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < min || v > max {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", fmt.Sprintf("%s must be an integer in [%d, %d]", name, min, max), "")
+		c.JSON(http.StatusBadRequest, resp)
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+func NewAPIKeyHandler(s *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: s}
+}
+
+// CreateAPIKey godoc
+// @Summary      Create an API key
+// @Description  Create a scoped key for an LMS integration or a script. The key itself is returned only in this response; only its hash is stored
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request body service.CreateAPIKeyRequest true "Name, scopes (works:submit, reports:read, analytics:read, reviews:write), optional assignment and expiry"
+// @Success      201 {object} httpdto.APIResponse{data=service.CreatedAPIKey}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req service.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid request body", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	key, err := h.apiKeyService.Create(c.Request.Context(), req, middleware.PrincipalFrom(c).UserID)
+	if err != nil {
+		if errors.Is(err, shared.ErrInvalidInput) {
+			resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid API key parameters", err.Error())
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to create API key", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	middleware.SetAuditResource(c, key.ID.String())
+	c.JSON(http.StatusCreated, httpdto.NewSuccessResponse(key))
+}
+
+// ListAPIKeys godoc
+// @Summary      List API keys
+// @Description  List all API keys with scopes, expiry and last use; secrets are never returned
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} httpdto.APIResponse{data=[]service.APIKeyInfo}
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.List(c.Request.Context())
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to list API keys", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(keys))
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Description  Revoke a key immediately; revoking an already revoked key keeps the original revocation time
+// @Tags         admin
+// @Produce      json
+// @Param        key_id path string true "API key ID (UUID)"
+// @Success      200 {object} httpdto.APIResponse{data=service.APIKeyInfo}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/admin/api-keys/{key_id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	keyID, err := uuid.Parse(c.Param("key_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid key_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	key, err := h.apiKeyService.Revoke(c.Request.Context(), keyID)
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			resp := httpdto.NewErrorResponse("NOT_FOUND", "API key not found", "")
+			c.JSON(http.StatusNotFound, resp)
+			return
+		}
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to revoke API key", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(key))
+}
@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/appeal"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+)
+
+type AppealHandler struct {
+	appealService *service.AppealService
+	maxFileSize   int64
+}
+
+func NewAppealHandler(as *service.AppealService, maxFileSize int64) *AppealHandler {
+	return &AppealHandler{appealService: as, maxFileSize: maxFileSize}
+}
+
+// SubmitAppeal godoc
+// @Summary      Appeal a plagiarism finding
+// @Description  The author of a work contests a plagiarism verdict confirmed by a teacher. Allowed within the filing window after the verdict, one open appeal per report
+// @Tags         appeals
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        statement formData string true "Statement"
+// @Param        evidence formData file false "Evidence files (up to 5)"
+// @Success      201 {object} httpdto.APIResponse{data=service.AppealResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Failure      409 {object} httpdto.APIResponse
+// @Failure      413 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/works/{work_id}/appeals [post]
+func (h *AppealHandler) SubmitAppeal(c *gin.Context) {
+	workID, ok := parseWorkID(c)
+	if !ok {
+		return
+	}
+	var req httpdto.SubmitAppealRequest
+	if err := c.ShouldBind(&req); err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid request parameters", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	var headers []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		headers = form.File["evidence"]
+	}
+	if len(headers) > appeal.MaxEvidenceFiles {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", fmt.Sprintf("At most %d evidence files are allowed", appeal.MaxEvidenceFiles), "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	files := make([]service.EvidenceUpload, 0, len(headers))
+	for _, fh := range headers {
+		if fh.Size > h.maxFileSize {
+			resp := httpdto.NewErrorResponse("FILE_TOO_LARGE", fmt.Sprintf("File exceeds max size of %d bytes", h.maxFileSize), fh.Filename)
+			c.JSON(http.StatusRequestEntityTooLarge, resp)
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to open uploaded file", err.Error())
+			c.JSON(http.StatusInternalServerError, resp)
+			return
+		}
+		defer f.Close()
+		mimeType := fh.Header.Get("Content-Type")
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		files = append(files, service.EvidenceUpload{FileName: fh.Filename, MimeType: mimeType, Size: fh.Size, Content: f})
+	}
+
+	student := middleware.PrincipalFrom(c).UserID
+	result, err := h.appealService.Submit(c.Request.Context(), workID, student, req.Statement, files)
+	if err != nil {
+		respondAppealError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, httpdto.NewSuccessResponse(result))
+}
+
+// ListAppeals godoc
+// @Summary      List appeals of a work
+// @Description  All appeals of a work, newest first, with status and review deadline
+// @Tags         appeals
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Success      200 {object} httpdto.APIResponse{data=[]service.AppealResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/appeals [get]
+func (h *AppealHandler) ListAppeals(c *gin.Context) {
+	workID, ok := parseWorkID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.appealService.List(c.Request.Context(), workID)
+	if err != nil {
+		respondAppealError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// GetAppeal godoc
+// @Summary      Get an appeal
+// @Description  Appeal with statement, evidence files, status and decision
+// @Tags         appeals
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        appeal_id path string true "Appeal ID (UUID)"
+// @Success      200 {object} httpdto.APIResponse{data=service.AppealResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/appeals/{appeal_id} [get]
+func (h *AppealHandler) GetAppeal(c *gin.Context) {
+	workID, appealID, ok := parseAppealPath(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.appealService.Get(c.Request.Context(), workID, appealID)
+	if err != nil {
+		respondAppealError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// GetAppealEvidence godoc
+// @Summary      Download appeal evidence
+// @Description  Download a file attached to an appeal
+// @Tags         appeals
+// @Produce      octet-stream
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        appeal_id path string true "Appeal ID (UUID)"
+// @Param        evidence_id path string true "Evidence file ID (UUID)"
+// @Success      200 {file} file "Evidence file"
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/appeals/{appeal_id}/evidence/{evidence_id} [get]
+func (h *AppealHandler) GetAppealEvidence(c *gin.Context) {
+	workID, appealID, ok := parseAppealPath(c)
+	if !ok {
+		return
+	}
+	evidenceID, err := uuid.Parse(c.Param("evidence_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid evidence_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	info, content, err := h.appealService.OpenEvidence(c.Request.Context(), workID, appealID, evidenceID)
+	if err != nil {
+		respondAppealError(c, err)
+		return
+	}
+	defer content.Close()
+
+	// Файлы присланы студентом, поэтому браузер их только скачивает, а не открывает.
+	c.Header("Content-Disposition", "attachment; filename="+strconv.Quote(info.FileName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Type", info.MimeType)
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, content); err != nil {
+		log.Printf("Failed to stream appeal evidence %s: %v", evidenceID, err)
+	}
+}
+
+// StartAppealReview godoc
+// @Summary      Start reviewing an appeal
+// @Description  A teacher of the assignment takes a submitted appeal under review; evidence can no longer be added
+// @Tags         appeals
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        appeal_id path string true "Appeal ID (UUID)"
+// @Success      200 {object} httpdto.APIResponse{data=service.AppealResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Failure      409 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/appeals/{appeal_id}/review [post]
+func (h *AppealHandler) StartAppealReview(c *gin.Context) {
+	workID, appealID, ok := parseAppealPath(c)
+	if !ok {
+		return
+	}
+
+	reviewer := middleware.PrincipalFrom(c).UserID
+	result, err := h.appealService.StartReview(c.Request.Context(), workID, appealID, reviewer)
+	if err != nil {
+		respondAppealError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// ResolveAppeal godoc
+// @Summary      Decide an appeal
+// @Description  Uphold or reject an appeal under review. An upheld appeal changes the review verdict to dismissed
+// @Tags         appeals
+// @Accept       json
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        appeal_id path string true "Appeal ID (UUID)"
+// @Param        request body httpdto.ResolveAppealRequest true "Decision (upheld or rejected) and resolution"
+// @Success      200 {object} httpdto.APIResponse{data=service.AppealResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Failure      409 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/appeals/{appeal_id}/decision [post]
+func (h *AppealHandler) ResolveAppeal(c *gin.Context) {
+	workID, appealID, ok := parseAppealPath(c)
+	if !ok {
+		return
+	}
+	var req httpdto.ResolveAppealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid request body", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	actor := middleware.PrincipalFrom(c).UserID
+	result, err := h.appealService.Resolve(c.Request.Context(), workID, appealID, actor, req.Decision, req.Resolution)
+	if err != nil {
+		respondAppealError(c, err)
+		return
+	}
+
+	middleware.AddAuditDetail(c, "decision", req.Decision)
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+func parseAppealPath(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	workID, ok := parseWorkID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	appealID, err := uuid.Parse(c.Param("appeal_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid appeal_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return workID, appealID, true
+}
+
+func respondAppealError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared.ErrNotFound):
+		c.JSON(http.StatusNotFound, httpdto.NewErrorResponse("NOT_FOUND", "Appeal or report not found", ""))
+	case errors.Is(err, shared.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid appeal", err.Error()))
+	case errors.Is(err, appeal.ErrNotAppealable), errors.Is(err, appeal.ErrFilingClosed),
+		errors.Is(err, appeal.ErrAlreadyOpen), errors.Is(err, appeal.ErrInvalidState):
+		c.JSON(http.StatusConflict, httpdto.NewErrorResponse("CONFLICT", err.Error(), ""))
+	default:
+		c.JSON(http.StatusInternalServerError, httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to process appeal", err.Error()))
+	}
+}
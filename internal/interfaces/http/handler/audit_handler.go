@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(s *service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: s}
+}
+
+// GetAuditLog godoc
+// @Summary      Query the audit log
+// @Description  Who viewed, exported or changed what: report views and exports, threshold changes, overrides, deletions and API key changes, newest first
+// @Tags         admin
+// @Produce      json
+// @Param        actor_id query string false "Actor ID (user or API key UUID)"
+// @Param        action query string false "Action, e.g. report.view, report.export, api_key.create"
+// @Param        resource_type query string false "Resource type, e.g. work, assignment, api_key"
+// @Param        resource_id query string false "Resource ID"
+// @Param        from query string false "From (RFC 3339, inclusive)"
+// @Param        to query string false "To (RFC 3339, exclusive)"
+// @Param        limit query int false "Page size (default 100, max 1000)"
+// @Param        offset query int false "Offset"
+// @Success      200 {object} httpdto.APIResponse{data=[]service.AuditEntryResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/admin/audit [get]
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	filter, err := service.ParseAuditFilter(c.Request.URL.Query())
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid audit filter", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	entries, err := h.auditService.Query(c.Request.Context(), filter)
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to query audit log", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(entries))
+}
+
+// VerifyAuditLog godoc
+// @Summary      Verify the audit log hash chain
+// @Description  Recompute the hash chain over the whole log and report the first entry that was changed, removed or reordered
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} httpdto.APIResponse{data=service.AuditVerification}
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/admin/audit/verify [get]
+func (h *AuditHandler) VerifyAuditLog(c *gin.Context) {
+	result, err := h.auditService.Verify(c.Request.Context())
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to verify audit log", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
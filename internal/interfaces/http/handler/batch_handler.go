@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/batch"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+)
+
+// maxArchiveSize ограничивает zip-архив пакета целиком; каждый файл внутри дополнительно
+// ограничен размером одиночной загрузки.
+const maxArchiveSize = 500 * 1024 * 1024
+
+type BatchHandler struct {
+	batchService *service.BatchService
+}
+
+func NewBatchHandler(bs *service.BatchService) *BatchHandler {
+	return &BatchHandler{batchService: bs}
+}
+
+// CreateBatch godoc
+// @Summary      Submit a batch of works
+// @Description  Upload a zip archive with a CSV or JSON manifest mapping files to students (columns file and student_id). All works are created at once and checked in one combined pass over the assignment; files that cannot be imported are listed with a reason
+// @Tags         batches
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        assignment_id path string true "Assignment ID (UUID)"
+// @Param        archive formData file true "Zip archive with TXT or MD files"
+// @Param        manifest formData file true "Manifest (.csv or .json)"
+// @Success      202 {object} httpdto.APIResponse{data=service.BatchResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      413 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/assignments/{assignment_id}/batches [post]
+func (h *BatchHandler) CreateBatch(c *gin.Context) {
+	assignmentID, ok := parseUUIDParam(c, "assignment_id")
+	if !ok {
+		return
+	}
+
+	manifestHeader, err := c.FormFile("manifest")
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Missing manifest file", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	format, err := batch.FormatOf(manifestHeader.Filename)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+	manifestFile, err := manifestHeader.Open()
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to open uploaded file", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+	defer manifestFile.Close()
+	manifest, err := batch.ParseManifest(manifestFile, format)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	archiveHeader, err := c.FormFile("archive")
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Missing archive file", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	if archiveHeader.Size > maxArchiveSize {
+		resp := httpdto.NewErrorResponse("FILE_TOO_LARGE", fmt.Sprintf("Archive exceeds max size of %d bytes", maxArchiveSize), "")
+		c.JSON(http.StatusRequestEntityTooLarge, resp)
+		return
+	}
+	archive, err := archiveHeader.Open()
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to open uploaded file", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+	defer archive.Close()
+
+	createdBy := middleware.PrincipalFrom(c).UserID
+	result, err := h.batchService.Create(c.Request.Context(), assignmentID, createdBy, archive, archiveHeader.Size, manifest)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, httpdto.NewSuccessResponse(result))
+}
+
+// GetBatch godoc
+// @Summary      Get batch status
+// @Description  Progress of the combined check and the outcome for each manifest file: created work, report status and score, or the reason it was rejected
+// @Tags         batches
+// @Produce      json
+// @Param        assignment_id path string true "Assignment ID (UUID)"
+// @Param        batch_id path string true "Batch ID (UUID)"
+// @Success      200 {object} httpdto.APIResponse{data=service.BatchResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/assignments/{assignment_id}/batches/{batch_id} [get]
+func (h *BatchHandler) GetBatch(c *gin.Context) {
+	assignmentID, ok := parseUUIDParam(c, "assignment_id")
+	if !ok {
+		return
+	}
+	batchID, ok := parseUUIDParam(c, "batch_id")
+	if !ok {
+		return
+	}
+
+	result, err := h.batchService.Get(c.Request.Context(), assignmentID, batchID)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+func respondBatchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared.ErrNotFound):
+		c.JSON(http.StatusNotFound, httpdto.NewErrorResponse("NOT_FOUND", "Batch not found", ""))
+	case errors.Is(err, shared.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid batch", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to process batch", err.Error()))
+	}
+}
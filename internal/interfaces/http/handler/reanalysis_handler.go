@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+)
+
+type ReanalysisHandler struct {
+	reanalysisService *service.ReanalysisService
+}
+
+func NewReanalysisHandler(rs *service.ReanalysisService) *ReanalysisHandler {
+	return &ReanalysisHandler{reanalysisService: rs}
+}
+
+// ReanalyzeWork godoc
+// @Summary      Re-analyze a work
+// @Description  Recompute the report of a work against all other works of its assignment with the current policy. A new report revision is added; earlier revisions are kept
+// @Tags         reanalysis
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        stale_only query bool false "Skip the work if its report already matches the current policy"
+// @Success      200 {object} httpdto.APIResponse{data=service.ReanalysisResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/reanalyze [post]
+func (h *ReanalysisHandler) ReanalyzeWork(c *gin.Context) {
+	workID, ok := parseWorkID(c)
+	if !ok {
+		return
+	}
+	staleOnly, ok := parseStaleOnly(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.reanalysisService.ReanalyzeWork(c.Request.Context(), workID, staleOnly)
+	if err != nil {
+		respondReanalysisError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// ReanalyzeAssignment godoc
+// @Summary      Re-analyze an assignment
+// @Description  Recompute the reports of all works of an assignment with the current policy in one pass
+// @Tags         reanalysis
+// @Produce      json
+// @Param        assignment_id path string true "Assignment ID (UUID)"
+// @Param        stale_only query bool false "Only works whose reports are stale or missing"
+// @Success      200 {object} httpdto.APIResponse{data=service.ReanalysisResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/assignments/{assignment_id}/reanalyze [post]
+func (h *ReanalysisHandler) ReanalyzeAssignment(c *gin.Context) {
+	assignmentID, ok := parseUUIDParam(c, "assignment_id")
+	if !ok {
+		return
+	}
+	staleOnly, ok := parseStaleOnly(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.reanalysisService.ReanalyzeAssignments(c.Request.Context(), []uuid.UUID{assignmentID}, staleOnly)
+	if err != nil {
+		respondReanalysisError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// ReanalyzeCourse godoc
+// @Summary      Re-analyze a course
+// @Description  Recompute the reports of all works of the listed assignments of a course. The caller must teach every listed assignment
+// @Tags         reanalysis
+// @Accept       json
+// @Produce      json
+// @Param        request body httpdto.ReanalyzeCourseRequest true "Assignments of the course"
+// @Param        stale_only query bool false "Only works whose reports are stale or missing"
+// @Success      200 {object} httpdto.APIResponse{data=service.ReanalysisResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/reanalyze [post]
+func (h *ReanalysisHandler) ReanalyzeCourse(c *gin.Context) {
+	var req httpdto.ReanalyzeCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid request body", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	staleOnly, ok := parseStaleOnly(c)
+	if !ok {
+		return
+	}
+
+	p := middleware.PrincipalFrom(c)
+	ids := make([]uuid.UUID, len(req.AssignmentIDs))
+	for i, raw := range req.AssignmentIDs {
+		// binding:"dive,uuid" уже проверил формат.
+		ids[i] = uuid.MustParse(raw)
+		if !p.TeachesAssignment(ids[i]) {
+			resp := httpdto.NewErrorResponse("FORBIDDEN", "No access to this assignment", raw)
+			c.JSON(http.StatusForbidden, resp)
+			return
+		}
+	}
+	middleware.SetAuditResource(c, strings.Join(req.AssignmentIDs, ","))
+
+	result, err := h.reanalysisService.ReanalyzeAssignments(c.Request.Context(), ids, staleOnly)
+	if err != nil {
+		respondReanalysisError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+func parseStaleOnly(c *gin.Context) (bool, bool) {
+	raw := c.Query("stale_only")
+	if raw == "" {
+		return false, true
+	}
+	staleOnly, err := strconv.ParseBool(raw)
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid stale_only value", raw)
+		c.JSON(http.StatusBadRequest, resp)
+		return false, false
+	}
+	return staleOnly, true
+}
+
+func respondReanalysisError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared.ErrNotFound):
+		c.JSON(http.StatusNotFound, httpdto.NewErrorResponse("NOT_FOUND", "Work not found", ""))
+	case errors.Is(err, shared.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid reanalysis request", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to reanalyze reports", err.Error()))
+	}
+}
@@ -1,14 +1,21 @@
 package handler
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/review"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/export"
 	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
 )
 
 type ReportHandler struct {
@@ -23,11 +30,15 @@ func NewReportHandler(rs *service.ReportService) *ReportHandler {
 
 // GetReport godoc
 // @Summary      Get plagiarism report for a work
-// @Description  Retrieve the plagiarism check report for a specific work
+// @Description  Retrieve the plagiarism check report for a specific work. The author of the work gets a summary without matched works and passages
 // @Tags         reports
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        work_id path string true "Work ID (UUID)"
 // @Success      200 {object} httpdto.APIResponse
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
 // @Failure      404 {object} httpdto.APIResponse
 // @Router       /api/v1/works/{work_id}/reports [get]
 func (h *ReportHandler) GetReport(c *gin.Context) {
@@ -52,18 +63,26 @@ func (h *ReportHandler) GetReport(c *gin.Context) {
 		return
 	}
 
+	if middleware.WorkAccessFrom(c) < auth.AccessFull {
+		c.JSON(http.StatusOK, httpdto.NewSuccessResponse(report.Summary()))
+		return
+	}
+
 	resp := httpdto.NewSuccessResponse(report)
 	c.JSON(http.StatusOK, resp)
 }
 
 // GetAssignmentReports godoc
 // @Summary      Get all plagiarism reports for an assignment
-// @Description  Retrieve plagiarism reports for all works in an assignment
+// @Description  Retrieve plagiarism reports for all works in an assignment, optionally filtered by review state
 // @Tags         reports
 // @Produce      json
 // @Param        assignment_id query string true "Assignment ID (UUID)"
+// @Param        review_state query string false "unreviewed, assigned, confirmed, dismissed or needs_discussion"
 // @Success      200 {object} httpdto.APIResponse
 // @Failure      400 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /api/v1/reports [get]
 func (h *ReportHandler) GetAssignmentReports(c *gin.Context) {
 	assignmentIDStr := c.Query("assignment_id")
@@ -80,7 +99,16 @@ func (h *ReportHandler) GetAssignmentReports(c *gin.Context) {
 		return
 	}
 
-	reports, err := h.reportService.GetReportsByAssignmentID(c.Request.Context(), assignmentID)
+	var state review.State
+	if raw := c.Query("review_state"); raw != "" {
+		if state, err = review.ParseState(raw); err != nil {
+			resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Unknown review_state", raw)
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+	}
+
+	reports, err := h.reportService.GetReportsByAssignmentID(c.Request.Context(), assignmentID, state)
 	if err != nil {
 		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to get reports", err.Error())
 		c.JSON(http.StatusInternalServerError, resp)
@@ -90,3 +118,167 @@ func (h *ReportHandler) GetAssignmentReports(c *gin.Context) {
 	resp := httpdto.NewSuccessResponse(reports)
 	c.JSON(http.StatusOK, resp)
 }
+
+var gradebookColumns = []string{
+	"student_id", "work_id", "submitted_at", "max_score", "coverage", "matched_work_id", "status",
+}
+
+// ExportAssignmentReports godoc
+// @Summary      Export assignment reports for a gradebook
+// @Description  Stream one row per work (student, score, coverage, matched work, status) as CSV or XLSX
+// @Tags         reports
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        assignment_id path string true "Assignment ID (UUID)"
+// @Param        format query string false "csv (default) or xlsx"
+// @Success      200 {file} file "Gradebook export"
+// @Failure      400 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/assignments/{assignment_id}/reports/export [get]
+func (h *ReportHandler) ExportAssignmentReports(c *gin.Context) {
+	assignmentID, err := uuid.Parse(c.Param("assignment_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid assignment_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "format must be csv or xlsx", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	reports, err := h.reportService.ExportAssignmentReports(c.Request.Context(), assignmentID)
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to get reports", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	filename := "reports-" + assignmentID.String() + "." + format
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+
+	var table export.TableWriter
+	if format == "xlsx" {
+		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		c.Status(http.StatusOK)
+		table, err = export.NewXLSXTable(c.Writer, "Reports")
+	} else {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		table = export.NewCSVTable(c.Writer)
+	}
+
+	// Заголовки уже отправлены, поэтому ошибки записи можно только залогировать
+	if err == nil {
+		err = table.WriteHeader(gradebookColumns)
+	}
+	for _, r := range reports {
+		if err != nil {
+			break
+		}
+		var matched interface{}
+		if r.MatchedWorkID != nil {
+			matched = r.MatchedWorkID.String()
+		}
+		var score, coverage interface{}
+		if r.Status != service.ReportStatusPending && r.Status != service.ReportStatusInsufficient {
+			score, coverage = r.SimilarityScore, r.Details.Coverage
+		}
+		err = table.WriteRow([]interface{}{
+			r.StudentID.String(), r.WorkID.String(), r.SubmittedAt, score, coverage, matched, r.Status,
+		})
+	}
+	if err == nil {
+		err = table.Close()
+	}
+	if err != nil {
+		log.Printf("Failed to stream export for assignment %s: %v", assignmentID, err)
+	}
+}
+
+// GetReportHistory godoc
+// @Summary      Report history of a work
+// @Description  All analysis runs of a work, newest first, with the policy each was computed with, whether it is stale, and the matched works with their submission order (earlier or later than this work)
+// @Tags         reports
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Success      200 {object} httpdto.APIResponse{data=[]service.ReportRevision}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/reports/history [get]
+func (h *ReportHandler) GetReportHistory(c *gin.Context) {
+	workID, ok := parseWorkID(c)
+	if !ok {
+		return
+	}
+
+	history, err := h.reportService.GetReportHistory(c.Request.Context(), workID)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(history))
+}
+
+// DiffReports godoc
+// @Summary      Diff two analysis runs of a work
+// @Description  Matches that appeared, disappeared or changed score between two report revisions. Defaults to the previous and the latest revision
+// @Tags         reports
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        from query int false "Older revision (default: the one before to)"
+// @Param        to query int false "Newer revision (default: latest)"
+// @Success      200 {object} httpdto.APIResponse{data=plagiarism.ReportDiff}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/reports/diff [get]
+func (h *ReportHandler) DiffReports(c *gin.Context) {
+	workID, ok := parseWorkID(c)
+	if !ok {
+		return
+	}
+	var revisions [2]int
+	for i, name := range []string{"from", "to"} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		rev, err := strconv.Atoi(raw)
+		if err != nil || rev < 1 {
+			resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid "+name+" revision", raw)
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		revisions[i] = rev
+	}
+
+	diff, err := h.reportService.DiffReports(c.Request.Context(), workID, revisions[0], revisions[1])
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(diff))
+}
+
+func respondReportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared.ErrNotFound):
+		c.JSON(http.StatusNotFound, httpdto.NewErrorResponse("NOT_FOUND", "Report not found", err.Error()))
+	case errors.Is(err, shared.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid report request", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to get report", err.Error()))
+	}
+}
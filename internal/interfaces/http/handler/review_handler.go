@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+)
+
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+}
+
+func NewReviewHandler(rs *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: rs}
+}
+
+// GetReview godoc
+// @Summary      Get teacher review of a report
+// @Description  Review state, assignee, verdict and full history next to the automatic result of the latest report
+// @Tags         reviews
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Success      200 {object} httpdto.APIResponse{data=service.ReviewResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/review [get]
+func (h *ReviewHandler) GetReview(c *gin.Context) {
+	workID, ok := parseWorkID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.reviewService.GetReview(c.Request.Context(), workID)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// AssignReview godoc
+// @Summary      Assign a reviewer
+// @Description  Assign a teacher to review the latest report of a work; reassigning is recorded in the history
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        request body httpdto.AssignReviewRequest true "Reviewer"
+// @Success      200 {object} httpdto.APIResponse{data=service.ReviewResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/review/assign [post]
+func (h *ReviewHandler) AssignReview(c *gin.Context) {
+	workID, ok := parseWorkID(c)
+	if !ok {
+		return
+	}
+	var req httpdto.AssignReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid request body", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	actor := middleware.PrincipalFrom(c).UserID
+	result, err := h.reviewService.Assign(c.Request.Context(), workID, actor, uuid.MustParse(req.AssigneeID))
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// CommentReview godoc
+// @Summary      Comment on a review
+// @Description  Add a comment to the review history without changing its state
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        request body httpdto.ReviewCommentRequest true "Comment"
+// @Success      200 {object} httpdto.APIResponse{data=service.ReviewResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/review/comments [post]
+func (h *ReviewHandler) CommentReview(c *gin.Context) {
+	workID, ok := parseWorkID(c)
+	if !ok {
+		return
+	}
+	var req httpdto.ReviewCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid request body", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	actor := middleware.PrincipalFrom(c).UserID
+	result, err := h.reviewService.Comment(c.Request.Context(), workID, actor, req.Comment)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+// SetVerdict godoc
+// @Summary      Set review verdict
+// @Description  Confirm, dismiss or mark the report for discussion. The automatic result is kept; a verdict that disagrees with it is shown as an override and recorded in the audit log
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        request body httpdto.ReviewVerdictRequest true "Verdict (confirmed, dismissed, needs_discussion) and optional comment"
+// @Success      200 {object} httpdto.APIResponse{data=service.ReviewResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/review/verdict [post]
+func (h *ReviewHandler) SetVerdict(c *gin.Context) {
+	workID, ok := parseWorkID(c)
+	if !ok {
+		return
+	}
+	var req httpdto.ReviewVerdictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid request body", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	actor := middleware.PrincipalFrom(c).UserID
+	result, err := h.reviewService.SetVerdict(c.Request.Context(), workID, actor, req.Verdict, req.Comment)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	middleware.AddAuditDetail(c, "verdict", req.Verdict)
+	middleware.AddAuditDetail(c, "overrides_automatic", strconv.FormatBool(result.OverridesAutomatic))
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+}
+
+func parseWorkID(c *gin.Context) (uuid.UUID, bool) {
+	workID, err := uuid.Parse(c.Param("work_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid work_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return uuid.Nil, false
+	}
+	return workID, true
+}
+
+func respondReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared.ErrNotFound):
+		c.JSON(http.StatusNotFound, httpdto.NewErrorResponse("NOT_FOUND", "Report not found", ""))
+	case errors.Is(err, shared.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid review action", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to update review", err.Error()))
+	}
+}
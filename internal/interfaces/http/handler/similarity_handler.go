@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/export"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+)
+
+// pdfTopMatches — сколько самых похожих работ попадает в PDF-отчет.
+const pdfTopMatches = 5
+
+type SimilarityHandler struct {
+	similarityService *service.SimilarityService
+}
+
+func NewSimilarityHandler(ss *service.SimilarityService) *SimilarityHandler {
+	return &SimilarityHandler{
+		similarityService: ss,
+	}
+}
+
+// GetAssignmentMatrix godoc
+// @Summary      Pairwise similarity matrix for an assignment
+// @Description  Compute the full N×N similarity matrix and group works that share content
+// @Tags         reports
+// @Produce      json
+// @Produce      text/csv
+// @Produce      text/vnd.graphviz
+// @Param        assignment_id path string true "Assignment ID (UUID)"
+// @Param        threshold query number false "Clustering threshold (defaults to the similarity threshold)"
+// @Param        format query string false "json (default), csv or dot"
+// @Success      200 {object} httpdto.APIResponse{data=service.SimilarityMatrixResponse}
+// @Failure      400 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/assignments/{assignment_id}/similarity [get]
+func (h *SimilarityHandler) GetAssignmentMatrix(c *gin.Context) {
+	assignmentID, err := uuid.Parse(c.Param("assignment_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid assignment_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	threshold := 0.0
+	if raw := c.Query("threshold"); raw != "" {
+		threshold, err = strconv.ParseFloat(raw, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "threshold must be a number in (0, 1]", "")
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" && format != "dot" {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "format must be one of json, csv, dot", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	result, err := h.similarityService.GetAssignmentMatrix(c.Request.Context(), assignmentID, threshold)
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to compute similarity matrix", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	var buf bytes.Buffer
+	var contentType string
+	switch format {
+	case "csv":
+		err = export.WriteMatrixCSV(&buf, result.SimilarityMatrix())
+		c.Header("Content-Disposition", "attachment; filename=similarity-"+assignmentID.String()+".csv")
+		contentType = "text/csv"
+	case "dot":
+		err = export.WriteMatrixDOT(&buf, result.SimilarityMatrix(), result.Threshold, result.Labels())
+		c.Header("Content-Disposition", "attachment; filename=similarity-"+assignmentID.String()+".dot")
+		contentType = "text/vnd.graphviz"
+	default:
+		c.JSON(http.StatusOK, httpdto.NewSuccessResponse(result))
+		return
+	}
+
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to export similarity matrix", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+	c.Data(http.StatusOK, contentType+"; charset=utf-8", buf.Bytes())
+}
+
+// CompareWorks godoc
+// @Summary      Side-by-side comparison of two works
+// @Description  Render a self-contained HTML page with both texts and matched passages highlighted
+// @Tags         reports
+// @Produce      html
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        matched_id path string true "Matched work ID (UUID)"
+// @Success      200 {string} string "HTML page"
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/reports/{matched_id}/compare [get]
+func (h *SimilarityHandler) CompareWorks(c *gin.Context) {
+	workID, err := uuid.Parse(c.Param("work_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid work_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	matchedID, err := uuid.Parse(c.Param("matched_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid matched_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	comparison, err := h.similarityService.CompareWorks(c.Request.Context(), workID, matchedID)
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			resp := httpdto.NewErrorResponse("NOT_FOUND", "Work not found", "")
+			c.JSON(http.StatusNotFound, resp)
+			return
+		}
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to compare works", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteComparisonHTML(&buf, comparison); err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to render comparison", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// GetAttribution godoc
+// @Summary      Likely source and copier of a matched pair
+// @Description  Estimate which of two matched works is the source using submission order, context of shared passages and earlier versions by the same authors
+// @Tags         reports
+// @Produce      json
+// @Param        work_id path string true "Work ID (UUID)"
+// @Param        matched_id path string true "Matched work ID (UUID)"
+// @Success      200 {object} httpdto.APIResponse{data=plagiarism.Attribution}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/reports/{matched_id}/attribution [get]
+func (h *SimilarityHandler) GetAttribution(c *gin.Context) {
+	workID, err := uuid.Parse(c.Param("work_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid work_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	matchedID, err := uuid.Parse(c.Param("matched_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid matched_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	if workID == matchedID {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "work_id and matched_id must differ", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	attribution, err := h.similarityService.AttributeMatch(c.Request.Context(), workID, matchedID)
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			resp := httpdto.NewErrorResponse("NOT_FOUND", "Work not found", "")
+			c.JSON(http.StatusNotFound, resp)
+			return
+		}
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to attribute match", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(attribution))
+}
+
+// GetReportPDF godoc
+// @Summary      Printable PDF report for a work
+// @Description  Render the plagiarism report with top matches and highlighted excerpts as PDF; the SHA-256 of the inputs is embedded for later verification
+// @Tags         reports
+// @Produce      application/pdf
+// @Param        work_id path string true "Work ID (UUID)"
+// @Success      200 {file} file "PDF document"
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api/v1/works/{work_id}/reports/pdf [get]
+func (h *SimilarityHandler) GetReportPDF(c *gin.Context) {
+	workID, err := uuid.Parse(c.Param("work_id"))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid work_id format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	evidence, err := h.similarityService.GetEvidence(c.Request.Context(), workID, pdfTopMatches)
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			resp := httpdto.NewErrorResponse("NOT_FOUND", "Report not found", "")
+			c.JSON(http.StatusNotFound, resp)
+			return
+		}
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to collect report data", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteReportPDF(&buf, evidence); err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to render PDF", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=report-"+workID.String()+".pdf")
+	c.Header("X-Input-SHA256", evidence.Digest())
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(s *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: s}
+}
+
+// CreateWebhook godoc
+// @Summary      Create a webhook subscription
+// @Description  Register a URL to receive report.created, report.flagged and review.updated events. Deliveries are signed with HMAC-SHA256 using the secret, which is returned only in this response
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request body service.CreateWebhookRequest true "Name, URL, events, optional secret and assignment"
+// @Success      201 {object} httpdto.APIResponse{data=service.CreatedWebhook}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req service.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid request body", err.Error())
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	hook, err := h.webhookService.Create(c.Request.Context(), req, middleware.PrincipalFrom(c).UserID)
+	if err != nil {
+		if errors.Is(err, shared.ErrInvalidInput) {
+			resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid webhook parameters", err.Error())
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to create webhook", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	middleware.SetAuditResource(c, hook.ID.String())
+	c.JSON(http.StatusCreated, httpdto.NewSuccessResponse(hook))
+}
+
+// ListWebhooks godoc
+// @Summary      List webhook subscriptions
+// @Description  List all subscriptions with their events; secrets are never returned
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} httpdto.APIResponse{data=[]service.WebhookInfo}
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/admin/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	hooks, err := h.webhookService.List(c.Request.Context())
+	if err != nil {
+		resp := httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to list webhooks", err.Error())
+		c.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(hooks))
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete a webhook subscription
+// @Description  Stop sending events to the URL. Pending deliveries are cancelled; the delivery log is kept
+// @Tags         admin
+// @Param        webhook_id path string true "Webhook ID (UUID)"
+// @Success      204
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/admin/webhooks/{webhook_id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	webhookID, ok := parseUUIDParam(c, "webhook_id")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(c.Request.Context(), webhookID); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary      Webhook delivery log
+// @Description  Deliveries of a subscription, newest first: status, attempts, last response and the next retry time
+// @Tags         admin
+// @Produce      json
+// @Param        webhook_id path string true "Webhook ID (UUID)"
+// @Param        limit query int false "Page size (default 50, max 500)"
+// @Success      200 {object} httpdto.APIResponse{data=[]service.WebhookDeliveryInfo}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/admin/webhooks/{webhook_id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	webhookID, ok := parseUUIDParam(c, "webhook_id")
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit", 50, 1, 500)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.webhookService.Deliveries(c.Request.Context(), webhookID, limit)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, httpdto.NewSuccessResponse(deliveries))
+}
+
+// ReplayWebhookDelivery godoc
+// @Summary      Replay a webhook delivery
+// @Description  Queue the same event again as a new delivery, e.g. after the receiver was fixed
+// @Tags         admin
+// @Produce      json
+// @Param        webhook_id path string true "Webhook ID (UUID)"
+// @Param        delivery_id path string true "Delivery ID (UUID)"
+// @Success      202 {object} httpdto.APIResponse{data=service.WebhookDeliveryInfo}
+// @Failure      400 {object} httpdto.APIResponse
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
+// @Failure      404 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Router       /api/v1/admin/webhooks/{webhook_id}/deliveries/{delivery_id}/replay [post]
+func (h *WebhookHandler) ReplayWebhookDelivery(c *gin.Context) {
+	webhookID, ok := parseUUIDParam(c, "webhook_id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseUUIDParam(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.Replay(c.Request.Context(), webhookID, deliveryID)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, httpdto.NewSuccessResponse(delivery))
+}
+
+func parseUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid "+name+" format", "")
+		c.JSON(http.StatusBadRequest, resp)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared.ErrNotFound):
+		c.JSON(http.StatusNotFound, httpdto.NewErrorResponse("NOT_FOUND", "Webhook or delivery not found", ""))
+	case errors.Is(err, shared.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, httpdto.NewErrorResponse("VALIDATION_ERROR", "Invalid webhook request", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, httpdto.NewErrorResponse("INTERNAL_ERROR", "Failed to process webhook request", err.Error()))
+	}
+}
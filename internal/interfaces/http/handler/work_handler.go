@@ -5,10 +5,12 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/dto"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
 	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
 )
 
 type WorkHandler struct {
@@ -34,7 +36,11 @@ func NewWorkHandler(ss *service.SubmissionService, maxFileSize int64) *WorkHandl
 // @Param        file formData file true "Work file (TXT or MD)"
 // @Success      202 {object} httpdto.APIResponse{data=dto.SubmitWorkResponse}
 // @Failure      400 {object} httpdto.APIResponse
+// @Failure      401 {object} httpdto.APIResponse
+// @Failure      403 {object} httpdto.APIResponse
 // @Failure      413 {object} httpdto.APIResponse
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /api/v1/works [post]
 func (h *WorkHandler) SubmitWork(c *gin.Context) {
 	var req httpdto.SubmitWorkRequest
@@ -44,6 +50,15 @@ func (h *WorkHandler) SubmitWork(c *gin.Context) {
 		return
 	}
 
+	// binding:"uuid" уже проверил формат, ошибки разбора здесь быть не может.
+	studentID, _ := uuid.Parse(req.StudentID)
+	assignmentID, _ := uuid.Parse(req.AssignmentID)
+	if p := middleware.PrincipalFrom(c); p == nil || !p.CanSubmitAs(studentID, assignmentID) {
+		resp := httpdto.NewErrorResponse("FORBIDDEN", "Cannot submit work on behalf of another student", "")
+		c.JSON(http.StatusForbidden, resp)
+		return
+	}
+
 	fileHeader, err := c.FormFile("file")
 	if err != nil {
 		resp := httpdto.NewErrorResponse("VALIDATION_ERROR", "Missing file or invalid multipart form", err.Error())
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/audit"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+)
+
+const (
+	auditResourceKey = "audit.resource_id"
+	auditDetailsKey  = "audit.details"
+)
+
+// AuditRecorder сохраняет запись аудита от имени пользователя запроса.
+type AuditRecorder func(ctx context.Context, p *auth.Principal, requestID string, ev audit.Event) error
+
+// Audit записывает успешные (2xx) обращения к ресурсу. Идентификатор ресурса — значение
+// первого параметра (из пути или query) или заданное обработчиком через SetAuditResource;
+// остальные параметры и format попадают в details.
+// Просмотр с format, отличным от json, записывается как выгрузка.
+func Audit(record AuditRecorder, action audit.Action, resourceType string, params ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Status() < http.StatusOK || c.Writer.Status() >= http.StatusMultipleChoices {
+			return
+		}
+		p := PrincipalFrom(c)
+		if p == nil {
+			return
+		}
+
+		ev := audit.Event{Action: action, ResourceType: resourceType, Details: map[string]string{}}
+		for i, param := range params {
+			value := c.Param(param)
+			if value == "" {
+				value = c.Query(param)
+			}
+			if i == 0 {
+				ev.ResourceID = value
+			} else {
+				ev.Details[param] = value
+			}
+		}
+		if id := c.GetString(auditResourceKey); id != "" {
+			ev.ResourceID = id
+		}
+		for k, v := range c.GetStringMapString(auditDetailsKey) {
+			ev.Details[k] = v
+		}
+		if format := c.Query("format"); format != "" {
+			ev.Details["format"] = format
+			if action == audit.ActionReportView && format != "json" {
+				ev.Action = audit.ActionReportExport
+			}
+		}
+
+		// Ответ уже отправлен: ошибку записи можно только залогировать.
+		if err := record(c.Request.Context(), p, RequestIDFrom(c), ev); err != nil {
+			log.Printf("Failed to record audit entry %s %s/%s: %v", ev.Action, ev.ResourceType, ev.ResourceID, err)
+		}
+	}
+}
+
+// SetAuditResource задает идентификатор ресурса, который стал известен только в обработчике
+// (например, только что созданного).
+func SetAuditResource(c *gin.Context, id string) {
+	c.Set(auditResourceKey, id)
+}
+
+// AddAuditDetail добавляет в запись аудита подробность, известную только обработчику.
+func AddAuditDetail(c *gin.Context, key, value string) {
+	details := c.GetStringMapString(auditDetailsKey)
+	if details == nil {
+		details = map[string]string{}
+		c.Set(auditDetailsKey, details)
+	}
+	details[key] = value
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/audit"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+)
+
+type recordedEvent struct {
+	principal *auth.Principal
+	requestID string
+	event     audit.Event
+}
+
+func newAuditEngine(p *auth.Principal, recorded *[]recordedEvent) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	record := func(_ context.Context, p *auth.Principal, requestID string, ev audit.Event) error {
+		*recorded = append(*recorded, recordedEvent{p, requestID, ev})
+		return nil
+	}
+
+	engine := gin.New()
+	engine.Use(RequestID(), func(c *gin.Context) {
+		c.Set(principalKey, p)
+		c.Next()
+	})
+	engine.GET("/works/:work_id/reports/:matched_id", Audit(record, audit.ActionReportView, "work", "work_id", "matched_id"), func(c *gin.Context) {
+		if c.Param("work_id") == "missing" {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	engine.POST("/keys", Audit(record, audit.ActionAPIKeyCreate, "api_key"), func(c *gin.Context) {
+		SetAuditResource(c, "created-key")
+		c.Status(http.StatusCreated)
+	})
+	return engine
+}
+
+func TestAudit_RecordsSuccessfulAccess(t *testing.T) {
+	p := &auth.Principal{UserID: uuid.New(), Role: auth.RoleTeacher}
+	var recorded []recordedEvent
+	engine := newAuditEngine(p, &recorded)
+
+	req := httptest.NewRequest(http.MethodGet, "/works/w1/reports/w2", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	require.Len(t, recorded, 1)
+	assert.Same(t, p, recorded[0].principal)
+	assert.Equal(t, "req-42", recorded[0].requestID)
+	assert.Equal(t, "req-42", w.Header().Get(RequestIDHeader))
+	assert.Equal(t, audit.Event{
+		Action: audit.ActionReportView, ResourceType: "work", ResourceID: "w1",
+		Details: map[string]string{"matched_id": "w2"},
+	}, recorded[0].event)
+}
+
+func TestAudit_ExportAndFailures(t *testing.T) {
+	var recorded []recordedEvent
+	engine := newAuditEngine(&auth.Principal{UserID: uuid.New(), Role: auth.RoleAdmin}, &recorded)
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/works/w1/reports/w2?format=csv", nil))
+	require.Len(t, recorded, 1)
+	assert.Equal(t, audit.ActionReportExport, recorded[0].event.Action)
+	assert.Equal(t, "csv", recorded[0].event.Details["format"])
+	assert.NotEmpty(t, recorded[0].requestID, "без заголовка идентификатор создается")
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/works/missing/reports/w2", nil))
+	assert.Len(t, recorded, 1, "неуспешные запросы не записываются")
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/keys", nil))
+	require.Len(t, recorded, 2)
+	assert.Equal(t, "created-key", recorded[1].event.ResourceID)
+}
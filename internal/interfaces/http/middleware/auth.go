@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/apikey"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/jwt"
+	httpdto "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/dto"
+)
+
+const (
+	principalKey  = "auth.principal"
+	workAccessKey = "auth.work_access"
+	bearerPrefix  = "Bearer "
+
+	// APIKeyHeader — заголовок с API-ключом для неинтерактивных клиентов (LMS, скрипты).
+	APIKeyHeader = "X-API-Key"
+)
+
+// KeyAuthenticator проверяет API-ключ и возвращает пользователя, от имени которого он действует.
+type KeyAuthenticator func(ctx context.Context, token string) (*auth.Principal, error)
+
+// WorkLookup загружает работу, чтобы проверить доступ к ней по автору и заданию.
+type WorkLookup func(ctx context.Context, workID uuid.UUID) (*work.Work, error)
+
+// Auth проверяет API-ключ из X-API-Key (если keys задан) или JWT из заголовка Authorization
+// и кладет пользователя в контекст запроса.
+func Auth(verifier *jwt.Verifier, keys KeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.GetHeader(APIKeyHeader); token != "" && keys != nil {
+			principal, err := keys(c.Request.Context(), token)
+			if err != nil {
+				if errors.Is(err, apikey.ErrInvalidKey) || errors.Is(err, apikey.ErrRevoked) || errors.Is(err, apikey.ErrExpired) {
+					abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid API key", err.Error())
+					return
+				}
+				abort(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to check API key", err.Error())
+				return
+			}
+			c.Set(principalKey, principal)
+			c.Next()
+			return
+		}
+
+		header := c.GetHeader("Authorization")
+		if !strings.HasPrefix(header, bearerPrefix) {
+			abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "Missing bearer token", "")
+			return
+		}
+
+		claims, err := verifier.Verify(strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix)))
+		if err != nil {
+			abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid token", err.Error())
+			return
+		}
+		principal, err := claims.Principal()
+		if err != nil {
+			abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid token", err.Error())
+			return
+		}
+
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// RequireRole пропускает только пользователей с одной из перечисленных ролей.
+func RequireRole(roles ...auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := PrincipalFrom(c)
+		for _, r := range roles {
+			if p != nil && p.Role == r {
+				c.Next()
+				return
+			}
+		}
+		abort(c, http.StatusForbidden, "FORBIDDEN", "Insufficient role", "")
+	}
+}
+
+// RequireScope пропускает API-ключи только с нужной областью; на пользователей с JWT не влияет.
+func RequireScope(scope auth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p := PrincipalFrom(c); p == nil || !p.HasScope(scope) {
+			abort(c, http.StatusForbidden, "FORBIDDEN", "API key lacks scope "+string(scope), "")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAssignmentAccess пропускает преподавателей задания и администраторов.
+// Идентификатор задания берется из параметра пути или, если его нет, из query.
+func RequireAssignmentAccess(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.Param(param)
+		if raw == "" {
+			raw = c.Query(param)
+		}
+		assignmentID, err := uuid.Parse(raw)
+		if err != nil {
+			abort(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid "+param+" format", "")
+			return
+		}
+
+		p := PrincipalFrom(c)
+		if p == nil || !p.TeachesAssignment(assignmentID) {
+			abort(c, http.StatusForbidden, "FORBIDDEN", "No access to this assignment", "")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireWorkAccess проверяет доступ ко всем работам из перечисленных параметров пути
+// и пропускает запрос, если уровень доступа не ниже min. Итоговый (минимальный) уровень
+// сохраняется в контексте, его читает WorkAccessFrom.
+func RequireWorkAccess(lookup WorkLookup, min auth.AccessLevel, params ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := PrincipalFrom(c)
+		if p == nil {
+			abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "Missing bearer token", "")
+			return
+		}
+
+		level := auth.AccessFull
+		for _, param := range params {
+			workID, err := uuid.Parse(c.Param(param))
+			if err != nil {
+				abort(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid "+param+" format", "")
+				return
+			}
+
+			w, err := lookup(c.Request.Context(), workID)
+			if err != nil {
+				if errors.Is(err, shared.ErrNotFound) {
+					abort(c, http.StatusNotFound, "NOT_FOUND", "Work not found", "")
+					return
+				}
+				abort(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to check access", err.Error())
+				return
+			}
+
+			if l := p.WorkAccess(w.StudentID, w.AssignmentID); l < level {
+				level = l
+			}
+		}
+
+		if level < min || level == auth.AccessNone {
+			abort(c, http.StatusForbidden, "FORBIDDEN", "No access to this work", "")
+			return
+		}
+		c.Set(workAccessKey, level)
+		c.Next()
+	}
+}
+
+// SetPrincipal кладет пользователя в контекст для сервисов со своей проверкой входа (шлюз).
+func SetPrincipal(c *gin.Context, p *auth.Principal) {
+	c.Set(principalKey, p)
+}
+
+// PrincipalFrom возвращает пользователя, установленного Auth, или nil.
+func PrincipalFrom(c *gin.Context) *auth.Principal {
+	if v, ok := c.Get(principalKey); ok {
+		if p, ok := v.(*auth.Principal); ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// WorkAccessFrom возвращает уровень доступа, установленный RequireWorkAccess.
+func WorkAccessFrom(c *gin.Context) auth.AccessLevel {
+	if v, ok := c.Get(workAccessKey); ok {
+		if l, ok := v.(auth.AccessLevel); ok {
+			return l
+		}
+	}
+	return auth.AccessNone
+}
+
+func abort(c *gin.Context, status int, code, message, details string) {
+	c.AbortWithStatusJSON(status, httpdto.NewErrorResponse(code, message, details))
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/apikey"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/jwt"
+)
+
+type authFixture struct {
+	signer     *jwt.Signer
+	engine     *gin.Engine
+	assignment uuid.UUID
+	student    uuid.UUID
+	workID     uuid.UUID
+	keys       map[string]*auth.Principal
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	gin.SetMode(gin.TestMode)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	f := &authFixture{
+		signer:     jwt.NewRSASigner(key),
+		engine:     gin.New(),
+		assignment: uuid.New(),
+		student:    uuid.New(),
+		workID:     uuid.New(),
+		keys:       map[string]*auth.Principal{},
+	}
+
+	lookup := func(_ context.Context, id uuid.UUID) (*work.Work, error) {
+		if id != f.workID {
+			return nil, shared.ErrNotFound
+		}
+		return &work.Work{ID: f.workID, AssignmentID: f.assignment, StudentID: f.student}, nil
+	}
+
+	keys := func(_ context.Context, token string) (*auth.Principal, error) {
+		if p, ok := f.keys[token]; ok {
+			return p, nil
+		}
+		return nil, apikey.ErrInvalidKey
+	}
+
+	ok := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"access": WorkAccessFrom(c)})
+	}
+	api := f.engine.Group("/", Auth(jwt.NewRSAVerifier(&key.PublicKey, ""), keys))
+	api.GET("/works/:work_id/reports", RequireWorkAccess(lookup, auth.AccessSummary, "work_id"), ok)
+	api.GET("/works/:work_id/pdf", RequireWorkAccess(lookup, auth.AccessFull, "work_id"), ok)
+	api.GET("/assignments/:assignment_id", RequireAssignmentAccess("assignment_id"), ok)
+	api.GET("/admin", RequireRole(auth.RoleAdmin), RequireScope(auth.ScopeAdmin), ok)
+	api.GET("/reports/:work_id", RequireScope(auth.ScopeReportsRead), RequireWorkAccess(lookup, auth.AccessFull, "work_id"), ok)
+
+	return f
+}
+
+func (f *authFixture) token(t *testing.T, userID uuid.UUID, role auth.Role, assignments ...uuid.UUID) string {
+	claims := jwt.Claims{
+		Subject:   userID.String(),
+		Role:      string(role),
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}
+	for _, a := range assignments {
+		claims.Assignments = append(claims.Assignments, a.String())
+	}
+	token, err := f.signer.Sign(claims)
+	require.NoError(t, err)
+	return token
+}
+
+func (f *authFixture) get(path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return f.serve(req)
+}
+
+func (f *authFixture) getWithKey(path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set(APIKeyHeader, key)
+	return f.serve(req)
+}
+
+func (f *authFixture) serve(req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	f.engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuth_RejectsMissingAndForeignTokens(t *testing.T) {
+	f := newAuthFixture(t)
+	path := "/works/" + f.workID.String() + "/reports"
+
+	assert.Equal(t, http.StatusUnauthorized, f.get(path, "").Code)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	forged, err := jwt.NewRSASigner(otherKey).Sign(jwt.Claims{
+		Subject:   f.student.String(),
+		Role:      string(auth.RoleAdmin),
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, f.get(path, forged).Code)
+}
+
+func TestRequireWorkAccess_StudentSeesOnlyOwnSummary(t *testing.T) {
+	f := newAuthFixture(t)
+	reports := "/works/" + f.workID.String() + "/reports"
+	pdf := "/works/" + f.workID.String() + "/pdf"
+
+	owner := f.token(t, f.student, auth.RoleStudent)
+	w := f.get(reports, owner)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"access":1}`, w.Body.String())
+	assert.Equal(t, http.StatusForbidden, f.get(pdf, owner).Code, "автору недоступен полный отчет")
+
+	stranger := f.token(t, uuid.New(), auth.RoleStudent)
+	assert.Equal(t, http.StatusForbidden, f.get(reports, stranger).Code)
+
+	assert.Equal(t, http.StatusNotFound, f.get("/works/"+uuid.New().String()+"/reports", owner).Code)
+	assert.Equal(t, http.StatusBadRequest, f.get("/works/not-a-uuid/reports", owner).Code)
+}
+
+func TestRequireWorkAccess_TeacherOfAssignment(t *testing.T) {
+	f := newAuthFixture(t)
+	pdf := "/works/" + f.workID.String() + "/pdf"
+
+	teacher := f.token(t, uuid.New(), auth.RoleTeacher, f.assignment)
+	w := f.get(pdf, teacher)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"access":2}`, w.Body.String())
+
+	otherTeacher := f.token(t, uuid.New(), auth.RoleTeacher, uuid.New())
+	assert.Equal(t, http.StatusForbidden, f.get(pdf, otherTeacher).Code)
+
+	admin := f.token(t, uuid.New(), auth.RoleAdmin)
+	assert.Equal(t, http.StatusOK, f.get(pdf, admin).Code)
+}
+
+func TestRequireAssignmentAccessAndRole(t *testing.T) {
+	f := newAuthFixture(t)
+	path := "/assignments/" + f.assignment.String()
+
+	assert.Equal(t, http.StatusOK, f.get(path, f.token(t, uuid.New(), auth.RoleTeacher, f.assignment)).Code)
+	assert.Equal(t, http.StatusForbidden, f.get(path, f.token(t, uuid.New(), auth.RoleTeacher)).Code)
+	assert.Equal(t, http.StatusForbidden, f.get(path, f.token(t, f.student, auth.RoleStudent)).Code)
+
+	assert.Equal(t, http.StatusOK, f.get("/admin", f.token(t, uuid.New(), auth.RoleAdmin)).Code)
+	assert.Equal(t, http.StatusForbidden, f.get("/admin", f.token(t, uuid.New(), auth.RoleTeacher, f.assignment)).Code)
+}
+
+func TestAuth_APIKeyScopes(t *testing.T) {
+	f := newAuthFixture(t)
+	f.keys["reader"] = &auth.Principal{
+		UserID: uuid.New(), Role: auth.RoleTeacher,
+		Assignments: []uuid.UUID{f.assignment}, Scopes: []auth.Scope{auth.ScopeReportsRead},
+	}
+	f.keys["submitter"] = &auth.Principal{
+		UserID: uuid.New(), Role: auth.RoleAdmin, Scopes: []auth.Scope{auth.ScopeWorksSubmit},
+	}
+	path := "/reports/" + f.workID.String()
+
+	assert.Equal(t, http.StatusOK, f.getWithKey(path, "reader").Code)
+	assert.Equal(t, http.StatusForbidden, f.getWithKey(path, "submitter").Code, "нет области reports:read")
+	assert.Equal(t, http.StatusForbidden, f.getWithKey("/admin", "submitter").Code, "ключам не доступно управление")
+	assert.Equal(t, http.StatusUnauthorized, f.getWithKey(path, "unknown").Code)
+
+	admin := f.token(t, uuid.New(), auth.RoleAdmin)
+	assert.Equal(t, http.StatusOK, f.get(path, admin).Code)
+	assert.Equal(t, http.StatusOK, f.get("/admin", admin).Code)
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/signing"
+)
+
+const serviceKey = "auth.service"
+
+// ServiceAuth пропускает только запросы, подписанные ключом доверенного сервиса.
+// Используется на /internal маршрутах, которые не должны быть доступны снаружи.
+func ServiceAuth(verifier *signing.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyID, err := verifier.Verify(c.Request)
+		if err != nil {
+			abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid service signature", err.Error())
+			return
+		}
+		c.Set(serviceKey, keyID)
+		c.Next()
+	}
+}
+
+// ServiceFrom возвращает идентификатор ключа вызывающего сервиса, установленный ServiceAuth.
+func ServiceFrom(c *gin.Context) string {
+	return c.GetString(serviceKey)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/signing"
+)
+
+func newServiceEngine(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	verifier, err := signing.NewVerifier(map[string][]byte{"gateway": []byte("gateway-secret")})
+	require.NoError(t, err)
+
+	engine := gin.New()
+	internal := engine.Group("/internal", ServiceAuth(verifier))
+	internal.POST("/analyze", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.JSON(http.StatusOK, gin.H{"service": ServiceFrom(c), "body": string(body)})
+	})
+	return engine
+}
+
+func TestServiceAuth_RejectsUnsignedRequests(t *testing.T) {
+	engine := newServiceEngine(t)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/internal/analyze", bytes.NewBufferString(`{}`)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	foreign, err := signing.NewSigner("gateway", []byte("guessed-secret"))
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/internal/analyze", bytes.NewBufferString(`{}`))
+	foreign.Sign(req, []byte(`{}`))
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestServiceAuth_AcceptsSignedRequests(t *testing.T) {
+	engine := newServiceEngine(t)
+
+	signer, err := signing.NewSigner("gateway", []byte("gateway-secret"))
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/internal/analyze", bytes.NewBufferString(`{"a":1}`))
+	signer.Sign(req, []byte(`{"a":1}`))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"service":"gateway","body":"{\"a\":1}"}`, w.Body.String())
+}
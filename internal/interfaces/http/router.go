@@ -5,6 +5,9 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/application/service"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/audit"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/jwt"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/handler"
 	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
 )
@@ -14,21 +17,101 @@ func SetupRoutes(
 	db *sqlx.DB,
 	submissionSvc *service.SubmissionService,
 	reportSvc *service.ReportService,
+	reviewSvc *service.ReviewService,
+	appealSvc *service.AppealService,
+	similaritySvc *service.SimilarityService,
+	analyticsSvc *service.AnalyticsService,
+	apiKeySvc *service.APIKeyService,
+	auditSvc *service.AuditService,
+	webhookSvc *service.WebhookService,
+	batchSvc *service.BatchService,
+	reanalysisSvc *service.ReanalysisService,
+	verifier *jwt.Verifier,
 	maxFileSize int64,
 ) {
+	engine.Use(middleware.RequestID())
 	engine.Use(middleware.Logger())
 	engine.Use(middleware.CORS())
 
 	healthHandler := handler.NewHealthHandler(db)
 	engine.GET("/health", healthHandler.Health)
 
-	v1 := engine.Group("/api/v1")
+	// Все эндпоинты API требуют JWT или API-ключ. Права проверяются на уровне маршрута:
+	// по заданию (преподаватель задания, администратор) или по работе (плюс автор работы);
+	// для API-ключей дополнительно проверяется область.
+	v1 := engine.Group("/api/v1", middleware.Auth(verifier, apiKeySvc.Authenticate))
 	{
+		workAccess := func(min auth.AccessLevel, params ...string) gin.HandlerFunc {
+			return middleware.RequireWorkAccess(reportSvc.GetWork, min, params...)
+		}
+		assignmentAccess := middleware.RequireAssignmentAccess("assignment_id")
+		submit := middleware.RequireScope(auth.ScopeWorksSubmit)
+		reports := middleware.RequireScope(auth.ScopeReportsRead)
+		analytics := middleware.RequireScope(auth.ScopeAnalyticsRead)
+		reviews := middleware.RequireScope(auth.ScopeReviewsWrite)
+		// Просмотры и выгрузки отчетов и изменения настроек попадают в журнал аудита.
+		audited := func(action audit.Action, resourceType string, params ...string) gin.HandlerFunc {
+			return middleware.Audit(auditSvc.Record, action, resourceType, params...)
+		}
+
 		workHandler := handler.NewWorkHandler(submissionSvc, maxFileSize)
-		v1.POST("/works", workHandler.SubmitWork)
+		v1.POST("/works", submit, workHandler.SubmitWork)
+		// Пакет загружает преподаватель задания за всех студентов из манифеста.
+		batchHandler := handler.NewBatchHandler(batchSvc)
+		v1.POST("/assignments/:assignment_id/batches", submit, assignmentAccess, batchHandler.CreateBatch)
+		v1.GET("/assignments/:assignment_id/batches/:batch_id", reports, assignmentAccess, batchHandler.GetBatch)
 		reportHandler := handler.NewReportHandler(reportSvc)
-		v1.GET("/works/:work_id/reports", reportHandler.GetReport)
-		v1.GET("/reports", reportHandler.GetAssignmentReports)
+		v1.GET("/works/:work_id/reports", reports, workAccess(auth.AccessSummary, "work_id"), audited(audit.ActionReportView, "work", "work_id"), reportHandler.GetReport)
+		v1.GET("/works/:work_id/reports/history", reports, workAccess(auth.AccessFull, "work_id"), audited(audit.ActionReportView, "work", "work_id"), reportHandler.GetReportHistory)
+		v1.GET("/works/:work_id/reports/diff", reports, workAccess(auth.AccessFull, "work_id"), audited(audit.ActionReportView, "work", "work_id"), reportHandler.DiffReports)
+		v1.GET("/reports", reports, assignmentAccess, audited(audit.ActionReportView, "assignment", "assignment_id"), reportHandler.GetAssignmentReports)
+		v1.GET("/assignments/:assignment_id/reports/export", reports, assignmentAccess, audited(audit.ActionReportExport, "assignment", "assignment_id"), reportHandler.ExportAssignmentReports)
+		similarityHandler := handler.NewSimilarityHandler(similaritySvc)
+		v1.GET("/assignments/:assignment_id/similarity", reports, assignmentAccess, audited(audit.ActionReportView, "assignment", "assignment_id"), similarityHandler.GetAssignmentMatrix)
+		v1.GET("/works/:work_id/reports/:matched_id/compare", reports, workAccess(auth.AccessFull, "work_id", "matched_id"), audited(audit.ActionReportView, "work", "work_id", "matched_id"), similarityHandler.CompareWorks)
+		v1.GET("/works/:work_id/reports/:matched_id/attribution", reports, workAccess(auth.AccessFull, "work_id", "matched_id"), audited(audit.ActionReportView, "work", "work_id", "matched_id"), similarityHandler.GetAttribution)
+		v1.GET("/works/:work_id/reports/pdf", reports, workAccess(auth.AccessFull, "work_id"), audited(audit.ActionReportExport, "work", "work_id"), similarityHandler.GetReportPDF)
+		// Проверку ведут преподаватели задания; автор работы ее не видит.
+		reviewHandler := handler.NewReviewHandler(reviewSvc)
+		v1.GET("/works/:work_id/review", reports, workAccess(auth.AccessFull, "work_id"), reviewHandler.GetReview)
+		v1.POST("/works/:work_id/review/assign", reviews, workAccess(auth.AccessFull, "work_id"), reviewHandler.AssignReview)
+		v1.POST("/works/:work_id/review/comments", reviews, workAccess(auth.AccessFull, "work_id"), reviewHandler.CommentReview)
+		v1.POST("/works/:work_id/review/verdict", reviews, workAccess(auth.AccessFull, "work_id"), audited(audit.ActionOverride, "work", "work_id"), reviewHandler.SetVerdict)
+		// Повторный анализ добавляет ревизии отчетов, поэтому доступен только преподавателям.
+		reanalysisHandler := handler.NewReanalysisHandler(reanalysisSvc)
+		v1.POST("/works/:work_id/reanalyze", reviews, workAccess(auth.AccessFull, "work_id"), audited(audit.ActionReanalyze, "work", "work_id"), reanalysisHandler.ReanalyzeWork)
+		v1.POST("/assignments/:assignment_id/reanalyze", reviews, assignmentAccess, audited(audit.ActionReanalyze, "assignment", "assignment_id"), reanalysisHandler.ReanalyzeAssignment)
+		v1.POST("/reanalyze", reviews, audited(audit.ActionReanalyze, "course"), reanalysisHandler.ReanalyzeCourse)
+		// Апелляцию подает только автор работы; рассматривают преподаватели задания.
+		appealHandler := handler.NewAppealHandler(appealSvc, maxFileSize)
+		v1.POST("/works/:work_id/appeals", middleware.RequireRole(auth.RoleStudent), workAccess(auth.AccessSummary, "work_id"), appealHandler.SubmitAppeal)
+		v1.GET("/works/:work_id/appeals", reports, workAccess(auth.AccessSummary, "work_id"), appealHandler.ListAppeals)
+		v1.GET("/works/:work_id/appeals/:appeal_id", reports, workAccess(auth.AccessSummary, "work_id"), appealHandler.GetAppeal)
+		v1.GET("/works/:work_id/appeals/:appeal_id/evidence/:evidence_id", reports, workAccess(auth.AccessSummary, "work_id"), appealHandler.GetAppealEvidence)
+		v1.POST("/works/:work_id/appeals/:appeal_id/review", reviews, workAccess(auth.AccessFull, "work_id"), appealHandler.StartAppealReview)
+		v1.POST("/works/:work_id/appeals/:appeal_id/decision", reviews, workAccess(auth.AccessFull, "work_id"), audited(audit.ActionAppealDecide, "appeal", "appeal_id"), appealHandler.ResolveAppeal)
+		analyticsHandler := handler.NewAnalyticsHandler(analyticsSvc)
+		v1.GET("/works/:work_id/wordcloud", analytics, workAccess(auth.AccessFull, "work_id"), analyticsHandler.WordCloud)
+		v1.GET("/works/:work_id/stylometry", analytics, workAccess(auth.AccessFull, "work_id"), analyticsHandler.Stylometry)
+		v1.GET("/assignments/:assignment_id/keywords", analytics, assignmentAccess, analyticsHandler.GetAssignmentKeywords)
+		v1.GET("/assignments/:assignment_id/vocabulary", analytics, assignmentAccess, analyticsHandler.GetAssignmentVocabulary)
+		v1.GET("/assignments/:assignment_id/rare-phrases", analytics, assignmentAccess, analyticsHandler.GetRarePhrases)
+
+		// Ключами управляют только администраторы с JWT: ключу область admin не выдается.
+		admin := v1.Group("/admin", middleware.RequireRole(auth.RoleAdmin), middleware.RequireScope(auth.ScopeAdmin))
+		apiKeyHandler := handler.NewAPIKeyHandler(apiKeySvc)
+		admin.POST("/api-keys", audited(audit.ActionAPIKeyCreate, "api_key"), apiKeyHandler.CreateAPIKey)
+		admin.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+		admin.DELETE("/api-keys/:key_id", audited(audit.ActionAPIKeyRevoke, "api_key", "key_id"), apiKeyHandler.RevokeAPIKey)
+		auditHandler := handler.NewAuditHandler(auditSvc)
+		admin.GET("/audit", auditHandler.GetAuditLog)
+		admin.GET("/audit/verify", auditHandler.VerifyAuditLog)
+		webhookHandler := handler.NewWebhookHandler(webhookSvc)
+		admin.POST("/webhooks", audited(audit.ActionWebhookCreate, "webhook"), webhookHandler.CreateWebhook)
+		admin.GET("/webhooks", webhookHandler.ListWebhooks)
+		admin.DELETE("/webhooks/:webhook_id", audited(audit.ActionWebhookDelete, "webhook", "webhook_id"), webhookHandler.DeleteWebhook)
+		admin.GET("/webhooks/:webhook_id/deliveries", webhookHandler.ListWebhookDeliveries)
+		admin.POST("/webhooks/:webhook_id/deliveries/:delivery_id/replay", webhookHandler.ReplayWebhookDelivery)
 	}
 
 }
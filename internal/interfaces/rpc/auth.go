@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/apikey"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/jwt"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/interfaces/http/middleware"
+)
+
+// Вызовы от имени пользователя несут его токен или API-ключ в метаданных, как
+// заголовки HTTP: внутренний сервис сам проверяет доступ к работе.
+
+const (
+	authorizationMD = "authorization"
+	bearerPrefix    = "Bearer "
+)
+
+var (
+	apiKeyMD    = strings.ToLower(middleware.APIKeyHeader)
+	requestIDMD = strings.ToLower(middleware.RequestIDHeader)
+)
+
+// ForwardUser добавляет к исходящему вызову токен или API-ключ пользователя и
+// идентификатор запроса.
+func ForwardUser(ctx context.Context, authorization, apiKey, requestID string) context.Context {
+	var kv []string
+	if authorization != "" {
+		kv = append(kv, authorizationMD, authorization)
+	}
+	if apiKey != "" {
+		kv = append(kv, apiKeyMD, apiKey)
+	}
+	if requestID != "" {
+		kv = append(kv, requestIDMD, requestID)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// Authenticator проверяет пользователя вызова по тем же правилам, что middleware.Auth.
+type Authenticator struct {
+	verifier *jwt.Verifier
+	keys     middleware.KeyAuthenticator
+}
+
+func NewAuthenticator(verifier *jwt.Verifier, keys middleware.KeyAuthenticator) *Authenticator {
+	return &Authenticator{verifier: verifier, keys: keys}
+}
+
+// Principal возвращает пользователя вызова: по API-ключу из x-api-key (если keys задан)
+// или по JWT из authorization. Ошибки — статусы gRPC.
+func (a *Authenticator) Principal(ctx context.Context) (*auth.Principal, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if token := first(md, apiKeyMD); token != "" && a.keys != nil {
+		principal, err := a.keys(ctx, token)
+		if err != nil {
+			if errors.Is(err, apikey.ErrInvalidKey) || errors.Is(err, apikey.ErrRevoked) || errors.Is(err, apikey.ErrExpired) {
+				return nil, status.Error(codes.Unauthenticated, "Invalid API key")
+			}
+			return nil, status.Error(codes.Internal, "Failed to check API key")
+		}
+		return principal, nil
+	}
+
+	header := first(md, authorizationMD)
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return nil, status.Error(codes.Unauthenticated, "Missing bearer token")
+	}
+	claims, err := a.verifier.Verify(strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix)))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "Invalid token")
+	}
+	principal, err := claims.Principal()
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "Invalid token")
+	}
+	return principal, nil
+}
+
+// WorkAccess проверяет пользователя вызова, область API-ключа и доступ к работе не ниже min,
+// как связка middleware.Auth, RequireScope и RequireWorkAccess. Возвращает уровень доступа.
+func (a *Authenticator) WorkAccess(ctx context.Context, lookup middleware.WorkLookup, scope auth.Scope, min auth.AccessLevel, rawWorkID string) (auth.AccessLevel, error) {
+	p, err := a.Principal(ctx)
+	if err != nil {
+		return auth.AccessNone, err
+	}
+	if !p.HasScope(scope) {
+		return auth.AccessNone, status.Error(codes.PermissionDenied, "API key lacks scope "+string(scope))
+	}
+
+	workID, err := uuid.Parse(rawWorkID)
+	if err != nil {
+		return auth.AccessNone, status.Error(codes.InvalidArgument, "Invalid work_id format")
+	}
+	w, err := lookup(ctx, workID)
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			return auth.AccessNone, status.Error(codes.NotFound, "Work not found")
+		}
+		return auth.AccessNone, status.Error(codes.Internal, "Failed to check access")
+	}
+
+	level := p.WorkAccess(w.StudentID, w.AssignmentID)
+	if level < min || level == auth.AccessNone {
+		return auth.AccessNone, status.Error(codes.PermissionDenied, "No access to this work")
+	}
+	return level, nil
+}
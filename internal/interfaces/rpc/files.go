@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	storagev1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/storage/v1"
+)
+
+// ChunkSize — размер части файла в потоках Upload и Download.
+const ChunkSize = 64 << 10
+
+// UploadFile передает файл в Storage Service: сначала описание, затем содержимое частями.
+func UploadFile(ctx context.Context, client storagev1.StorageServiceClient, filename, contentType string, content io.Reader) (*storagev1.UploadResponse, error) {
+	stream, err := client.Upload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info := &storagev1.FileInfo{Filename: filename, ContentType: contentType}
+	if err := stream.Send(&storagev1.UploadRequest{Payload: &storagev1.UploadRequest_Info{Info: info}}); err != nil {
+		return nil, closeUpload(stream, err)
+	}
+
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := content.Read(buf)
+		if n > 0 {
+			// Send может удерживать срез до отправки, поэтому каждая часть копируется.
+			chunk := append([]byte(nil), buf[:n]...)
+			if err := stream.Send(&storagev1.UploadRequest{Payload: &storagev1.UploadRequest_Chunk{Chunk: chunk}}); err != nil {
+				return nil, closeUpload(stream, err)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// closeUpload возвращает настоящую причину обрыва: при ошибке сервера Send отдает io.EOF,
+// а статус приходит из CloseAndRecv.
+func closeUpload(stream storagev1.StorageService_UploadClient, err error) error {
+	if errors.Is(err, io.EOF) {
+		if _, recvErr := stream.CloseAndRecv(); recvErr != nil {
+			return recvErr
+		}
+	}
+	return err
+}
+
+// DownloadFile читает файл из Storage Service целиком.
+func DownloadFile(ctx context.Context, client storagev1.StorageServiceClient, fileID string) (*storagev1.FileInfo, []byte, error) {
+	stream, err := client.Download(ctx, &storagev1.DownloadRequest{FileId: fileID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var info *storagev1.FileInfo
+	var content []byte
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return info, content, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		switch p := msg.Payload.(type) {
+		case *storagev1.DownloadResponse_Info:
+			info = p.Info
+		case *storagev1.DownloadResponse_Chunk:
+			content = append(content, p.Chunk...)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	analysisv1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/analysis/v1"
+	storagev1 "github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/api/proto/storage/v1"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/apikey"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/shared"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/work"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/jwt"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/signing"
+)
+
+// memoryStorage хранит загруженные файлы в памяти.
+type memoryStorage struct {
+	storagev1.UnimplementedStorageServiceServer
+	mu    sync.Mutex
+	files map[string][]byte
+	info  map[string]*storagev1.FileInfo
+}
+
+func (s *memoryStorage) Upload(stream storagev1.StorageService_UploadServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	var content []byte
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		content = append(content, msg.GetChunk()...)
+	}
+
+	id := uuid.NewString()
+	s.mu.Lock()
+	s.files[id] = content
+	s.info[id] = first.GetInfo()
+	s.mu.Unlock()
+	return stream.SendAndClose(&storagev1.UploadResponse{FileId: id, FileName: first.GetInfo().Filename, Size: int64(len(content))})
+}
+
+func (s *memoryStorage) Download(req *storagev1.DownloadRequest, stream storagev1.StorageService_DownloadServer) error {
+	s.mu.Lock()
+	content, ok := s.files[req.FileId]
+	info := s.info[req.FileId]
+	s.mu.Unlock()
+	if !ok {
+		return status.Error(codes.NotFound, "File metadata not found")
+	}
+	if err := stream.Send(&storagev1.DownloadResponse{Payload: &storagev1.DownloadResponse_Info{Info: info}}); err != nil {
+		return err
+	}
+	for len(content) > 0 {
+		n := min(len(content), ChunkSize)
+		if err := stream.Send(&storagev1.DownloadResponse{Payload: &storagev1.DownloadResponse_Chunk{Chunk: content[:n]}}); err != nil {
+			return err
+		}
+		content = content[n:]
+	}
+	return nil
+}
+
+// accessReporter отвечает на GetReport уровнем доступа пользователя вызова.
+type accessReporter struct {
+	analysisv1.UnimplementedAnalysisServiceServer
+	users  *Authenticator
+	lookup func(context.Context, uuid.UUID) (*work.Work, error)
+}
+
+func (a *accessReporter) GetReport(ctx context.Context, req *analysisv1.GetReportRequest) (*analysisv1.GetReportResponse, error) {
+	level, err := a.users.WorkAccess(ctx, a.lookup, auth.ScopeReportsRead, auth.AccessSummary, req.WorkId)
+	if err != nil {
+		return nil, err
+	}
+	if level == auth.AccessFull {
+		return &analysisv1.GetReportResponse{Report: &analysisv1.Report{Id: "full"}}, nil
+	}
+	return &analysisv1.GetReportResponse{Report: &analysisv1.Report{Id: "summary"}}, nil
+}
+
+type rpcFixture struct {
+	jwt        *jwt.Signer
+	signer     *signing.Signer
+	storage    storagev1.StorageServiceClient
+	analysis   analysisv1.AnalysisServiceClient
+	unsigned   *grpc.ClientConn
+	assignment uuid.UUID
+	student    uuid.UUID
+	workID     uuid.UUID
+	keys       map[string]*auth.Principal
+}
+
+func newRPCFixture(t *testing.T) *rpcFixture {
+	serviceKey := []byte("0123456789abcdef0123456789abcdef")
+	verifier, err := signing.NewVerifier(map[string][]byte{"gateway": serviceKey})
+	require.NoError(t, err)
+	signer, err := signing.NewSigner("gateway", serviceKey)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	f := &rpcFixture{
+		jwt:        jwt.NewRSASigner(rsaKey),
+		signer:     signer,
+		assignment: uuid.New(),
+		student:    uuid.New(),
+		workID:     uuid.New(),
+		keys:       map[string]*auth.Principal{},
+	}
+	keys := func(_ context.Context, token string) (*auth.Principal, error) {
+		if p, ok := f.keys[token]; ok {
+			return p, nil
+		}
+		return nil, apikey.ErrInvalidKey
+	}
+	lookup := func(_ context.Context, id uuid.UUID) (*work.Work, error) {
+		if id != f.workID {
+			return nil, shared.ErrNotFound
+		}
+		return &work.Work{ID: f.workID, AssignmentID: f.assignment, StudentID: f.student}, nil
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(verifier)
+	storagev1.RegisterStorageServiceServer(server, &memoryStorage{files: map[string][]byte{}, info: map[string]*storagev1.FileInfo{}})
+	analysisv1.RegisterAnalysisServiceServer(server, &accessReporter{
+		users:  NewAuthenticator(jwt.NewRSAVerifier(&rsaKey.PublicKey, ""), keys),
+		lookup: lookup,
+	})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})
+	conn, err := Dial("passthrough:///bufnet", signer, dialer)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	f.storage = storagev1.NewStorageServiceClient(conn)
+	f.analysis = analysisv1.NewAnalysisServiceClient(conn)
+
+	f.unsigned, err = grpc.NewClient("passthrough:///bufnet", dialer, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { f.unsigned.Close() })
+
+	return f
+}
+
+func (f *rpcFixture) token(t *testing.T, userID uuid.UUID, role auth.Role, assignments ...uuid.UUID) string {
+	claims := jwt.Claims{
+		Subject:   userID.String(),
+		Role:      string(role),
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}
+	for _, a := range assignments {
+		claims.Assignments = append(claims.Assignments, a.String())
+	}
+	token, err := f.jwt.Sign(claims)
+	require.NoError(t, err)
+	return "Bearer " + token
+}
+
+func (f *rpcFixture) report(authorization, apiKey string) (string, codes.Code) {
+	ctx := ForwardUser(context.Background(), authorization, apiKey, "req-1")
+	resp, err := f.analysis.GetReport(ctx, &analysisv1.GetReportRequest{WorkId: f.workID.String()})
+	if err != nil {
+		return "", status.Code(err)
+	}
+	return resp.Report.Id, codes.OK
+}
+
+func TestServiceAuth_RejectsUnsignedCalls(t *testing.T) {
+	f := newRPCFixture(t)
+	ctx := context.Background()
+
+	_, err := analysisv1.NewAnalysisServiceClient(f.unsigned).GetReport(ctx, &analysisv1.GetReportRequest{WorkId: f.workID.String()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := storagev1.NewStorageServiceClient(f.unsigned).Download(ctx, &storagev1.DownloadRequest{FileId: "x"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "потоковый вызов тоже проверяется")
+}
+
+func TestServiceAuth_StreamSignatureCoversRequest(t *testing.T) {
+	f := newRPCFixture(t)
+	ctx := context.Background()
+	uploaded, err := UploadFile(ctx, f.storage, "work.txt", "text/plain", strings.NewReader("текст работы"))
+	require.NoError(t, err)
+
+	// download открывает поток без перехватчика, с подписью тела signed.
+	download := func(signed []byte) codes.Code {
+		keyID, ts, sig := f.signer.SignCall(storagev1.StorageService_Download_FullMethodName, signed)
+		md := metadata.AppendToOutgoingContext(ctx, keyIDMD, keyID, timestampMD, ts, signatureMD, sig)
+		stream, err := storagev1.NewStorageServiceClient(f.unsigned).Download(md, &storagev1.DownloadRequest{FileId: uploaded.FileId})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		return status.Code(err)
+	}
+	body := func(fileID string) []byte {
+		b, err := marshal.Marshal(&storagev1.DownloadRequest{FileId: fileID})
+		require.NoError(t, err)
+		return b
+	}
+
+	assert.Equal(t, codes.OK, download(body(uploaded.FileId)))
+	assert.Equal(t, codes.Unauthenticated, download(nil), "подпись без тела не подходит ни к одному файлу")
+	assert.Equal(t, codes.Unauthenticated, download(body("other")), "подпись чужого запроса не повторить")
+}
+
+func TestUploadDownloadFile_RoundTrip(t *testing.T) {
+	f := newRPCFixture(t)
+	ctx := context.Background()
+
+	content := bytes.Repeat([]byte("антиплагиат "), ChunkSize/4)
+	uploaded, err := UploadFile(ctx, f.storage, "work.txt", "text/plain", bytes.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, "work.txt", uploaded.FileName)
+	assert.Equal(t, int64(len(content)), uploaded.Size)
+
+	info, downloaded, err := DownloadFile(ctx, f.storage, uploaded.FileId)
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", info.ContentType)
+	assert.Equal(t, content, downloaded)
+
+	_, _, err = DownloadFile(ctx, f.storage, "missing")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAuthenticator_WorkAccess(t *testing.T) {
+	f := newRPCFixture(t)
+
+	level, code := f.report(f.token(t, f.student, auth.RoleStudent), "")
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, "summary", level, "автор видит только итог")
+
+	level, code = f.report(f.token(t, uuid.New(), auth.RoleTeacher, f.assignment), "")
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, "full", level)
+
+	_, code = f.report(f.token(t, uuid.New(), auth.RoleStudent), "")
+	assert.Equal(t, codes.PermissionDenied, code, "чужая работа")
+
+	_, code = f.report(f.token(t, uuid.New(), auth.RoleTeacher, uuid.New()), "")
+	assert.Equal(t, codes.PermissionDenied, code, "преподаватель другого задания")
+
+	_, code = f.report("", "")
+	assert.Equal(t, codes.Unauthenticated, code)
+	_, code = f.report("Bearer garbage", "")
+	assert.Equal(t, codes.Unauthenticated, code)
+
+	f.keys["ak_reports"] = &auth.Principal{UserID: uuid.New(), Role: auth.RoleTeacher, Assignments: []uuid.UUID{f.assignment}, Scopes: []auth.Scope{auth.ScopeReportsRead}}
+	f.keys["ak_submit"] = &auth.Principal{UserID: uuid.New(), Role: auth.RoleTeacher, Assignments: []uuid.UUID{f.assignment}, Scopes: []auth.Scope{auth.ScopeWorksSubmit}}
+	level, code = f.report("", "ak_reports")
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, "full", level)
+	_, code = f.report("", "ak_submit")
+	assert.Equal(t, codes.PermissionDenied, code, "ключ без reports:read")
+	_, code = f.report("", "ak_unknown")
+	assert.Equal(t, codes.Unauthenticated, code)
+
+	ctx := ForwardUser(context.Background(), f.token(t, f.student, auth.RoleStudent), "", "")
+	_, err := f.analysis.GetReport(ctx, &analysisv1.GetReportRequest{WorkId: uuid.NewString()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = f.analysis.GetReport(ctx, &analysisv1.GetReportRequest{WorkId: "bad"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,218 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/signing"
+)
+
+// Межсервисные вызовы gRPC подписываются тем же ключом, что и HTTP-запросы
+// (см. signing): заголовки подписи передаются в метаданных. Унарный вызов
+// подписывается вместе с сериализованным запросом, потоковый — вместе с первым
+// сообщением клиента (запросом Download, описанием файла Upload). Иначе подпись,
+// перехваченную в пределах signing.MaxSkew, можно было бы повторить с другим file_id.
+
+var (
+	keyIDMD     = strings.ToLower(signing.HeaderKeyID)
+	timestampMD = strings.ToLower(signing.HeaderTimestamp)
+	signatureMD = strings.ToLower(signing.HeaderSignature)
+)
+
+// marshal сериализует запрос детерминированно, чтобы клиент и сервер подписывали одни байты.
+var marshal = proto.MarshalOptions{Deterministic: true}
+
+// NewServer создает gRPC-сервер, принимающий только подписанные вызовы доверенных сервисов.
+func NewServer(verifier *signing.Verifier, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(ServiceAuthUnary(verifier)),
+		grpc.ChainStreamInterceptor(ServiceAuthStream(verifier)),
+	)
+	return grpc.NewServer(opts...)
+}
+
+// Dial открывает соединение, подписывающее каждый вызов ключом signer. Сеть между
+// сервисами внутренняя, поэтому соединение без TLS.
+func Dial(target string, signer *signing.Signer, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append(opts,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(SignUnary(signer)),
+		grpc.WithChainStreamInterceptor(SignStream(signer)),
+	)
+	return grpc.NewClient(target, opts...)
+}
+
+func ServiceAuthUnary(verifier *signing.Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		body, err := requestBody(req)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if err := verifyCall(ctx, verifier, info.FullMethod, body); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func ServiceAuthStream(verifier *signing.Verifier) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &verifiedStream{ServerStream: ss, verifier: verifier, method: info.FullMethod})
+	}
+}
+
+// verifiedStream проверяет подпись по первому полученному сообщению; до этого обработчик
+// не может ничего отправить.
+type verifiedStream struct {
+	grpc.ServerStream
+	verifier *signing.Verifier
+	method   string
+	verified bool
+}
+
+func (s *verifiedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.verified {
+		return nil
+	}
+	body, err := requestBody(m)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if err := verifyCall(s.Context(), s.verifier, s.method, body); err != nil {
+		return err
+	}
+	s.verified = true
+	return nil
+}
+
+func (s *verifiedStream) SendMsg(m any) error {
+	if !s.verified {
+		return status.Error(codes.Unauthenticated, "stream is not verified")
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+func SignUnary(signer *signing.Signer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		body, err := requestBody(req)
+		if err != nil {
+			return err
+		}
+		return invoker(signCall(ctx, signer, method, body), method, req, reply, cc, opts...)
+	}
+}
+
+func SignStream(signer *signing.Signer) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &signedStream{
+			ctx:    ctx,
+			signer: signer,
+			method: method,
+			open: func(ctx context.Context) (grpc.ClientStream, error) {
+				return streamer(ctx, desc, cc, method, opts...)
+			},
+		}, nil
+	}
+}
+
+// errStreamNotOpened — поток еще не открыт: подпись ждет первого сообщения.
+var errStreamNotOpened = status.Error(codes.FailedPrecondition, "stream is opened by the first message")
+
+// signedStream откладывает открытие потока до первого сообщения, чтобы подписать его
+// так же, как тело унарного вызова: метаданные уходят вместе с открытием.
+type signedStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	signer *signing.Signer
+	method string
+	open   func(ctx context.Context) (grpc.ClientStream, error)
+}
+
+func (s *signedStream) SendMsg(m any) error {
+	if s.ClientStream == nil {
+		body, err := requestBody(m)
+		if err != nil {
+			return err
+		}
+		cs, err := s.open(signCall(s.ctx, s.signer, s.method, body))
+		if err != nil {
+			return err
+		}
+		s.ClientStream = cs
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *signedStream) RecvMsg(m any) error {
+	if s.ClientStream == nil {
+		return errStreamNotOpened
+	}
+	return s.ClientStream.RecvMsg(m)
+}
+
+func (s *signedStream) Header() (metadata.MD, error) {
+	if s.ClientStream == nil {
+		return nil, errStreamNotOpened
+	}
+	return s.ClientStream.Header()
+}
+
+func (s *signedStream) Trailer() metadata.MD {
+	if s.ClientStream == nil {
+		return nil
+	}
+	return s.ClientStream.Trailer()
+}
+
+func (s *signedStream) CloseSend() error {
+	if s.ClientStream == nil {
+		return errStreamNotOpened
+	}
+	return s.ClientStream.CloseSend()
+}
+
+func (s *signedStream) Context() context.Context {
+	if s.ClientStream == nil {
+		return s.ctx
+	}
+	return s.ClientStream.Context()
+}
+
+func signCall(ctx context.Context, signer *signing.Signer, method string, body []byte) context.Context {
+	keyID, ts, sig := signer.SignCall(method, body)
+	return metadata.AppendToOutgoingContext(ctx, keyIDMD, keyID, timestampMD, ts, signatureMD, sig)
+}
+
+func verifyCall(ctx context.Context, verifier *signing.Verifier, method string, body []byte) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if _, err := verifier.VerifyCall(first(md, keyIDMD), first(md, timestampMD), first(md, signatureMD), method, body); err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return nil
+}
+
+func requestBody(req any) ([]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, errors.New("request is not a protobuf message")
+	}
+	return marshal.Marshal(msg)
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
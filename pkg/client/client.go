@@ -0,0 +1,330 @@
+// Package client — типизированный клиент публичного API антиплагиата.
+//
+//	c := client.New("https://antiplague.example.com", client.WithAPIKey(key))
+//	sub, err := c.SubmitWork(ctx, assignmentID, studentID, file, "essay.md")
+//	if errors.Is(err, client.ErrFileTooLarge) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetryPolicy — повторы с экспоненциальной задержкой: BaseDelay, 2×BaseDelay, ... до MaxDelay.
+// Повторяются только GET-запросы при сетевых ошибках и ответах 429, 502, 503 и 504;
+// MaxAttempts считает и первую попытку.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy: 3 попытки в пределах пары секунд.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second}
+}
+
+// Backoff — задержка перед следующей попыткой после attempt неудачных.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	apiKey     string
+	retry      RetryPolicy
+}
+
+type Option func(*Client)
+
+// WithToken передает JWT в заголовке Authorization: Bearer.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithAPIKey передает API-ключ в заголовке X-API-Key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRetry задает политику повторов; MaxAttempts 1 отключает повторы.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// New создает клиент. baseURL — адрес сервиса без /api/v1, например http://localhost:8080.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: time.Minute},
+		retry:      DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SubmitWork загружает работу студента и возвращает результат мгновенной проверки.
+// Запрос не повторяется: если ответ потерян после приема, повтор сдал бы работу дважды.
+func (c *Client) SubmitWork(ctx context.Context, assignmentID, studentID uuid.UUID, content io.Reader, filename string) (*Submission, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("read work content: %w", err)
+	}
+
+	var sub Submission
+	err = c.do(ctx, http.MethodPost, "/api/v1/works", nil, func() (io.Reader, string, error) {
+		return workForm(assignmentID, studentID, filename, data)
+	}, &sub)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// GetReport возвращает отчет по работе.
+func (c *Client) GetReport(ctx context.Context, workID uuid.UUID) (*Report, error) {
+	var report Report
+	if err := c.do(ctx, http.MethodGet, "/api/v1/works/"+workID.String()+"/reports", nil, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ListAssignmentReports возвращает отчеты по проверенным работам задания. Непустой
+// reviewState (ReviewUnreviewed, ReviewConfirmed, ...) оставляет отчеты в этом состоянии.
+func (c *Client) ListAssignmentReports(ctx context.Context, assignmentID uuid.UUID, reviewState string) ([]Report, error) {
+	query := url.Values{"assignment_id": {assignmentID.String()}}
+	if reviewState != "" {
+		query.Set("review_state", reviewState)
+	}
+	var reports []Report
+	if err := c.do(ctx, http.MethodGet, "/api/v1/reports", query, nil, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// WordCloud возвращает облако слов работы в формате FormatSVG или FormatPNG.
+func (c *Client) WordCloud(ctx context.Context, workID uuid.UUID, format string) ([]byte, error) {
+	query := url.Values{"format": {format}}
+	var image []byte
+	if err := c.do(ctx, http.MethodGet, "/api/v1/works/"+workID.String()+"/wordcloud", query, nil, &image); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// body собирает тело запроса заново для каждой попытки.
+type body func() (io.Reader, string, error)
+
+// envelope — общая обертка ответов API. Шлюз отдает error строкой, а не объектом.
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   json.RawMessage `json:"error"`
+}
+
+type errorInfo struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+}
+
+// do выполняет запрос с повторами. Ответ разбирается в out: *[]byte получает тело
+// как есть, остальное — поле data JSON-ответа.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, newBody body, out any) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	// Повторять безопасно только чтение; POST мог быть принят до сбоя.
+	attempts := 1
+	if method == http.MethodGet {
+		attempts = max(c.retry.MaxAttempts, 1)
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, target, newBody)
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			defer resp.Body.Close()
+			return decode(resp, out)
+		}
+
+		var wait time.Duration
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if attempt >= attempts {
+				return fmt.Errorf("%w: %v", ErrUnavailable, err)
+			}
+		} else {
+			if attempt >= attempts {
+				defer resp.Body.Close()
+				return decode(resp, out)
+			}
+			wait = retryAfter(resp)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if wait == 0 {
+			wait = c.retry.Backoff(attempt)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, target string, newBody body) (*http.Response, error) {
+	var reader io.Reader
+	var contentType string
+	if newBody != nil {
+		var err error
+		if reader, contentType, err = newBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	return c.httpClient.Do(req)
+}
+
+func decode(resp *http.Response, out any) error {
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		apiErr := &Error{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		var env envelope
+		if json.Unmarshal(raw, &env) == nil && len(env.Error) > 0 {
+			var info errorInfo
+			var message string
+			switch {
+			case json.Unmarshal(env.Error, &info) == nil:
+				apiErr.Code, apiErr.Message, apiErr.Details = info.Code, info.Message, info.Details
+			case json.Unmarshal(env.Error, &message) == nil && message != "":
+				apiErr.Message = message
+			}
+		}
+		return apiErr
+	}
+
+	if b, ok := out.(*[]byte); ok {
+		*b = raw
+		return nil
+	}
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if len(env.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("decode response data: %w", err)
+	}
+	return nil
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter читает Retry-After в секундах; ноль — заголовка нет.
+func retryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// workForm собирает multipart-форму загрузки. Тип файла выводится из расширения:
+// API принимает только TXT и MD.
+func workForm(assignmentID, studentID uuid.UUID, filename string, data []byte) (io.Reader, string, error) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+	if err := w.WriteField("assignment_id", assignmentID.String()); err != nil {
+		return nil, "", err
+	}
+	if err := w.WriteField("student_id", studentID.String()); err != nil {
+		return nil, "", err
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", multipart.FileContentDisposition("file", filename))
+	header.Set("Content-Type", fileContentType(filename))
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, "", err
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf, w.FormDataContentType(), nil
+}
+
+func fileContentType(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".txt":
+		return "text/plain"
+	case ".md", ".markdown":
+		return "text/markdown"
+	}
+	return "application/octet-stream"
+}
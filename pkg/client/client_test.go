@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func writeData(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"success": true, "data": data})
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"success": false,
+		"error":   map[string]string{"code": code, "message": message},
+	})
+}
+
+func TestSubmitWork(t *testing.T) {
+	assignmentID, studentID, workID := uuid.New(), uuid.New(), uuid.New()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/works", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		assert.Equal(t, assignmentID.String(), r.FormValue("assignment_id"))
+		assert.Equal(t, studentID.String(), r.FormValue("student_id"))
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		content, _ := io.ReadAll(file)
+		assert.Equal(t, "essay.md", header.Filename)
+		assert.Equal(t, "text/markdown", header.Header.Get("Content-Type"))
+		assert.Equal(t, "# Essay", string(content))
+
+		writeData(w, http.StatusAccepted, map[string]any{
+			"work_id":          workID,
+			"submitted_at":     "2026-01-02T10:00:00Z",
+			"plagiarism_check": map[string]any{"is_plagiarized": true, "score": 0.93, "status": StatusFlagged},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithToken("token"))
+	sub, err := c.SubmitWork(context.Background(), assignmentID, studentID, strings.NewReader("# Essay"), "essay.md")
+	require.NoError(t, err)
+	assert.Equal(t, workID, sub.WorkID)
+	assert.True(t, sub.Plagiarism.IsPlagiarized)
+	assert.Equal(t, 0.93, sub.Plagiarism.Score)
+	assert.Equal(t, StatusFlagged, sub.Plagiarism.Status)
+}
+
+func TestTypedErrors(t *testing.T) {
+	cases := []struct {
+		status int
+		code   string
+		want   error
+	}{
+		{http.StatusBadRequest, CodeValidation, ErrValidation},
+		{http.StatusNotFound, CodeNotFound, ErrNotFound},
+		{http.StatusRequestEntityTooLarge, CodeFileTooLarge, ErrFileTooLarge},
+		{http.StatusUnsupportedMediaType, CodeUnsupportedFormat, ErrUnsupportedFormat},
+		{http.StatusForbidden, CodeForbidden, ErrForbidden},
+		{http.StatusInternalServerError, CodeInternal, ErrInternal},
+	}
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeError(w, tc.status, tc.code, "message")
+			}))
+			defer srv.Close()
+
+			_, err := New(srv.URL, WithRetry(fastRetry)).GetReport(context.Background(), uuid.New())
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tc.want)
+
+			var apiErr *Error
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tc.status, apiErr.StatusCode)
+			assert.Equal(t, tc.code, apiErr.Code)
+			assert.Equal(t, "message", apiErr.Message)
+		})
+	}
+}
+
+func TestRetriesUnavailable(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "key", r.Header.Get("X-API-Key"))
+		assert.Equal(t, "confirmed", r.URL.Query().Get("review_state"))
+		writeData(w, http.StatusOK, []map[string]any{
+			{"work_id": uuid.New(), "status": StatusFlagged, "similarity_score": 0.9, "review": map[string]any{"state": "confirmed"}},
+		})
+	}))
+	defer srv.Close()
+
+	reports, err := New(srv.URL, WithAPIKey("key"), WithRetry(fastRetry)).
+		ListAssignmentReports(context.Background(), uuid.New(), ReviewConfirmed)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+	require.Len(t, reports, 1)
+	assert.Equal(t, ReviewConfirmed, reports[0].Review.State)
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, WithRetry(fastRetry)).GetReport(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, int32(fastRetry.MaxAttempts), calls.Load())
+}
+
+func TestSubmitWorkIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, WithRetry(fastRetry)).
+		SubmitWork(context.Background(), uuid.New(), uuid.New(), strings.NewReader("text"), "a.txt")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "повтор мог бы сдать работу дважды")
+}
+
+func TestContextCancelStopsRetries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	slow := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Minute, MaxDelay: time.Minute}
+
+	start := time.Now()
+	_, err := New(srv.URL, WithRetry(slow)).GetReport(ctx, uuid.New())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestWordCloudReturnsRawImage(t *testing.T) {
+	workID := uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/works/"+workID.String()+"/wordcloud", r.URL.Path)
+		assert.Equal(t, FormatSVG, r.URL.Query().Get("format"))
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write([]byte("<svg/>"))
+	}))
+	defer srv.Close()
+
+	image, err := New(srv.URL).WordCloud(context.Background(), workID, FormatSVG)
+	require.NoError(t, err)
+	assert.Equal(t, "<svg/>", string(image))
+}
+
+func TestGatewayStringError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"success":false,"error":"Storage service unavailable"}`))
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, WithRetry(RetryPolicy{MaxAttempts: 1})).GetReport(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, ErrUnavailable)
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "Storage service unavailable", apiErr.Message)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Коды ошибок публичного API (поле error.code ответа).
+const (
+	CodeValidation        = "VALIDATION_ERROR"
+	CodeUnauthorized      = "UNAUTHORIZED"
+	CodeForbidden         = "FORBIDDEN"
+	CodeNotFound          = "NOT_FOUND"
+	CodeFileTooLarge      = "FILE_TOO_LARGE"
+	CodeUnsupportedFormat = "UNSUPPORTED_FORMAT"
+	CodeConflict          = "CONFLICT"
+	CodeInternal          = "INTERNAL_ERROR"
+)
+
+// Ошибки для errors.Is: *Error разворачивается в ту, что соответствует его коду.
+var (
+	ErrValidation        = errors.New("validation error")
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrForbidden         = errors.New("forbidden")
+	ErrNotFound          = errors.New("not found")
+	ErrFileTooLarge      = errors.New("file too large")
+	ErrUnsupportedFormat = errors.New("unsupported format")
+	ErrConflict          = errors.New("conflict")
+	ErrInternal          = errors.New("internal error")
+	// ErrUnavailable — сервис не ответил или ответил 502/503/504 и после всех повторов.
+	ErrUnavailable = errors.New("service unavailable")
+)
+
+var codeErrors = map[string]error{
+	CodeValidation:        ErrValidation,
+	CodeUnauthorized:      ErrUnauthorized,
+	CodeForbidden:         ErrForbidden,
+	CodeNotFound:          ErrNotFound,
+	CodeFileTooLarge:      ErrFileTooLarge,
+	CodeUnsupportedFormat: ErrUnsupportedFormat,
+	CodeConflict:          ErrConflict,
+	CodeInternal:          ErrInternal,
+}
+
+// Error — ошибка, которую вернул API: HTTP-статус и содержимое поля error ответа.
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("antiplague: %d %s: %s", e.StatusCode, e.Code, e.Message)
+	if e.Details != "" {
+		msg += " (" + e.Details + ")"
+	}
+	return msg
+}
+
+// Unwrap возвращает ошибку-признак по коду. Ответ без известного кода (например, от
+// прокси) сопоставляется по статусу.
+func (e *Error) Unwrap() error {
+	if err, ok := codeErrors[e.Code]; ok {
+		return err
+	}
+	switch {
+	case retryableStatus(e.StatusCode):
+		return ErrUnavailable
+	case e.StatusCode >= 500:
+		return ErrInternal
+	}
+	return nil
+}
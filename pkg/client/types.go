@@ -0,0 +1,167 @@
+package client
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы проверки в отчете.
+const (
+	StatusPending = "pending"
+	StatusClean   = "clean"
+	StatusFlagged = "flagged"
+	// StatusInsufficientContent — в работе слишком мало слов для сравнения, оценки нет.
+	StatusInsufficientContent = "insufficient_content"
+)
+
+// Состояния проверки отчета преподавателем — фильтр ListAssignmentReports.
+const (
+	ReviewUnreviewed      = "unreviewed"
+	ReviewAssigned        = "assigned"
+	ReviewConfirmed       = "confirmed"
+	ReviewDismissed       = "dismissed"
+	ReviewNeedsDiscussion = "needs_discussion"
+)
+
+// Форматы облака слов.
+const (
+	FormatSVG = "svg"
+	FormatPNG = "png"
+)
+
+// Submission — результат загрузки работы вместе с мгновенной проверкой.
+type Submission struct {
+	WorkID      uuid.UUID       `json:"work_id"`
+	SubmittedAt time.Time       `json:"submitted_at"`
+	Plagiarism  PlagiarismCheck `json:"plagiarism_check"`
+}
+
+type PlagiarismCheck struct {
+	IsPlagiarized bool    `json:"is_plagiarized"`
+	Score         float64 `json:"score"`
+	Status        string  `json:"status"`
+}
+
+// Report — отчет по работе. Автор работы получает только итог: совпавшая работа,
+// детали и решение преподавателя в его отчете не заполнены.
+type Report struct {
+	WorkID          uuid.UUID  `json:"work_id"`
+	StudentID       *uuid.UUID `json:"student_id,omitempty"`
+	SubmittedAt     *time.Time `json:"submitted_at,omitempty"`
+	Status          string     `json:"status"`
+	IsPlagiarized   bool       `json:"is_plagiarized"`
+	SimilarityScore float64    `json:"similarity_score"`
+	MatchedWorkID   *uuid.UUID `json:"matched_work_id,omitempty"`
+	// MatchRelation — сдана ли лучшая совпавшая работа раньше (RelationEarlier) или позже
+	// (RelationLater); FlaggedByLaterOnly — плагиат найден только в более поздних работах.
+	MatchRelation      string        `json:"match_relation,omitempty"`
+	FlaggedByLaterOnly bool          `json:"flagged_by_later_only"`
+	Matches            []Match       `json:"matches,omitempty"`
+	CreatedAt          *time.Time    `json:"created_at,omitempty"`
+	Details            ReportDetails `json:"details"`
+	// Decision — уровень по правилам политики и сработавшие правила; Explanation — то же
+	// одной строкой.
+	Decision    *Decision `json:"decision,omitempty"`
+	Explanation string    `json:"explanation,omitempty"`
+	// Revision — номер ревизии отчета; Stale — отчет построен не с текущей политикой
+	// анализа и будет пересчитан при повторном анализе.
+	Revision int     `json:"revision,omitempty"`
+	Policy   *Policy `json:"policy,omitempty"`
+	Stale    bool    `json:"stale"`
+	Review   *Review `json:"review,omitempty"`
+}
+
+const (
+	RelationEarlier = "earlier"
+	RelationLater   = "later"
+)
+
+// Match — совпавшая работа и ее оценка; Containment — доля шинглов работы, найденных в совпавшей.
+type Match struct {
+	WorkID      uuid.UUID `json:"work_id"`
+	Score       float64   `json:"score"`
+	Containment float64   `json:"containment,omitempty"`
+	Relation    string    `json:"relation,omitempty"`
+}
+
+// Уровни решения по правилам политики.
+const (
+	SeverityNone = "none"
+	SeverityInfo = "info"
+	SeverityWarn = "warn"
+	SeverityFlag = "flag"
+)
+
+// Decision — итог правил политики: Fired — сработавшие правила, Suppressed — отмененные исключением.
+type Decision struct {
+	Severity   string       `json:"severity"`
+	Fired      []RuleResult `json:"fired,omitempty"`
+	Suppressed []RuleResult `json:"suppressed,omitempty"`
+}
+
+type RuleResult struct {
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"`
+	Explanation string `json:"explanation"`
+}
+
+// Policy — параметры алгоритма, порог и правила решения, с которыми построен отчет.
+type Policy struct {
+	Parameters Parameters `json:"parameters"`
+	Threshold  float64    `json:"threshold"`
+	Rules      []Rule     `json:"rules,omitempty"`
+}
+
+// Rule срабатывает, если выполнено хотя бы одно условие When и ни одно из Unless.
+type Rule struct {
+	Name     string      `json:"name"`
+	Severity string      `json:"severity"`
+	When     []Condition `json:"when"`
+	Unless   []Condition `json:"unless,omitempty"`
+}
+
+type Condition struct {
+	Metric string  `json:"metric"`
+	Op     string  `json:"op"`
+	Value  float64 `json:"value"`
+}
+
+type Parameters struct {
+	Algorithm           string `json:"algorithm"`
+	Version             string `json:"version"`
+	ShingleLen          int    `json:"shingle_len"`
+	ExcludeQuotes       bool   `json:"exclude_quotes"`
+	ExcludeBibliography bool   `json:"exclude_bibliography"`
+	MinTokens           int    `json:"min_tokens,omitempty"`
+	MinPassageWords     int    `json:"min_passage_words,omitempty"`
+}
+
+type ReportDetails struct {
+	Algorithm      string          `json:"algorithm"`
+	MatchedTokens  int             `json:"matched_tokens"`
+	TotalTokens    int             `json:"total_tokens"`
+	Coverage       float64         `json:"coverage"`
+	ExcludedRanges []ExcludedRange `json:"excluded_ranges,omitempty"`
+	// InsufficientContent: работа слишком короткая и не оценивалась.
+	InsufficientContent bool `json:"insufficient_content,omitempty"`
+}
+
+// ExcludedRange — фрагмент текста (цитата, список литературы), не участвовавший в сравнении.
+type ExcludedRange struct {
+	Kind  string `json:"kind"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+// Review — решение преподавателя по отчету; автоматический результат оно не меняет.
+type Review struct {
+	State              string     `json:"state"`
+	Verdict            string     `json:"verdict,omitempty"`
+	AssigneeID         *uuid.UUID `json:"assignee_id,omitempty"`
+	VerdictBy          *uuid.UUID `json:"verdict_by,omitempty"`
+	Comment            string     `json:"comment,omitempty"`
+	DecidedAt          *time.Time `json:"decided_at,omitempty"`
+	OverridesAutomatic bool       `json:"overrides_automatic"`
+}
@@ -21,11 +21,32 @@ type Config struct {
 
 	SimilarityThreshold    float64
 	MinTokensForComparison int
+	MinPassageWords        int
+	ExcludeQuotes          bool
+	ExcludeBibliography    bool
+	// DecisionRules — правила решения по отчету, JSON-массив; пусто — плагиат выше порога.
+	DecisionRules string
+
+	AppealFilingDays int
+	AppealReviewDays int
+
+	JWTSecret        string
+	JWTPublicKeyFile string
+	JWTIssuer        string
+
+	ServiceKeyID       string
+	ServiceKeySecret   string
+	TrustedServiceKeys string
 }
 
 func LoadConfig() Config {
 	threshold, _ := strconv.ParseFloat(getEnv("SIMILARITY_THRESHOLD", "0.85"), 64)
 	minTokens, _ := strconv.Atoi(getEnv("MIN_TOKENS_FOR_COMPARISON", "50"))
+	minPassageWords, _ := strconv.Atoi(getEnv("MIN_PASSAGE_WORDS", "5"))
+	excludeQuotes, _ := strconv.ParseBool(getEnv("EXCLUDE_QUOTES", "true"))
+	excludeBibliography, _ := strconv.ParseBool(getEnv("EXCLUDE_BIBLIOGRAPHY", "true"))
+	appealFilingDays, _ := strconv.Atoi(getEnv("APPEAL_FILING_DAYS", "14"))
+	appealReviewDays, _ := strconv.Atoi(getEnv("APPEAL_REVIEW_DAYS", "14"))
 
 	return Config{
 		ServerHost: getEnv("SERVER_HOST", "0.0.0.0"),
@@ -43,6 +64,21 @@ func LoadConfig() Config {
 
 		SimilarityThreshold:    threshold,
 		MinTokensForComparison: minTokens,
+		MinPassageWords:        minPassageWords,
+		ExcludeQuotes:          excludeQuotes,
+		ExcludeBibliography:    excludeBibliography,
+		DecisionRules:          getEnv("DECISION_RULES", ""),
+
+		AppealFilingDays: appealFilingDays,
+		AppealReviewDays: appealReviewDays,
+
+		JWTSecret:        getEnv("JWT_SECRET", ""),
+		JWTPublicKeyFile: getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		JWTIssuer:        getEnv("JWT_ISSUER", ""),
+
+		ServiceKeyID:       getEnv("SERVICE_KEY_ID", ""),
+		ServiceKeySecret:   getEnv("SERVICE_KEY_SECRET", ""),
+		TrustedServiceKeys: getEnv("TRUSTED_SERVICE_KEYS", ""),
 	}
 }
 
@@ -1,97 +1,106 @@
 package integrationpackage
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-	"mime/multipart"
+	"context"
 	"net/http"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/domain/auth"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/internal/infrastructure/jwt"
+	"github.com/DanyKaystery/HSE-KPO-ANTIPLAGUE/pkg/client"
 )
 
-const baseURL = "http://localhost:9090/api/v1"
+const serverURL = "http://localhost:9090"
+
+// essay длиннее MIN_TOKENS_FOR_COMPARISON: более короткие работы не сравниваются.
+const essay = `Plagiarism detection compares a new submission with every earlier work of the same
+assignment. The text is normalized, split into overlapping word shingles and the share of shared
+shingles becomes the similarity score. Quotations and the bibliography are excluded because
+students legitimately repeat them. Passages shorter than a few words are ignored as common
+phrases, and works that are too short to compare are reported as having insufficient content
+instead of receiving a meaningless score of zero or one.`
+
+// devJWTSecret совпадает со значением по умолчанию в docker-compose.yml.
+const devJWTSecret = "antiplague-dev-secret"
+
+func bearer(t *testing.T, userID string, role auth.Role, assignments ...string) string {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = devJWTSecret
+	}
+	token, err := jwt.NewHMACSigner([]byte(secret)).Sign(jwt.Claims{
+		Subject:     userID,
+		Role:        string(role),
+		Assignments: assignments,
+		ExpiresAt:   time.Now().Add(time.Hour).Unix(),
+	})
+	assert.NoError(t, err)
+	return token
+}
 
 func TestSubmitWorkFlow(t *testing.T) {
-	resp, err := http.Get("http://localhost:9090/health")
+	resp, err := http.Get(serverURL + "/health")
 	if err != nil {
 		t.Skip("Server is not running, skipping integration test")
 	}
 	defer resp.Body.Close()
 
-	assignmentID := uuid.New().String()
-	student1 := uuid.New().String()
-	student2 := uuid.New().String()
+	assignmentID := uuid.New()
+	student1 := uuid.New()
+	student2 := uuid.New()
 
-	work1ID := uploadWork(t, assignmentID, student1, "Unique content for integration test purpose.")
-	assert.NotEmpty(t, work1ID)
+	work1ID := uploadWork(t, assignmentID, student1, essay)
+	assert.NotEqual(t, uuid.Nil, work1ID)
 
-	work2ID := uploadWork(t, assignmentID, student2, "Unique content for integration test purpose.")
-	assert.NotEmpty(t, work2ID)
+	work2ID := uploadWork(t, assignmentID, student2, essay)
+	assert.NotEqual(t, uuid.Nil, work2ID)
 
-	checkReport(t, work2ID, true)
+	checkReport(t, assignmentID, work2ID, true)
 }
 
-func uploadWork(t *testing.T, assignmentID, studentID, content string) string {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	writer.WriteField("assignment_id", assignmentID)
-	writer.WriteField("student_id", studentID)
-
-	part, err := writer.CreateFormFile("file", "test.txt")
-	assert.NoError(t, err)
-
-	part.Write([]byte(content))
-
-	writer.Close()
-
-	req, err := http.NewRequest("POST", baseURL+"/works", body)
-	assert.NoError(t, err)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	assert.NoError(t, err)
-	defer resp.Body.Close()
-
-	respBody, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusAccepted {
-		t.Fatalf("Expected 202 Accepted, got %d. Body: %s", resp.StatusCode, string(respBody))
-	}
-
-	var result struct {
-		Data struct {
-			WorkID string `json:"work_id"`
-		} `json:"data"`
+// TestSubmitShortWork: работа короче MIN_TOKENS_FOR_COMPARISON (по умолчанию 50 слов)
+// не сравнивается, даже если совпадает с другой дословно.
+func TestSubmitShortWork(t *testing.T) {
+	resp, err := http.Get(serverURL + "/health")
+	if err != nil {
+		t.Skip("Server is not running, skipping integration test")
 	}
-	err = json.Unmarshal(respBody, &result)
-	assert.NoError(t, err)
-
-	return result.Data.WorkID
-}
-
-func checkReport(t *testing.T, workID string, expectPlagiarism bool) {
-	resp, err := http.Get(baseURL + "/works/" + workID + "/reports")
-	assert.NoError(t, err)
 	defer resp.Body.Close()
 
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assignmentID := uuid.New()
+	const short = "Unique content for integration test purpose."
+	uploadWork(t, assignmentID, uuid.New(), short)
+	workID := uploadWork(t, assignmentID, uuid.New(), short)
+
+	token := bearer(t, uuid.New().String(), auth.RoleTeacher, assignmentID.String())
+	report, err := client.New(serverURL, client.WithToken(token)).GetReport(context.Background(), workID)
+	require.NoError(t, err)
+	assert.Equal(t, client.StatusInsufficientContent, report.Status)
+	assert.True(t, report.Details.InsufficientContent)
+	assert.False(t, report.IsPlagiarized)
+	assert.Zero(t, report.SimilarityScore)
+}
 
-	var result struct {
-		Data struct {
-			IsPlagiarized bool    `json:"is_plagiarized"`
-			Score         float64 `json:"similarity_score"`
-		} `json:"data"`
-	}
+func uploadWork(t *testing.T, assignmentID, studentID uuid.UUID, content string) uuid.UUID {
+	c := client.New(serverURL, client.WithToken(bearer(t, studentID.String(), auth.RoleStudent)))
+	sub, err := c.SubmitWork(context.Background(), assignmentID, studentID, strings.NewReader(content), "test.txt")
+	require.NoError(t, err)
+	return sub.WorkID
+}
 
-	body, _ := io.ReadAll(resp.Body)
-	json.Unmarshal(body, &result)
+func checkReport(t *testing.T, assignmentID, workID uuid.UUID, expectPlagiarism bool) {
+	token := bearer(t, uuid.New().String(), auth.RoleTeacher, assignmentID.String())
+	report, err := client.New(serverURL, client.WithToken(token)).GetReport(context.Background(), workID)
+	require.NoError(t, err)
 
 	if expectPlagiarism {
-		assert.True(t, result.Data.Score > 0.9, "Expected high similarity score")
+		assert.True(t, report.SimilarityScore > 0.9, "Expected high similarity score")
 	}
 }